@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
@@ -11,15 +13,40 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server  ServerConfig                 `mapstructure:"server"`
-	Redis   RedisConfig                  `mapstructure:"redis"`
-	Servers map[string]ServerOAuthConfig `mapstructure:"servers"`
+	Server       ServerConfig                 `mapstructure:"server"`
+	Redis        RedisConfig                  `mapstructure:"redis"`
+	Servers      map[string]ServerOAuthConfig `mapstructure:"servers"`
+	Warmup       WarmupConfig                 `mapstructure:"warmup"`
+	TokenRefresh TokenRefreshConfig           `mapstructure:"token_refresh"`
+	Cleanup      CleanupConfig                `mapstructure:"cleanup"`
+	Maintenance  MaintenanceConfig            `mapstructure:"maintenance"`
+	RateLimit    RateLimitConfig              `mapstructure:"rate_limit"`
+	CORS         CORSConfig                   `mapstructure:"cors"`
+	Timeouts     TimeoutsConfig               `mapstructure:"timeouts"`
+	TokenTTL     time.Duration                `mapstructure:"token_ttl"` // how long tokens are stored, 0 derives it from the token's expiry
+
+	ShareVerification ShareVerificationConfig `mapstructure:"share_verification"`
+	Scheduler         SchedulerConfig         `mapstructure:"scheduler"`
+	StatsCache        StatsCacheConfig        `mapstructure:"stats_cache"`
+	UserInfoCache     UserInfoCacheConfig     `mapstructure:"user_info_cache"`
+	Idempotency       IdempotencyConfig       `mapstructure:"idempotency"`
+	Media             MediaConfig             `mapstructure:"media"`
+	Sanitization      SanitizationConfig      `mapstructure:"sanitization"`
+	Retry             RetryConfig             `mapstructure:"retry"`
+	CircuitBreaker    CircuitBreakerConfig    `mapstructure:"circuit_breaker"`
+	PlatformRateLimit PlatformRateLimitConfig `mapstructure:"platform_rate_limit"`
+	Tracing           TracingConfig           `mapstructure:"tracing"`
+	Sandbox           SandboxConfig           `mapstructure:"sandbox"`
+	TLS               TLSConfig               `mapstructure:"tls"`
+	Log               LogConfig               `mapstructure:"log"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port    string `mapstructure:"port"`
-	BaseURL string `mapstructure:"base_url"`
+	Port         string `mapstructure:"port"`
+	BaseURL      string `mapstructure:"base_url"`
+	AdminToken   string `mapstructure:"admin_token"`    // admin endpoints are disabled when empty
+	MaxBodyBytes int64  `mapstructure:"max_body_bytes"` // largest request body accepted
 }
 
 // RedisConfig holds Redis connection configuration
@@ -29,11 +56,173 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// WarmupConfig holds startup connection prewarming configuration
+type WarmupConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// TokenRefreshConfig holds background token refresh configuration
+type TokenRefreshConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"` // how often stored tokens are scanned
+	Window   time.Duration `mapstructure:"window"`   // tokens expiring within this window are refreshed
+}
+
+// CleanupConfig holds the periodic purge of expired storage entries
+type CleanupConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"` // how often expired entries are purged
+}
+
+// MaintenanceConfig holds maintenance mode configuration
+type MaintenanceConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // block write endpoints on startup
+	Message string `mapstructure:"message"` // message returned by blocked write endpoints
+}
+
+// RateLimitConfig holds per-user request rate limiting configuration
+type RateLimitConfig struct {
+	Enabled           bool                     `mapstructure:"enabled"`
+	RequestsPerMinute int                      `mapstructure:"requests_per_minute"` // sustained request rate per user
+	Burst             int                      `mapstructure:"burst"`               // requests allowed in a burst before limiting
+	Servers           map[string]RateLimitTier `mapstructure:"servers"`             // per-server overrides of the default limit
+}
+
+// RateLimitTier holds the request rate limit of a server's users
+type RateLimitTier struct {
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	Burst             int `mapstructure:"burst"`
+}
+
+// CORSConfig holds the browser origins allowed to call the API
+type CORSConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	AllowedOrigins []string      `mapstructure:"allowed_origins"` // origins like https://app.example.com, * allows any origin outside production
+	MaxAge         time.Duration `mapstructure:"max_age"`         // how long browsers cache preflight responses
+}
+
+// TimeoutsConfig holds the deadlines of API operations, reported in timeout error responses
+type TimeoutsConfig struct {
+	Share      time.Duration `mapstructure:"share"`       // share and delete-post
+	BatchShare time.Duration `mapstructure:"batch_share"` // batch-share across all platforms
+	Read       time.Duration `mapstructure:"read"`        // stats, post status and recent posts
+	BatchRead  time.Duration `mapstructure:"batch_read"`  // batch recent posts across all platforms
+}
+
+// ShareVerificationConfig holds configuration of the post-share read-back check
+type ShareVerificationConfig struct {
+	Enabled bool `mapstructure:"enabled"` // allow share requests to set verify_after_share
+}
+
+// SchedulerConfig holds configuration of the scheduled share worker
+type SchedulerConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`  // accept scheduled_at and publish due posts
+	Interval time.Duration `mapstructure:"interval"` // how often due posts are picked up
+}
+
+// StatsCacheConfig holds configuration of the cache in front of platform stats lookups
+type StatsCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"` // how long fetched stats are served from the cache
+}
+
+// UserInfoCacheConfig holds configuration of the cache in front of platform user info lookups
+type UserInfoCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"` // how long fetched user info is served from the cache
+}
+
+// IdempotencyConfig holds configuration of Idempotency-Key handling on share requests
+type IdempotencyConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"` // how long the response of a key is replayed to retries
+}
+
+// MediaConfig holds limits of media downloaded from media_url before uploading it to a provider
+type MediaConfig struct {
+	BaseURL   string           `mapstructure:"base_url"`    // resolves relative media URLs such as /uploads/a.jpg, which are rejected when empty
+	MaxSizeMB map[string]int64 `mapstructure:"max_size_mb"` // per-provider download limit, overriding the platform's default
+}
+
+// MediaMaxSize returns the configured download limit of a provider's media in bytes, or 0 for
+// the platform's default
+func (c *Config) MediaMaxSize(provider string) int64 {
+	return c.Media.MaxSizeMB[provider] * 1024 * 1024
+}
+
+// SanitizationConfig holds configuration of the invisible character cleanup applied before posting
+type SanitizationConfig struct {
+	Enabled   bool `mapstructure:"enabled"`
+	ZeroWidth bool `mapstructure:"zero_width"` // strip zero-width spaces, word joiners and BOMs
+	Control   bool `mapstructure:"control"`    // strip control and bidi override characters, keeping newlines and tabs
+}
+
+// RetryConfig holds the retry policy of idempotent upstream API calls such as stats and user info
+type RetryConfig struct {
+	MaxRetries int           `mapstructure:"max_retries"` // retries after the first attempt, 0 disables retrying
+	BaseDelay  time.Duration `mapstructure:"base_delay"`  // wait before the first retry, doubled on each further one
+	MaxDelay   time.Duration `mapstructure:"max_delay"`   // longest single wait, a longer Retry-After isn't retried
+}
+
+// CircuitBreakerConfig holds the per-provider circuit breaker of outbound platform API calls
+type CircuitBreakerConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	FailureThreshold int           `mapstructure:"failure_threshold"` // consecutive failures that open the breaker
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`      // how long an open breaker fails fast before letting a probe through
+}
+
+// PlatformRateLimitConfig holds how the rate limit windows platforms report are honored
+type PlatformRateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"` // reject calls to an exhausted window until it resets instead of sending them
+}
+
+// TracingConfig holds the OpenTelemetry tracing of requests
+type TracingConfig struct {
+	Endpoint    string `mapstructure:"endpoint"`     // OTLP/HTTP collector url, tracing is disabled when empty
+	ServiceName string `mapstructure:"service_name"` // service name spans are reported under
+}
+
+// LogConfig holds the application log output
+type LogConfig struct {
+	Level  string `mapstructure:"level"`  // minimum level logged: debug, info, warn or error
+	Format string `mapstructure:"format"` // json, or text for reading locally
+}
+
+// TLSConfig holds the TLS settings of outbound provider, media and callback requests
+type TLSConfig struct {
+	MinVersion string `mapstructure:"min_version"` // "1.2" or "1.3"
+}
+
+// SandboxConfig holds configuration of sandbox mode, in which platforms are replaced with stubs
+// returning fake media IDs and stats instead of calling the providers
+type SandboxConfig struct {
+	Enabled   bool     `mapstructure:"enabled"`
+	Providers []string `mapstructure:"providers"` // providers to sandbox, all of them when empty
+}
+
+// IsSandboxed reports whether a provider is served by a sandbox stub
+func (s SandboxConfig) IsSandboxed(provider string) bool {
+	if !s.Enabled {
+		return false
+	}
+	return len(s.Providers) == 0 || slices.Contains(s.Providers, provider)
+}
+
 // ProviderConfig holds configuration for a single OAuth provider
 type ProviderConfig struct {
-	ClientID     string   `mapstructure:"client_id"`
-	ClientSecret string   `mapstructure:"client_secret"`
-	Scopes       []string `mapstructure:"scopes"`
+	ClientID     string        `mapstructure:"client_id"`
+	ClientSecret string        `mapstructure:"client_secret"`
+	Scopes       []string      `mapstructure:"scopes"`
+	APIHost      string        `mapstructure:"api_host"`     // optional regional/alternate API host
+	TokenTTL     time.Duration `mapstructure:"token_ttl"`    // overrides the global token TTL for this provider
+	InstanceURL  string        `mapstructure:"instance_url"` // instance of self-hosted providers such as mastodon
+	UserAgent    string        `mapstructure:"user_agent"`   // User-Agent sent to the provider, required by reddit
+	BotToken     string        `mapstructure:"bot_token"`    // token of the bot providers such as discord post as, used instead of user tokens
+	Timeout      time.Duration `mapstructure:"timeout"`      // overrides the share and read timeouts and limits each API request
+	UsePKCE      bool          `mapstructure:"use_pkce"`     // send a PKCE challenge when authorizing, always on for PKCERequiredProviders
+
+	RequiredScopes map[string][]string `mapstructure:"required_scopes"` // overrides ProviderRequiredScopes per operation
 }
 
 // ServerOAuthConfig holds OAuth configuration for a specific server
@@ -43,6 +232,19 @@ type ServerOAuthConfig struct {
 	Facebook  ProviderConfig `mapstructure:"facebook"`
 	TikTok    ProviderConfig `mapstructure:"tiktok"`
 	Instagram ProviderConfig `mapstructure:"instagram"`
+	Pinterest ProviderConfig `mapstructure:"pinterest"`
+	Mastodon  ProviderConfig `mapstructure:"mastodon"`
+	Reddit    ProviderConfig `mapstructure:"reddit"`
+	Discord   ProviderConfig `mapstructure:"discord"`
+	Threads   ProviderConfig `mapstructure:"threads"`
+
+	CallbackRedirect CallbackRedirectConfig `mapstructure:"callback_redirect"`
+}
+
+// CallbackRedirectConfig holds where browsers are sent after the OAuth callback of a server completes
+type CallbackRedirectConfig struct {
+	SuccessURL string `mapstructure:"success_url"` // redirected to with status=success and provider
+	FailureURL string `mapstructure:"failure_url"` // redirected to with status=failed, provider and error
 }
 
 // Load loads configuration from environment variables and files
@@ -113,15 +315,119 @@ func overrideWithEnvVars(config *Config) {
 		// Note: viper will handle the string to int conversion
 		config.Redis.DB = 0 // This will be overridden by viper if env var is set
 	}
+	if adminToken := GetEnvWithDefault(EnvAdminToken, ""); adminToken != "" {
+		config.Server.AdminToken = adminToken
+	}
+	config.Warmup.Enabled = GetEnvBool(EnvWarmupEnabled, config.Warmup.Enabled)
+	config.Maintenance.Enabled = GetEnvBool(EnvMaintenanceMode, config.Maintenance.Enabled)
+	config.RateLimit.Enabled = GetEnvBool(EnvRateLimitEnabled, config.RateLimit.Enabled)
+	// CORS_ALLOWED_ORIGINS is a comma separated list of origins and enables CORS
+	if origins := GetEnvWithDefault(EnvCORSAllowedOrigins, ""); origins != "" {
+		config.CORS.Enabled = true
+		config.CORS.AllowedOrigins = nil
+		for _, origin := range strings.Split(origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				config.CORS.AllowedOrigins = append(config.CORS.AllowedOrigins, origin)
+			}
+		}
+	}
+	config.TokenRefresh.Enabled = GetEnvBool(EnvTokenRefreshEnabled, config.TokenRefresh.Enabled)
+	if interval := GetEnvWithDefault(EnvTokenRefreshInterval, ""); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.TokenRefresh.Interval = d
+		}
+	}
+	if GetEnvWithDefault(EnvLogLevel, "") != "" {
+		config.Log.Level = GetLogLevel()
+	}
+	if GetEnvWithDefault(EnvLogFormat, "") != "" {
+		config.Log.Format = GetLogFormat()
+	}
+	// SANDBOX_MODE is true/false, or a comma separated list of the providers to sandbox
+	if sandbox := GetEnvWithDefault(EnvSandboxMode, ""); sandbox != "" {
+		switch strings.ToLower(sandbox) {
+		case "true":
+			config.Sandbox.Enabled = true
+			config.Sandbox.Providers = nil
+		case "false":
+			config.Sandbox.Enabled = false
+		default:
+			config.Sandbox.Enabled = true
+			config.Sandbox.Providers = nil
+			for _, provider := range strings.Split(sandbox, ",") {
+				if provider = strings.TrimSpace(provider); provider != "" {
+					config.Sandbox.Providers = append(config.Sandbox.Providers, provider)
+				}
+			}
+		}
+	}
 }
 
 // setDefaults sets default configuration values
 func setDefaults() {
 	viper.SetDefault("server.port", DefaultPort)
 	viper.SetDefault("server.base_url", DefaultBaseURL)
+	viper.SetDefault("server.max_body_bytes", DefaultMaxBodyBytes)
 	viper.SetDefault("redis.addr", DefaultRedisAddr)
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", DefaultRedisDB)
+	viper.SetDefault("warmup.enabled", false)
+	viper.SetDefault("warmup.timeout", DefaultWarmupTimeout)
+	viper.SetDefault("token_refresh.enabled", true)
+	viper.SetDefault("token_refresh.interval", DefaultTokenRefreshInterval)
+	viper.SetDefault("token_refresh.window", DefaultTokenRefreshWindow)
+
+	viper.SetDefault("cleanup.enabled", true)
+	viper.SetDefault("cleanup.interval", DefaultCleanupInterval)
+	viper.SetDefault("maintenance.enabled", false)
+	viper.SetDefault("maintenance.message", DefaultMaintenanceMessage)
+	viper.SetDefault("rate_limit.enabled", false)
+	viper.SetDefault("rate_limit.requests_per_minute", DefaultRateLimitPerMinute)
+	viper.SetDefault("rate_limit.burst", DefaultRateLimitBurst)
+	viper.SetDefault("cors.enabled", false)
+	viper.SetDefault("cors.max_age", DefaultCORSMaxAge)
+
+	viper.SetDefault("timeouts.share", DefaultShareTimeout)
+	viper.SetDefault("timeouts.batch_share", DefaultBatchShareTimeout)
+	viper.SetDefault("timeouts.read", DefaultReadTimeout)
+	viper.SetDefault("timeouts.batch_read", DefaultBatchReadTimeout)
+
+	viper.SetDefault("share_verification.enabled", true)
+
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.interval", DefaultSchedulerInterval)
+
+	viper.SetDefault("stats_cache.enabled", true)
+	viper.SetDefault("stats_cache.ttl", DefaultStatsCacheTTL)
+	viper.SetDefault("user_info_cache.enabled", true)
+	viper.SetDefault("user_info_cache.ttl", DefaultUserInfoCacheTTL)
+
+	viper.SetDefault("idempotency.enabled", true)
+	viper.SetDefault("idempotency.ttl", DefaultIdempotencyTTL)
+
+	viper.SetDefault("sanitization.enabled", true)
+	viper.SetDefault("sanitization.zero_width", true)
+	viper.SetDefault("sanitization.control", true)
+
+	viper.SetDefault("retry.max_retries", DefaultRetryMaxRetries)
+	viper.SetDefault("retry.base_delay", DefaultRetryBaseDelay)
+	viper.SetDefault("retry.max_delay", DefaultRetryMaxDelay)
+
+	viper.SetDefault("circuit_breaker.enabled", true)
+	viper.SetDefault("circuit_breaker.failure_threshold", DefaultCircuitBreakerFailureThreshold)
+	viper.SetDefault("circuit_breaker.open_timeout", DefaultCircuitBreakerOpenTimeout)
+
+	viper.SetDefault("platform_rate_limit.enabled", true)
+
+	viper.SetDefault("tracing.endpoint", "")
+	viper.SetDefault("tracing.service_name", DefaultTracingServiceName)
+
+	viper.SetDefault("sandbox.enabled", false)
+
+	viper.SetDefault("tls.min_version", DefaultTLSMinVersion)
+
+	viper.SetDefault("log.level", GetLogLevel())
+	viper.SetDefault("log.format", GetLogFormat())
 }
 
 // Validate validates the configuration
@@ -130,6 +436,189 @@ func (c *Config) Validate() error {
 	return validator.ValidateAll()
 }
 
+// providerNames lists the providers a server can configure, in the order of ServerOAuthConfig
+var providerNames = []string{"youtube", "x", "facebook", "tiktok", "instagram", "pinterest", "mastodon", "reddit", "discord", "threads"}
+
+// ConfiguredProviders returns the providers that have credentials configured in at least one server
+func (c *Config) ConfiguredProviders() []string {
+	var providers []string
+	for _, name := range providerNames {
+		for _, serverConfig := range c.Servers {
+			if provider, ok := serverConfig.Provider(name); ok && provider.ClientID != "" {
+				providers = append(providers, name)
+				break
+			}
+		}
+	}
+	return providers
+}
+
+// ConfiguredServers returns the providers with credentials configured on each server
+func (c *Config) ConfiguredServers() map[string][]string {
+	servers := make(map[string][]string, len(c.Servers))
+	for serverName, serverConfig := range c.Servers {
+		providers := []string{}
+		for _, name := range providerNames {
+			if provider, ok := serverConfig.Provider(name); ok && provider.ClientID != "" {
+				providers = append(providers, name)
+			}
+		}
+		servers[serverName] = providers
+	}
+	return servers
+}
+
+// Provider returns the configuration of the named provider
+func (s ServerOAuthConfig) Provider(name string) (ProviderConfig, bool) {
+	switch name {
+	case "youtube":
+		return s.YouTube, true
+	case "x":
+		return s.X, true
+	case "facebook":
+		return s.Facebook, true
+	case "tiktok":
+		return s.TikTok, true
+	case "instagram":
+		return s.Instagram, true
+	case "pinterest":
+		return s.Pinterest, true
+	case "mastodon":
+		return s.Mastodon, true
+	case "reddit":
+		return s.Reddit, true
+	case "discord":
+		return s.Discord, true
+	case "threads":
+		return s.Threads, true
+	default:
+		return ProviderConfig{}, false
+	}
+}
+
+// GetProviderAPIHost returns the API host override for a provider on a server,
+// or an empty string if the default host should be used
+func (c *Config) GetProviderAPIHost(provider, serverName string) string {
+	serverConfig, ok := c.Servers[serverName]
+	if !ok {
+		return ""
+	}
+
+	providerConfig, ok := serverConfig.Provider(provider)
+	if !ok {
+		return ""
+	}
+
+	return providerConfig.APIHost
+}
+
+// GetInstanceURL returns the instance a self-hosted provider is used on for a server,
+// or an empty string for providers with a fixed API host
+func (c *Config) GetInstanceURL(provider, serverName string) string {
+	if provider != "mastodon" {
+		return ""
+	}
+
+	if serverConfig, ok := c.Servers[serverName]; ok && serverConfig.Mastodon.InstanceURL != "" {
+		return strings.TrimSuffix(serverConfig.Mastodon.InstanceURL, "/")
+	}
+	return DefaultMastodonInstance
+}
+
+// GetProviderTimeout returns the timeout configured for a provider on a server, or 0 if none is
+func (c *Config) GetProviderTimeout(provider, serverName string) time.Duration {
+	if serverConfig, ok := c.Servers[serverName]; ok {
+		if providerConfig, ok := serverConfig.Provider(provider); ok {
+			return providerConfig.Timeout
+		}
+	}
+	return 0
+}
+
+// ShareTimeout returns the deadline of sharing to and deleting from a provider: the provider's
+// timeout if configured, else its upload default from ProviderShareTimeouts, else timeouts.share
+func (c *Config) ShareTimeout(provider, serverName string) time.Duration {
+	if timeout := c.GetProviderTimeout(provider, serverName); timeout > 0 {
+		return timeout
+	}
+	if timeout, ok := ProviderShareTimeouts[provider]; ok {
+		return timeout
+	}
+	return c.Timeouts.Share
+}
+
+// ReadTimeout returns the deadline of reading stats, post status and recent posts from a
+// provider: the provider's timeout if configured, else timeouts.read
+func (c *Config) ReadTimeout(provider, serverName string) time.Duration {
+	if timeout := c.GetProviderTimeout(provider, serverName); timeout > 0 {
+		return timeout
+	}
+	return c.Timeouts.Read
+}
+
+// GetUserAgent returns the User-Agent sent to a provider for a server, falling back to
+// DefaultRedditUserAgent for reddit. It returns an empty string to keep the default one.
+func (c *Config) GetUserAgent(provider, serverName string) string {
+	if serverConfig, ok := c.Servers[serverName]; ok {
+		if providerConfig, ok := serverConfig.Provider(provider); ok && providerConfig.UserAgent != "" {
+			return providerConfig.UserAgent
+		}
+	}
+	if provider == "reddit" {
+		return DefaultRedditUserAgent
+	}
+	return ""
+}
+
+// GetBotToken returns the bot token a provider posts with on a server, or an empty string
+// if it uses the user's token
+func (c *Config) GetBotToken(provider, serverName string) string {
+	if serverConfig, ok := c.Servers[serverName]; ok {
+		if providerConfig, ok := serverConfig.Provider(provider); ok {
+			return providerConfig.BotToken
+		}
+	}
+	return ""
+}
+
+// UsesPKCE reports whether authorization with a provider on a server uses PKCE, either because the
+// server enables use_pkce for it or because the provider requires PKCE
+func (c *Config) UsesPKCE(provider, serverName string) bool {
+	if PKCERequiredProviders[provider] {
+		return true
+	}
+	if serverConfig, ok := c.Servers[serverName]; ok {
+		if providerConfig, ok := serverConfig.Provider(provider); ok {
+			return providerConfig.UsePKCE
+		}
+	}
+	return false
+}
+
+// RequiredScopes returns the scopes a provider operation needs on a server, preferring the
+// server's required_scopes override over ProviderRequiredScopes
+func (c *Config) RequiredScopes(provider, serverName, operation string) []string {
+	if serverConfig, ok := c.Servers[serverName]; ok {
+		if providerConfig, ok := serverConfig.Provider(provider); ok {
+			if scopes, ok := providerConfig.RequiredScopes[operation]; ok {
+				return scopes
+			}
+		}
+	}
+	return ProviderRequiredScopes[provider][operation]
+}
+
+// TokenTTLFor returns the configured token TTL for a provider on a server, preferring the
+// provider override over the global setting. It returns 0 if neither is set.
+func (c *Config) TokenTTLFor(provider, serverName string) time.Duration {
+	if serverConfig, ok := c.Servers[serverName]; ok {
+		if providerConfig, ok := serverConfig.Provider(provider); ok && providerConfig.TokenTTL > 0 {
+			return providerConfig.TokenTTL
+		}
+	}
+	return c.TokenTTL
+}
+
 // GetServerOAuthConfig returns oauth2.Config for the specified provider and server
 func (c *Config) GetServerOAuthConfig(provider, serverName, redirectURI string) (*oauth2.Config, error) {
 	// 从服务器特定配置获取
@@ -191,6 +680,63 @@ func (c *Config) GetServerOAuthConfig(provider, serverName, redirectURI string)
 			},
 			RedirectURL: redirectURI,
 		}, nil
+	case "pinterest":
+		return &oauth2.Config{
+			ClientID:     serverConfig.Pinterest.ClientID,
+			ClientSecret: serverConfig.Pinterest.ClientSecret,
+			Scopes:       serverConfig.Pinterest.Scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  PinterestAuthURL,
+				TokenURL: PinterestTokenURL,
+			},
+			RedirectURL: redirectURI,
+		}, nil
+	case "mastodon":
+		instanceURL := c.GetInstanceURL(provider, serverName)
+		return &oauth2.Config{
+			ClientID:     serverConfig.Mastodon.ClientID,
+			ClientSecret: serverConfig.Mastodon.ClientSecret,
+			Scopes:       serverConfig.Mastodon.Scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  instanceURL + MastodonAuthPath,
+				TokenURL: instanceURL + MastodonTokenPath,
+			},
+			RedirectURL: redirectURI,
+		}, nil
+	case "reddit":
+		return &oauth2.Config{
+			ClientID:     serverConfig.Reddit.ClientID,
+			ClientSecret: serverConfig.Reddit.ClientSecret,
+			Scopes:       serverConfig.Reddit.Scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   RedditAuthURL,
+				TokenURL:  RedditTokenURL,
+				AuthStyle: oauth2.AuthStyleInHeader,
+			},
+			RedirectURL: redirectURI,
+		}, nil
+	case "discord":
+		return &oauth2.Config{
+			ClientID:     serverConfig.Discord.ClientID,
+			ClientSecret: serverConfig.Discord.ClientSecret,
+			Scopes:       serverConfig.Discord.Scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  DiscordAuthURL,
+				TokenURL: DiscordTokenURL,
+			},
+			RedirectURL: redirectURI,
+		}, nil
+	case "threads":
+		return &oauth2.Config{
+			ClientID:     serverConfig.Threads.ClientID,
+			ClientSecret: serverConfig.Threads.ClientSecret,
+			Scopes:       serverConfig.Threads.Scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  ThreadsAuthURL,
+				TokenURL: ThreadsTokenURL,
+			},
+			RedirectURL: redirectURI,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", provider)
 	}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // OAuth provider endpoints
 const (
 	// YouTube OAuth endpoints
@@ -21,12 +23,205 @@ const (
 	// Instagram OAuth endpoints
 	InstagramAuthURL  = "https://api.instagram.com/oauth/authorize"
 	InstagramTokenURL = "https://api.instagram.com/oauth/access_token"
+
+	// Pinterest OAuth endpoints
+	PinterestAuthURL  = "https://www.pinterest.com/oauth/"
+	PinterestTokenURL = "https://api.pinterest.com/v5/oauth/token"
+
+	// Reddit OAuth endpoints
+	RedditAuthURL  = "https://www.reddit.com/api/v1/authorize"
+	RedditTokenURL = "https://www.reddit.com/api/v1/access_token"
+
+	// Discord OAuth endpoints
+	DiscordAuthURL  = "https://discord.com/oauth2/authorize"
+	DiscordTokenURL = "https://discord.com/api/oauth2/token"
+
+	// Threads OAuth endpoints
+	ThreadsAuthURL  = "https://threads.net/oauth/authorize"
+	ThreadsTokenURL = "https://graph.threads.net/oauth/access_token"
+
+	// Mastodon OAuth endpoint paths, every instance hosts its own
+	MastodonAuthPath  = "/oauth/authorize"
+	MastodonTokenPath = "/oauth/token"
+
+	// DefaultMastodonInstance is used for servers that don't configure an instance_url
+	DefaultMastodonInstance = "https://mastodon.social"
 )
 
 // Default configuration values
 const (
-	DefaultPort      = "8080"
-	DefaultBaseURL   = "http://localhost:8080"
-	DefaultRedisAddr = "localhost:6379"
-	DefaultRedisDB   = 0
+	DefaultPort          = "8080"
+	DefaultBaseURL       = "http://localhost:8080"
+	DefaultRedisAddr     = "localhost:6379"
+	DefaultRedisDB       = 0
+	DefaultWarmupTimeout = "10s"
+	DefaultMaxBodyBytes  = 1024 * 1024
+
+	DefaultTokenRefreshInterval = "10m"
+	DefaultTokenRefreshWindow   = "1h"
+
+	DefaultCleanupInterval = "10m"
+
+	DefaultMaintenanceMessage = "service is under maintenance, posting is temporarily disabled"
+
+	DefaultRateLimitPerMinute = 60
+	DefaultRateLimitBurst     = 20
+
+	DefaultCORSMaxAge = "10m"
+
+	DefaultShareTimeout      = "30s"
+	DefaultBatchShareTimeout = "120s"
+	DefaultReadTimeout       = "15s"
+	DefaultBatchReadTimeout  = "30s"
+
+	DefaultSchedulerInterval = "30s"
+
+	DefaultStatsCacheTTL = "60s"
+
+	DefaultUserInfoCacheTTL = "15m"
+
+	DefaultIdempotencyTTL = "24h"
+
+	DefaultRetryMaxRetries = 3
+	DefaultRetryBaseDelay  = "500ms"
+	DefaultRetryMaxDelay   = "10s"
+
+	DefaultCircuitBreakerFailureThreshold = 5
+	DefaultCircuitBreakerOpenTimeout      = "30s"
+
+	DefaultTracingServiceName = "social"
+
+	DefaultTLSMinVersion = "1.2"
+
+	// DefaultRedditUserAgent is sent to Reddit when a server doesn't configure a user_agent,
+	// Reddit throttles or blocks requests with generic user agents
+	DefaultRedditUserAgent = "server:social-share-service:v1.0"
+)
+
+// PKCERequiredProviders lists the providers whose OAuth 2.0 flow rejects authorizations without
+// PKCE, they use it regardless of use_pkce
+var PKCERequiredProviders = map[string]bool{
+	"x": true,
+}
+
+// ProviderAPIHosts lists the API hosts each provider serves. The first entry is
+// the default host used by the platform implementations; an api_host override
+// must be one of the listed hosts.
+var ProviderAPIHosts = map[string][]string{
+	"youtube":   {"www.googleapis.com", "youtube.googleapis.com"},
+	"x":         {"api.x.com", "api.twitter.com"},
+	"facebook":  {"graph.facebook.com", "graph.beta.facebook.com"},
+	"tiktok":    {"open.tiktokapis.com", "open-api.tiktok.com"},
+	"instagram": {"graph.instagram.com", "graph.facebook.com"},
+	"pinterest": {"api.pinterest.com", "api-sandbox.pinterest.com"},
+	"reddit":    {"oauth.reddit.com"},
+}
+
+// ProviderShareTimeouts are the share deadlines of providers that upload media during the share,
+// used instead of timeouts.share unless the provider has its own timeout configured
+var ProviderShareTimeouts = map[string]time.Duration{
+	"youtube": 10 * time.Minute,
+	"tiktok":  5 * time.Minute,
+	"threads": 3 * time.Minute,
+}
+
+// IsKnownAPIHost reports whether host is a known API host for the provider
+func IsKnownAPIHost(provider, host string) bool {
+	for _, known := range ProviderAPIHosts[provider] {
+		if known == host {
+			return true
+		}
+	}
+	return false
+}
+
+// Operations listed in the required scope map
+const (
+	ScopeOperationShare       = "share"
+	ScopeOperationStats       = "stats"
+	ScopeOperationDelete      = "delete"
+	ScopeOperationRecentPosts = "recent_posts"
 )
+
+// scopeOperations are the operations of ProviderRequiredScopes in the order they're reported
+var scopeOperations = []string{ScopeOperationShare, ScopeOperationStats, ScopeOperationDelete, ScopeOperationRecentPosts}
+
+// ProviderBaseScopes lists scopes a provider needs for every operation, such as X's
+// offline.access without which no refresh token is issued and users re-authorize every 2 hours
+var ProviderBaseScopes = map[string][]string{
+	"x": {"offline.access"},
+}
+
+// ProviderImpliedScopes lists scopes that grant others, so configuring the broader scope
+// satisfies the narrower one it covers
+var ProviderImpliedScopes = map[string]map[string][]string{
+	"youtube": {
+		"https://www.googleapis.com/auth/youtube": {
+			"https://www.googleapis.com/auth/youtube.upload",
+			"https://www.googleapis.com/auth/youtube.readonly",
+		},
+		"https://www.googleapis.com/auth/youtube.force-ssl": {
+			"https://www.googleapis.com/auth/youtube",
+			"https://www.googleapis.com/auth/youtube.upload",
+			"https://www.googleapis.com/auth/youtube.readonly",
+		},
+	},
+}
+
+// ProviderRequiredScopes lists the scopes each provider operation needs, reported when the
+// provider rejects a request for a missing scope. A server can override an operation's
+// scopes with required_scopes in its provider config.
+var ProviderRequiredScopes = map[string]map[string][]string{
+	"youtube": {
+		ScopeOperationShare:       {"https://www.googleapis.com/auth/youtube.upload"},
+		ScopeOperationStats:       {"https://www.googleapis.com/auth/youtube.readonly"},
+		ScopeOperationDelete:      {"https://www.googleapis.com/auth/youtube"},
+		ScopeOperationRecentPosts: {"https://www.googleapis.com/auth/youtube.readonly"},
+	},
+	"x": {
+		ScopeOperationShare:       {"tweet.read", "tweet.write", "users.read"},
+		ScopeOperationStats:       {"tweet.read", "users.read"},
+		ScopeOperationDelete:      {"tweet.read", "tweet.write", "users.read"},
+		ScopeOperationRecentPosts: {"tweet.read", "users.read"},
+	},
+	"facebook": {
+		ScopeOperationShare:       {"pages_manage_posts"},
+		ScopeOperationStats:       {"pages_read_engagement"},
+		ScopeOperationDelete:      {"pages_manage_posts"},
+		ScopeOperationRecentPosts: {"pages_read_engagement", "pages_read_user_content"},
+	},
+	"tiktok": {
+		ScopeOperationShare:       {"video.publish"},
+		ScopeOperationStats:       {"video.list"},
+		ScopeOperationRecentPosts: {"video.list"},
+	},
+	"instagram": {
+		ScopeOperationShare:       {"instagram_content_publish", "pages_show_list"},
+		ScopeOperationStats:       {"instagram_manage_insights"},
+		ScopeOperationRecentPosts: {"instagram_basic"},
+	},
+	"pinterest": {
+		ScopeOperationShare:       {"boards:read", "pins:write"},
+		ScopeOperationStats:       {"pins:read"},
+		ScopeOperationDelete:      {"pins:write"},
+		ScopeOperationRecentPosts: {"pins:read"},
+	},
+	"mastodon": {
+		ScopeOperationShare:       {"write:statuses"},
+		ScopeOperationStats:       {"read:statuses"},
+		ScopeOperationDelete:      {"write:statuses"},
+		ScopeOperationRecentPosts: {"read:accounts", "read:statuses"},
+	},
+	"reddit": {
+		ScopeOperationShare:       {"submit"},
+		ScopeOperationStats:       {"read"},
+		ScopeOperationDelete:      {"edit"},
+		ScopeOperationRecentPosts: {"identity", "history"},
+	},
+	"threads": {
+		ScopeOperationShare:       {"threads_basic", "threads_content_publish"},
+		ScopeOperationStats:       {"threads_basic", "threads_manage_insights"},
+		ScopeOperationDelete:      {"threads_basic", "threads_delete"},
+		ScopeOperationRecentPosts: {"threads_basic"},
+	},
+}
@@ -13,6 +13,37 @@ const (
 	EnvRedisPassword = "REDIS_PASSWORD"
 	EnvRedisDB       = "REDIS_DB"
 	EnvGinMode       = "GIN_MODE"
+	EnvWarmupEnabled = "WARMUP_ENABLED"
+	EnvAdminToken    = "ADMIN_TOKEN"
+
+	// EnvMaintenanceMode starts the service with write endpoints blocked
+	EnvMaintenanceMode = "MAINTENANCE_MODE"
+
+	EnvRateLimitEnabled = "RATE_LIMIT_ENABLED"
+
+	// EnvCORSAllowedOrigins enables CORS for a comma separated list of origins
+	EnvCORSAllowedOrigins = "CORS_ALLOWED_ORIGINS"
+
+	EnvTokenRefreshEnabled  = "TOKEN_REFRESH_ENABLED"
+	EnvTokenRefreshInterval = "TOKEN_REFRESH_INTERVAL"
+
+	// EnvStorageBackend selects the storage backend: redis (default), memory or postgres
+	EnvStorageBackend = "STORAGE_BACKEND"
+
+	// EnvPostgresDSN is the connection string of the postgres storage backend
+	EnvPostgresDSN = "POSTGRES_DSN"
+
+	// EnvTokenEncryptionKey is the base64 encoded 32 byte key tokens are encrypted with in postgres
+	EnvTokenEncryptionKey = "TOKEN_ENCRYPTION_KEY"
+
+	// EnvLogLevel sets the minimum log level: debug, info, warn or error
+	EnvLogLevel = "LOG_LEVEL"
+
+	// EnvLogFormat sets the log output format: json or text
+	EnvLogFormat = "LOG_FORMAT"
+
+	// EnvSandboxMode replaces platforms with stubs: true for all providers or a comma separated list of them
+	EnvSandboxMode = "SANDBOX_MODE"
 )
 
 // GetEnvWithDefault returns environment variable value or default if not set
@@ -83,10 +114,25 @@ func GetConfigFile() string {
 	}
 }
 
-// GetLogLevel returns the appropriate log level based on environment
+// GetLogLevel returns the log level set by LOG_LEVEL, or the default of the environment
 func GetLogLevel() string {
+	if level := os.Getenv(EnvLogLevel); level != "" {
+		return strings.ToLower(level)
+	}
 	if IsDevelopment() {
 		return "debug"
 	}
 	return "info"
 }
+
+// GetLogFormat returns the log format set by LOG_FORMAT, or the default of the environment:
+// readable text in development and JSON elsewhere
+func GetLogFormat() string {
+	if format := os.Getenv(EnvLogFormat); format != "" {
+		return strings.ToLower(format)
+	}
+	if IsDevelopment() {
+		return "text"
+	}
+	return "json"
+}
@@ -0,0 +1,31 @@
+package config
+
+import "sync/atomic"
+
+// ConfigProvider returns the current configuration.
+// Components call Get per request instead of keeping a *Config, so a reloaded config takes effect immediately.
+type ConfigProvider interface {
+	Get() *Config
+}
+
+// AtomicProvider is a ConfigProvider whose configuration can be swapped at runtime
+type AtomicProvider struct {
+	current atomic.Pointer[Config]
+}
+
+// NewAtomicProvider creates a provider serving cfg until it is replaced
+func NewAtomicProvider(cfg *Config) *AtomicProvider {
+	p := &AtomicProvider{}
+	p.current.Store(cfg)
+	return p
+}
+
+// Get returns the current configuration
+func (p *AtomicProvider) Get() *Config {
+	return p.current.Load()
+}
+
+// Set replaces the configuration returned by subsequent Get calls
+func (p *AtomicProvider) Set(cfg *Config) {
+	p.current.Store(cfg)
+}
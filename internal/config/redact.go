@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in the redacted configuration
+const redactedValue = "[REDACTED]"
+
+// secretKeys are the configuration keys whose values are never exposed
+var secretKeys = map[string]bool{
+	"client_secret": true,
+	"password":      true,
+	"admin_token":   true,
+	"bot_token":     true,
+}
+
+// Redacted returns the configuration as a map keyed like the config file, with secrets replaced
+// by a placeholder. Unset secrets stay empty so it shows whether they are configured.
+func (c *Config) Redacted() map[string]any {
+	redacted, _ := redactValue(reflect.ValueOf(*c)).(map[string]any)
+	return redacted
+}
+
+// redactValue converts a configuration value to plain maps, slices and scalars, using the
+// mapstructure keys of struct fields and redacting secret fields
+func redactValue(v reflect.Value) any {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if key == "" || key == "-" {
+				continue
+			}
+			if secretKeys[key] && v.Field(i).Kind() == reflect.String {
+				if v.Field(i).String() != "" {
+					fields[key] = redactedValue
+				} else {
+					fields[key] = ""
+				}
+				continue
+			}
+			fields[key] = redactValue(v.Field(i))
+		}
+		return fields
+	case reflect.Map:
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[iter.Key().String()] = redactValue(iter.Value())
+		}
+		return entries
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = redactValue(v.Index(i))
+		}
+		return items
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // ConfigValidator provides configuration validation functionality
@@ -35,6 +36,70 @@ func (v *ConfigValidator) ValidateAll() error {
 		return fmt.Errorf("servers validation failed: %w", err)
 	}
 
+	if err := v.ValidateTokenRefresh(); err != nil {
+		return fmt.Errorf("token refresh validation failed: %w", err)
+	}
+
+	if err := v.ValidateCleanup(); err != nil {
+		return fmt.Errorf("cleanup validation failed: %w", err)
+	}
+
+	if err := v.ValidateRateLimit(); err != nil {
+		return fmt.Errorf("rate limit validation failed: %w", err)
+	}
+
+	if err := v.ValidateCORS(); err != nil {
+		return fmt.Errorf("cors validation failed: %w", err)
+	}
+
+	if err := v.ValidateTimeouts(); err != nil {
+		return fmt.Errorf("timeouts validation failed: %w", err)
+	}
+
+	if err := v.ValidateScheduler(); err != nil {
+		return fmt.Errorf("scheduler validation failed: %w", err)
+	}
+
+	if err := v.ValidateStatsCache(); err != nil {
+		return fmt.Errorf("stats cache validation failed: %w", err)
+	}
+
+	if err := v.ValidateUserInfoCache(); err != nil {
+		return fmt.Errorf("user info cache validation failed: %w", err)
+	}
+
+	if err := v.ValidateIdempotency(); err != nil {
+		return fmt.Errorf("idempotency validation failed: %w", err)
+	}
+
+	if err := v.ValidateMedia(); err != nil {
+		return fmt.Errorf("media validation failed: %w", err)
+	}
+
+	if err := v.ValidateRetry(); err != nil {
+		return fmt.Errorf("retry validation failed: %w", err)
+	}
+
+	if err := v.ValidateCircuitBreaker(); err != nil {
+		return fmt.Errorf("circuit breaker validation failed: %w", err)
+	}
+
+	if err := v.ValidateTracing(); err != nil {
+		return fmt.Errorf("tracing validation failed: %w", err)
+	}
+
+	if err := v.ValidateTLS(); err != nil {
+		return fmt.Errorf("tls validation failed: %w", err)
+	}
+
+	if err := v.ValidateSandbox(); err != nil {
+		return fmt.Errorf("sandbox validation failed: %w", err)
+	}
+
+	if err := v.ValidateLog(); err != nil {
+		return fmt.Errorf("log validation failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -59,6 +124,10 @@ func (v *ConfigValidator) ValidateServer() error {
 		return fmt.Errorf("invalid port format: %s", v.config.Server.Port)
 	}
 
+	if v.config.Server.MaxBodyBytes <= 0 {
+		return fmt.Errorf("server max body bytes must be positive")
+	}
+
 	return nil
 }
 
@@ -83,6 +152,275 @@ func (v *ConfigValidator) ValidateRedis() error {
 	return nil
 }
 
+// ValidateTokenRefresh validates background token refresh configuration
+func (v *ConfigValidator) ValidateTokenRefresh() error {
+	if v.config.TokenTTL < 0 {
+		return fmt.Errorf("token ttl must not be negative")
+	}
+
+	if !v.config.TokenRefresh.Enabled {
+		return nil
+	}
+
+	if v.config.TokenRefresh.Interval <= 0 {
+		return fmt.Errorf("token refresh interval must be positive")
+	}
+
+	if v.config.TokenRefresh.Window <= 0 {
+		return fmt.Errorf("token refresh window must be positive")
+	}
+
+	return nil
+}
+
+// ValidateCleanup validates the periodic purge of expired storage entries
+func (v *ConfigValidator) ValidateCleanup() error {
+	if !v.config.Cleanup.Enabled {
+		return nil
+	}
+
+	if v.config.Cleanup.Interval <= 0 {
+		return fmt.Errorf("cleanup interval must be positive")
+	}
+
+	return nil
+}
+
+// ValidateRateLimit validates rate limiting configuration
+func (v *ConfigValidator) ValidateRateLimit() error {
+	if !v.config.RateLimit.Enabled {
+		return nil
+	}
+
+	if v.config.RateLimit.RequestsPerMinute <= 0 {
+		return fmt.Errorf("rate limit requests per minute must be positive")
+	}
+
+	if v.config.RateLimit.Burst <= 0 {
+		return fmt.Errorf("rate limit burst must be positive")
+	}
+
+	for serverName, tier := range v.config.RateLimit.Servers {
+		if _, ok := v.config.Servers[serverName]; !ok {
+			return fmt.Errorf("rate limit configured for unknown server %q", serverName)
+		}
+		if tier.RequestsPerMinute <= 0 {
+			return fmt.Errorf("rate limit requests per minute of server %q must be positive", serverName)
+		}
+		if tier.Burst <= 0 {
+			return fmt.Errorf("rate limit burst of server %q must be positive", serverName)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCORS validates the allowed browser origins
+func (v *ConfigValidator) ValidateCORS() error {
+	if !v.config.CORS.Enabled {
+		return nil
+	}
+
+	if len(v.config.CORS.AllowedOrigins) == 0 {
+		return fmt.Errorf("cors allowed origins cannot be empty")
+	}
+
+	for _, origin := range v.config.CORS.AllowedOrigins {
+		if origin == "*" {
+			if IsProduction() {
+				return fmt.Errorf("cors wildcard origin is not allowed in production")
+			}
+			continue
+		}
+		if err := validateOrigin(origin); err != nil {
+			return fmt.Errorf("cors allowed origin %s is invalid: %w", origin, err)
+		}
+	}
+
+	if v.config.CORS.MaxAge < 0 {
+		return fmt.Errorf("cors max age cannot be negative")
+	}
+
+	return nil
+}
+
+// ValidateTimeouts validates API operation deadlines
+func (v *ConfigValidator) ValidateTimeouts() error {
+	timeouts := map[string]time.Duration{
+		"share":       v.config.Timeouts.Share,
+		"batch_share": v.config.Timeouts.BatchShare,
+		"read":        v.config.Timeouts.Read,
+		"batch_read":  v.config.Timeouts.BatchRead,
+	}
+
+	for name, timeout := range timeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("%s timeout must be positive", name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateScheduler validates scheduled share worker configuration
+func (v *ConfigValidator) ValidateScheduler() error {
+	if !v.config.Scheduler.Enabled {
+		return nil
+	}
+
+	if v.config.Scheduler.Interval <= 0 {
+		return fmt.Errorf("scheduler interval must be positive")
+	}
+
+	return nil
+}
+
+// ValidateStatsCache validates stats cache configuration
+func (v *ConfigValidator) ValidateStatsCache() error {
+	if !v.config.StatsCache.Enabled {
+		return nil
+	}
+
+	if v.config.StatsCache.TTL <= 0 {
+		return fmt.Errorf("stats cache ttl must be positive")
+	}
+
+	return nil
+}
+
+// ValidateUserInfoCache validates user info cache configuration
+func (v *ConfigValidator) ValidateUserInfoCache() error {
+	if !v.config.UserInfoCache.Enabled {
+		return nil
+	}
+
+	if v.config.UserInfoCache.TTL <= 0 {
+		return fmt.Errorf("user info cache ttl must be positive")
+	}
+
+	return nil
+}
+
+// ValidateIdempotency validates Idempotency-Key configuration
+func (v *ConfigValidator) ValidateIdempotency() error {
+	if !v.config.Idempotency.Enabled {
+		return nil
+	}
+
+	if v.config.Idempotency.TTL <= 0 {
+		return fmt.Errorf("idempotency ttl must be positive")
+	}
+
+	return nil
+}
+
+// ValidateMedia validates the per-provider media download limits
+func (v *ConfigValidator) ValidateMedia() error {
+	if v.config.Media.BaseURL != "" {
+		if err := validateHTTPURL(v.config.Media.BaseURL); err != nil {
+			return fmt.Errorf("media base url %s is invalid: %w", v.config.Media.BaseURL, err)
+		}
+	}
+
+	for provider, maxSizeMB := range v.config.Media.MaxSizeMB {
+		if _, ok := (ServerOAuthConfig{}).Provider(provider); !ok {
+			return fmt.Errorf("media size limit configured for unknown provider %q", provider)
+		}
+		if maxSizeMB <= 0 {
+			return fmt.Errorf("media size limit of provider %q must be positive", provider)
+		}
+	}
+	return nil
+}
+
+// ValidateRetry validates the upstream API retry policy
+func (v *ConfigValidator) ValidateRetry() error {
+	retry := v.config.Retry
+
+	if retry.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative")
+	}
+
+	if retry.MaxRetries > 0 && (retry.BaseDelay <= 0 || retry.MaxDelay < retry.BaseDelay) {
+		return fmt.Errorf("base delay must be positive and not exceed max delay")
+	}
+
+	return nil
+}
+
+// ValidateCircuitBreaker validates the per-provider circuit breaker
+func (v *ConfigValidator) ValidateCircuitBreaker() error {
+	breaker := v.config.CircuitBreaker
+	if !breaker.Enabled {
+		return nil
+	}
+
+	if breaker.FailureThreshold <= 0 {
+		return fmt.Errorf("failure threshold must be positive")
+	}
+
+	if breaker.OpenTimeout <= 0 {
+		return fmt.Errorf("open timeout must be positive")
+	}
+
+	return nil
+}
+
+// ValidateTracing validates the OTLP collector of request tracing
+func (v *ConfigValidator) ValidateTracing() error {
+	tracing := v.config.Tracing
+	if tracing.Endpoint == "" {
+		return nil
+	}
+
+	if err := validateHTTPURL(tracing.Endpoint); err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", tracing.Endpoint, err)
+	}
+
+	if tracing.ServiceName == "" {
+		return fmt.Errorf("service name is required")
+	}
+
+	return nil
+}
+
+// ValidateTLS validates the minimum TLS version of outbound requests
+func (v *ConfigValidator) ValidateTLS() error {
+	switch v.config.TLS.MinVersion {
+	case "1.2", "1.3":
+		return nil
+	default:
+		return fmt.Errorf("min version must be 1.2 or 1.3, got %q", v.config.TLS.MinVersion)
+	}
+}
+
+// ValidateLog validates the log level and format
+func (v *ConfigValidator) ValidateLog() error {
+	switch v.config.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("level must be debug, info, warn or error, got %q", v.config.Log.Level)
+	}
+
+	switch v.config.Log.Format {
+	case "json", "text":
+		return nil
+	default:
+		return fmt.Errorf("format must be json or text, got %q", v.config.Log.Format)
+	}
+}
+
+// ValidateSandbox validates the providers selected for sandbox mode
+func (v *ConfigValidator) ValidateSandbox() error {
+	for _, provider := range v.config.Sandbox.Providers {
+		if _, ok := (ServerOAuthConfig{}).Provider(provider); !ok {
+			return fmt.Errorf("unknown sandbox provider %s", provider)
+		}
+	}
+
+	return nil
+}
+
 // ValidateOAuth validates OAuth configuration in servers
 func (v *ConfigValidator) ValidateOAuth() error {
 	// 验证每个服务器的 OAuth 配置
@@ -93,6 +431,11 @@ func (v *ConfigValidator) ValidateOAuth() error {
 			"facebook":  serverConfig.Facebook,
 			"tiktok":    serverConfig.TikTok,
 			"instagram": serverConfig.Instagram,
+			"pinterest": serverConfig.Pinterest,
+			"mastodon":  serverConfig.Mastodon,
+			"reddit":    serverConfig.Reddit,
+			"discord":   serverConfig.Discord,
+			"threads":   serverConfig.Threads,
 		}
 
 		for name, provider := range providers {
@@ -161,6 +504,11 @@ func (v *ConfigValidator) ValidateServerConfig(serverName string, serverConfig S
 		"facebook":  serverConfig.Facebook,
 		"tiktok":    serverConfig.TikTok,
 		"instagram": serverConfig.Instagram,
+		"pinterest": serverConfig.Pinterest,
+		"mastodon":  serverConfig.Mastodon,
+		"reddit":    serverConfig.Reddit,
+		"discord":   serverConfig.Discord,
+		"threads":   serverConfig.Threads,
 	}
 
 	for providerName, provider := range providers {
@@ -170,11 +518,85 @@ func (v *ConfigValidator) ValidateServerConfig(serverName string, serverConfig S
 				return err
 			}
 		}
+
+		if provider.TokenTTL < 0 {
+			return fmt.Errorf("OAuth provider %s.%s token ttl must not be negative", serverName, providerName)
+		}
+
+		if provider.Timeout < 0 {
+			return fmt.Errorf("OAuth provider %s.%s timeout must not be negative", serverName, providerName)
+		}
+
+		if provider.InstanceURL != "" {
+			if err := validateInstanceURL(provider.InstanceURL); err != nil {
+				return fmt.Errorf("OAuth provider %s.%s instance url %s is invalid: %w", serverName, providerName, provider.InstanceURL, err)
+			}
+		}
+
+		if provider.APIHost != "" && !IsKnownAPIHost(providerName, provider.APIHost) {
+			return fmt.Errorf("OAuth provider %s.%s api host %s is not supported, expected one of %v",
+				serverName, providerName, provider.APIHost, ProviderAPIHosts[providerName])
+		}
+	}
+
+	redirects := map[string]string{
+		"success_url": serverConfig.CallbackRedirect.SuccessURL,
+		"failure_url": serverConfig.CallbackRedirect.FailureURL,
+	}
+	for name, redirectURL := range redirects {
+		if redirectURL == "" {
+			continue
+		}
+		if err := validateHTTPURL(redirectURL); err != nil {
+			return fmt.Errorf("callback redirect %s.%s %s is invalid: %w", serverName, name, redirectURL, err)
+		}
 	}
 
 	return nil
 }
 
+// validateOrigin checks that a browser origin is a bare http or https origin
+func validateOrigin(origin string) error {
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("must be an http or https url")
+	}
+	if strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "" {
+		return fmt.Errorf("must not have a path or query")
+	}
+	return nil
+}
+
+// validateHTTPURL checks that a url is an absolute http or https url
+func validateHTTPURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("must be an http or https url")
+	}
+	return nil
+}
+
+// validateInstanceURL checks that an instance URL is a bare https origin
+func validateInstanceURL(instanceURL string) error {
+	parsed, err := url.Parse(instanceURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("must be an https url")
+	}
+	if strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "" {
+		return fmt.Errorf("must not have a path or query")
+	}
+	return nil
+}
+
 // GetValidationWarnings returns non-critical validation warnings
 func (v *ConfigValidator) GetValidationWarnings() []string {
 	var warnings []string
@@ -189,6 +611,10 @@ func (v *ConfigValidator) GetValidationWarnings() []string {
 		}
 	}
 
+	if IsProduction() && v.config.Sandbox.Enabled {
+		warnings = append(warnings, "Sandbox mode is enabled in production, shares are not posted")
+	}
+
 	// Check for missing server configurations
 	if len(v.config.Servers) == 0 {
 		warnings = append(warnings, "No multi-server configurations found")
@@ -202,14 +628,56 @@ func (v *ConfigValidator) GetValidationWarnings() []string {
 			"facebook":  serverConfig.Facebook,
 			"tiktok":    serverConfig.TikTok,
 			"instagram": serverConfig.Instagram,
+			"pinterest": serverConfig.Pinterest,
+			"mastodon":  serverConfig.Mastodon,
+			"reddit":    serverConfig.Reddit,
+			"discord":   serverConfig.Discord,
+			"threads":   serverConfig.Threads,
 		}
 
 		for name, provider := range providers {
 			if provider.ClientID == "" || provider.ClientSecret == "" {
 				warnings = append(warnings, fmt.Sprintf("Server %s: OAuth provider %s is not configured", serverName, name))
+				continue
+			}
+			warnings = append(warnings, v.scopeWarnings(serverName, name, provider)...)
+		}
+	}
+
+	return warnings
+}
+
+// scopeWarnings reports the scopes a configured provider is missing for the operations it
+// supports, which would otherwise only show up as a 403 once a user tries them
+func (v *ConfigValidator) scopeWarnings(serverName, name string, provider ProviderConfig) []string {
+	granted := make(map[string]bool)
+	for _, scope := range provider.Scopes {
+		granted[scope] = true
+		for _, implied := range ProviderImpliedScopes[name][scope] {
+			granted[implied] = true
+		}
+	}
+
+	missingScopes := func(scopes []string) []string {
+		var missing []string
+		for _, scope := range scopes {
+			if !granted[scope] {
+				missing = append(missing, scope)
 			}
 		}
+		return missing
 	}
 
+	var warnings []string
+	if missing := missingScopes(ProviderBaseScopes[name]); len(missing) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Server %s: OAuth provider %s is missing scope(s) %s",
+			serverName, name, strings.Join(missing, ", ")))
+	}
+	for _, operation := range scopeOperations {
+		if missing := missingScopes(v.config.RequiredScopes(name, serverName, operation)); len(missing) > 0 {
+			warnings = append(warnings, fmt.Sprintf("Server %s: OAuth provider %s is missing scope(s) %s required for %s",
+				serverName, name, strings.Join(missing, ", "), operation))
+		}
+	}
 	return warnings
 }
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"social/internal/config"
+	"social/internal/middleware"
+	"social/internal/types"
+	"social/pkg/logger"
+	"social/pkg/response"
+)
+
+// AdminHandler handles operator endpoints
+type AdminHandler struct {
+	configs     config.ConfigProvider
+	maintenance *middleware.MaintenanceMiddleware
+	logger      *logger.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(configs config.ConfigProvider, maintenance *middleware.MaintenanceMiddleware, logger *logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		configs:     configs,
+		maintenance: maintenance,
+		logger:      logger,
+	}
+}
+
+// GetMaintenance returns the maintenance mode state
+// @Summary 查询维护模式
+// @Description 查询当前是否处于维护模式，维护模式下写接口返回503，读接口不受影响
+// @Tags 管理
+// @Produce json
+// @Param X-Admin-Token header string true "管理员Token"
+// @Success 200 {object} types.APIResponse{data=types.MaintenanceResponse} "维护模式状态"
+// @Failure 401 {object} types.ErrorResponse "管理员Token无效"
+// @Failure 403 {object} types.ErrorResponse "未配置管理员Token"
+// @Router /admin/maintenance [get]
+func (h *AdminHandler) GetMaintenance(c *gin.Context) {
+	enabled, message := h.maintenance.Enabled()
+	response.Success(c, types.MaintenanceResponse{
+		Enabled: enabled,
+		Message: message,
+	})
+}
+
+// SetMaintenance turns maintenance mode on or off
+// @Summary 切换维护模式
+// @Description 开启或关闭维护模式，开启后 /api/share、/api/batch-share、/api/delete-post 等写接口返回503
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "管理员Token"
+// @Param request body types.MaintenanceRequest true "维护模式设置"
+// @Success 200 {object} types.APIResponse{data=types.MaintenanceResponse} "设置后的维护模式状态"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 401 {object} types.ErrorResponse "管理员Token无效"
+// @Failure 403 {object} types.ErrorResponse "未配置管理员Token"
+// @Router /admin/maintenance [post]
+func (h *AdminHandler) SetMaintenance(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind maintenance request")
+		response.BindError(c, err)
+		return
+	}
+
+	h.maintenance.Set(*req.Enabled, req.Message)
+	enabled, message := h.maintenance.Enabled()
+
+	h.logger.Warn(ctx, "maintenance mode changed", "enabled", enabled, "message", message)
+
+	response.SuccessWithMessage(c, "maintenance mode updated", types.MaintenanceResponse{
+		Enabled: enabled,
+		Message: message,
+	})
+}
+
+// GetConfig returns the effective configuration with secrets redacted
+// @Summary 查询生效配置
+// @Description 返回合并配置文件、环境变量和默认值后实际生效的配置，client_secret、密码和管理员Token已脱敏；同时返回各服务已配置凭据的平台和配置校验警告，用于排查配置是否按预期加载
+// @Tags 管理
+// @Produce json
+// @Param X-Admin-Token header string true "管理员Token"
+// @Success 200 {object} types.APIResponse{data=types.AdminConfigResponse} "脱敏后的生效配置"
+// @Failure 401 {object} types.ErrorResponse "管理员Token无效"
+// @Failure 403 {object} types.ErrorResponse "未配置管理员Token"
+// @Router /admin/config [get]
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	cfg := h.configs.Get()
+
+	h.logger.Info(c.Request.Context(), "effective configuration requested")
+
+	response.Success(c, types.AdminConfigResponse{
+		Environment: config.GetEnvironment(),
+		Config:      cfg.Redacted(),
+		Servers:     cfg.ConfiguredServers(),
+		Warnings:    config.NewConfigValidator(cfg).GetValidationWarnings(),
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,7 +22,7 @@ import (
 
 // AuthHandler handles OAuth authentication requests
 type AuthHandler struct {
-	config           *config.Config
+	configs          config.ConfigProvider
 	storage          storage.Storage
 	logger           *logger.Logger
 	platformRegistry *platforms.Registry
@@ -28,16 +30,21 @@ type AuthHandler struct {
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(cfg *config.Config, storage storage.Storage, platformRegistry *platforms.Registry, logger *logger.Logger) *AuthHandler {
+func NewAuthHandler(configs config.ConfigProvider, storage storage.Storage, platformRegistry *platforms.Registry, logger *logger.Logger) *AuthHandler {
 	return &AuthHandler{
-		config:           cfg,
+		configs:          configs,
 		storage:          storage,
 		logger:           logger,
 		platformRegistry: platformRegistry,
-		tokenManager:     oauth.NewTokenManager(cfg, storage, logger),
+		tokenManager:     oauth.NewTokenManager(configs, storage, logger),
 	}
 }
 
+// config returns the current configuration
+func (h *AuthHandler) config() *config.Config {
+	return h.configs.Get()
+}
+
 // StartAuth initiates OAuth flow
 // @Summary 开始OAuth授权流程
 // @Description 启动指定平台的OAuth授权流程，返回授权URL
@@ -55,12 +62,12 @@ func (h *AuthHandler) StartAuth(c *gin.Context) {
 	var req types.StartAuthRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind start auth request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
 	// Get OAuth config with server-specific configuration
-	oauthConfig, err := h.config.GetServerOAuthConfig(req.Provider, req.ServerName, req.RedirectURI)
+	oauthConfig, err := h.config().GetServerOAuthConfig(req.Provider, req.ServerName, req.RedirectURI)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get OAuth config", "provider", req.Provider, "server_name", req.ServerName)
 		response.ErrorWithDetail(c, errors.ErrInvalidProvider, err.Error())
@@ -68,18 +75,25 @@ func (h *AuthHandler) StartAuth(c *gin.Context) {
 	}
 
 	// Encode state with server name
-	state, err := oauth.EncodeState(req.UserID, req.ServerName)
+	state, err := oauth.EncodeState(req.UserID, req.ServerName, req.AccountID)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to encode state")
 		response.InternalServerError(c, "failed to generate state")
 		return
 	}
 
+	// Track the issued state so the callback can prove it started here
+	if err := h.storage.SaveOAuthState(ctx, state); err != nil {
+		h.logger.Error(ctx, err, "failed to save OAuth state")
+		response.InternalServerError(c, "failed to save state")
+		return
+	}
+
 	// Create OAuth service
 	oauthService := oauth.NewOAuthService(oauthConfig)
 
 	// Generate auth URL
-	usePKCE := req.Provider == "x" // Only X/Twitter uses PKCE
+	usePKCE := h.config().UsesPKCE(req.Provider, req.ServerName)
 	authURL, verifier, err := oauthService.GenerateAuthURL(state, usePKCE)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to generate auth URL", "provider", req.Provider)
@@ -111,7 +125,7 @@ func (h *AuthHandler) StartAuth(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info(ctx, "OAuth flow initiated", "provider", req.Provider, "user_id", req.UserID, "server_name", req.ServerName)
+	h.logger.Info(ctx, "OAuth flow initiated", "provider", req.Provider, "user_id", req.UserID, "account_id", req.AccountID, "server_name", req.ServerName)
 
 	// 返回授权 URL，让前端处理重定向
 	authResponse := types.StartAuthResponse{
@@ -140,16 +154,30 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	var req types.CallbackRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind callback request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
+	callbackResponse, appErr, detail := h.completeCallback(ctx, &req)
+	if appErr != nil {
+		if detail != "" {
+			response.ErrorWithDetail(c, appErr, detail)
+		} else {
+			response.Error(c, appErr)
+		}
+		return
+	}
+	response.SuccessWithMessage(c, "OAuth callback completed successfully", callbackResponse)
+}
+
+// completeCallback exchanges the authorization code of a callback and saves the token.
+// On failure it returns the error to respond with and, for errors shown in debug mode, its detail.
+func (h *AuthHandler) completeCallback(ctx context.Context, req *types.CallbackRequest) (types.CallbackResponse, *errors.AppError, string) {
 	// Decode state
 	statePayload, err := oauth.DecodeState(req.State)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to decode state")
-		response.Error(c, errors.ErrInvalidState)
-		return
+		return types.CallbackResponse{}, errors.ErrInvalidState, ""
 	}
 
 	h.logger.Info(ctx, "decoded state", "state", req.State, "state_payload user_id", statePayload.UserID, "state_payload server_name", statePayload.ServerName)
@@ -158,17 +186,30 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	userID := req.UserID
 	serverName := req.ServerName
 
+	// 账号以发起授权时state中的为准，回调请求可以不携带，携带时须与state一致
+	accountID := statePayload.AccountID
+	tokenUserID := types.AccountUserID(userID, accountID)
+
 	// 验证请求中的 server_name 与 state 中的 server_name 是否一致
 	if req.ServerName != statePayload.ServerName {
 		h.logger.Error(ctx, errors.ErrInvalidState, "server_name mismatch", "request_server", req.ServerName, "state_server", statePayload.ServerName)
-		response.Error(c, errors.ErrInvalidState)
-		return
+		return types.CallbackResponse{}, errors.ErrInvalidState, ""
+	}
+	if req.AccountID != "" && req.AccountID != statePayload.AccountID {
+		h.logger.Error(ctx, errors.ErrInvalidState, "account_id mismatch", "request_account", req.AccountID, "state_account", statePayload.AccountID)
+		return types.CallbackResponse{}, errors.ErrInvalidState, ""
 	}
 
 	// 记录平台用户ID用于日志和调试
 	platformUserID := statePayload.UserID
 	h.logger.Info(ctx, "processing OAuth callback", "service_user_id", userID, "platform_user_id", platformUserID, "server_name", serverName)
 
+	// Reject states that were never issued or were already used, before the code is exchanged
+	if err := h.storage.ConsumeOAuthState(ctx, req.State); err != nil {
+		h.logger.Error(ctx, err, "OAuth state verification failed", "provider", req.Provider, "server_name", serverName)
+		return types.CallbackResponse{}, errors.ErrInvalidState, ""
+	}
+
 	// Get OAuth config with server-specific configuration
 	// Use the redirect URI from the request or default callback URL
 	// For token exchange, we need to use the exact same redirect_uri as used in authorization
@@ -191,19 +232,21 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 		}
 	}
 
-	oauthConfig, err := h.config.GetServerOAuthConfig(req.Provider, serverName, redirectURI)
+	oauthConfig, err := h.config().GetServerOAuthConfig(req.Provider, serverName, redirectURI)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get OAuth config", "provider", req.Provider, "server_name", serverName)
-		response.ErrorWithDetail(c, errors.ErrInvalidProvider, err.Error())
-		return
+		return types.CallbackResponse{}, errors.ErrInvalidProvider, err.Error()
 	}
 
 	// Create OAuth service
 	oauthService := oauth.NewOAuthService(oauthConfig)
+	oauthService.SetUserAgent(h.config().GetUserAgent(req.Provider, serverName))
+	oauthService.SetTimeout(h.config().GetProviderTimeout(req.Provider, serverName))
+	oauthService.SetLogger(h.logger)
 
-	// Get PKCE verifier if needed (for X platform)
+	// Get the PKCE verifier saved when the authorization was started
 	var verifier string
-	if req.Provider == "x" {
+	if h.config().UsesPKCE(req.Provider, serverName) {
 		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
@@ -212,8 +255,7 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 		verifier, err = h.storage.GetAndDeletePKCEVerifier(ctx, req.State)
 		if err != nil {
 			h.logger.Error(ctx, err, "failed to get PKCE verifier", "provider", req.Provider, "state", req.State)
-			response.ErrorWithDetail(c, errors.ErrInvalidState, "PKCE verifier not found or expired")
-			return
+			return types.CallbackResponse{}, errors.ErrInvalidState, "PKCE verifier not found or expired"
 		}
 
 		verifierPreview := verifier
@@ -227,8 +269,7 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	token, err := oauthService.ExchangeCode(ctx, req.Code, verifier)
 	if err != nil {
 		h.logger.Error(ctx, err, "token exchange failed", "provider", req.Provider, "service_user_id", userID, "platform_user_id", platformUserID)
-		response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("token exchange failed: %v", err))
-		return
+		return types.CallbackResponse{}, errors.ErrInternalServer, fmt.Sprintf("token exchange failed: %v", err)
 	}
 
 	// Save token to storage
@@ -237,27 +278,24 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 
 	h.logger.Info(ctx, "attempting to save token", "provider", req.Provider, "service_user_id", userID, "platform_user_id", platformUserID, "server_name", serverName, "token_type", token.TokenType)
 
-	if err := h.storage.SaveToken(ctx, userID, req.Provider, serverName, token); err != nil {
+	if err := h.storage.SaveToken(ctx, tokenUserID, req.Provider, serverName, token); err != nil {
 		h.logger.Error(ctx, err, "failed to save token", "provider", req.Provider, "service_user_id", userID, "platform_user_id", platformUserID, "server_name", serverName)
-		response.ErrorWithDetail(c, errors.ErrInternalServer, "failed to save token")
-		return
+		return types.CallbackResponse{}, errors.ErrInternalServer, "failed to save token"
 	}
 
 	// Verify token was saved successfully by trying to retrieve it
 	ctx2, cancel2 := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel2()
 
-	savedToken, err := h.storage.GetToken(ctx2, userID, req.Provider, serverName)
+	savedToken, err := h.storage.GetToken(ctx2, tokenUserID, req.Provider, serverName)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to verify token save", "provider", req.Provider, "service_user_id", userID, "platform_user_id", platformUserID, "server_name", serverName)
-		response.ErrorWithDetail(c, errors.ErrInternalServer, "token save verification failed")
-		return
+		return types.CallbackResponse{}, errors.ErrInternalServer, "token save verification failed"
 	}
 
 	if savedToken.AccessToken != token.AccessToken {
 		h.logger.Error(ctx, errors.ErrInternalServer, "token save verification failed - access token mismatch", "provider", req.Provider, "service_user_id", userID, "platform_user_id", platformUserID, "server_name", serverName)
-		response.ErrorWithDetail(c, errors.ErrInternalServer, "token save verification failed")
-		return
+		return types.CallbackResponse{}, errors.ErrInternalServer, "token save verification failed"
 	}
 
 	h.logger.Info(ctx, "token saved and verified successfully", "provider", req.Provider, "service_user_id", userID, "platform_user_id", platformUserID, "server_name", serverName)
@@ -266,8 +304,7 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	platformInstance, err := h.platformRegistry.GetPlatform(req.Provider)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get platform", "provider", req.Provider)
-		response.ErrorWithDetail(c, errors.ErrInvalidProvider, err.Error())
-		return
+		return types.CallbackResponse{}, errors.ErrInvalidProvider, err.Error()
 	}
 
 	// 调用平台特定的OAuth回调处理（用于平台特定的后处理）
@@ -290,12 +327,13 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	callbackResponse := types.CallbackResponse{
 		Provider:   req.Provider,
 		UserID:     userID,
+		AccountID:  accountID,
 		ServerName: serverName,
 		ExpiresAt:  expiresAt,
 		ReferAt:    referAt,
 		Message:    fmt.Sprintf("OAuth callback completed for user %s provider %s. You may close this window.", userID, req.Provider),
 	}
-	response.SuccessWithMessage(c, "OAuth callback completed successfully", callbackResponse)
+	return callbackResponse, nil, ""
 }
 
 // 查询是否授权
@@ -315,7 +353,7 @@ func (h *AuthHandler) IsAuthorized(c *gin.Context) {
 	var req types.IsAuthorizedRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind is authorized request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
@@ -323,27 +361,139 @@ func (h *AuthHandler) IsAuthorized(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	isValid, err := h.tokenManager.IsTokenValid(ctx, req.UserID, req.Provider, req.ServerName)
+	isValid, expiresAt, err := h.tokenManager.GetTokenStatus(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+	if err != nil || !isValid {
+		// A missing token is reported the same way as an expired one
+		h.logger.Error(ctx, errors.ErrTokenExpired, "token not valid", "provider", req.Provider, "user_id", req.UserID)
+		response.Error(c, errors.ErrTokenExpired)
+		return
+	}
+
+	var expiresIn int64
+	if expiresAt > 0 {
+		expiresIn = max(expiresAt-time.Now().Unix(), 0)
+	}
+
+	response.Success(c, types.IsAuthorizedResponse{
+		IsAuthorized:     true,
+		ExpiresAt:        expiresAt,
+		ExpiresInSeconds: expiresIn,
+	})
+}
+
+// ListAuthorized lists the platforms a user has authorized
+// @Summary 查询已授权平台列表
+// @Description 查询指定用户在指定服务下已授权的所有平台及token有效期
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body types.ListAuthorizedRequest true "查询已授权平台请求参数"
+// @Success 200 {object} types.APIResponse{data=types.ListAuthorizedResponse} "查询成功"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /auth/list-authorized [post]
+func (h *AuthHandler) ListAuthorized(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.ListAuthorizedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind list authorized request")
+		response.BindError(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	providers, err := h.storage.ListProvidersForUser(ctx, req.UserID, req.ServerName)
 	if err != nil {
-		h.logger.Error(ctx, err, "failed to check token validity", "provider", req.Provider, "user_id", req.UserID)
+		h.logger.Error(ctx, err, "failed to list providers for user", "user_id", req.UserID, "server_name", req.ServerName)
 		response.Error(c, errors.ErrInternalServer)
 		return
 	}
+	sort.Strings(providers)
 
-	if !isValid {
-		h.logger.Error(ctx, errors.ErrTokenExpired, "token not valid", "provider", req.Provider, "user_id", req.UserID)
-		response.Error(c, errors.ErrTokenExpired)
+	authorized := make([]types.AuthorizedProvider, 0, len(providers))
+	for _, provider := range providers {
+		isValid, expiresAt, err := h.tokenManager.GetTokenStatus(ctx, req.UserID, provider, req.ServerName)
+		if err != nil {
+			// Token was removed between listing and reading
+			h.logger.Warn(ctx, "failed to get token status", "provider", provider, "user_id", req.UserID, "error", err)
+			continue
+		}
+
+		authorized = append(authorized, types.AuthorizedProvider{
+			Provider:  provider,
+			IsValid:   isValid,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	response.Success(c, types.ListAuthorizedResponse{
+		UserID:     req.UserID,
+		ServerName: req.ServerName,
+		Providers:  authorized,
+	})
+}
+
+// ListAccounts lists the accounts a user has connected for a platform
+// @Summary 查询已连接账号列表
+// @Description 查询指定用户在指定平台连接的所有账号及token有效期，默认账号的account_id为空字符串
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body types.ListAccountsRequest true "查询已连接账号请求参数"
+// @Success 200 {object} types.APIResponse{data=types.ListAccountsResponse} "查询成功"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /auth/list-accounts [post]
+func (h *AuthHandler) ListAccounts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.ListAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind list accounts request")
+		response.BindError(c, err)
 		return
 	}
 
-	response.Success(c, types.IsAuthorizedResponse{
-		IsAuthorized: true,
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	accountIDs, err := h.storage.ListAccounts(ctx, req.UserID, req.Provider, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to list accounts for user", "provider", req.Provider, "user_id", req.UserID, "server_name", req.ServerName)
+		response.Error(c, errors.ErrInternalServer)
+		return
+	}
+
+	accounts := make([]types.ConnectedAccount, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		isValid, expiresAt, err := h.tokenManager.GetTokenStatus(ctx, types.AccountUserID(req.UserID, accountID), req.Provider, req.ServerName)
+		if err != nil {
+			// Token was removed between listing and reading
+			h.logger.Warn(ctx, "failed to get token status", "provider", req.Provider, "user_id", req.UserID, "account_id", accountID, "error", err)
+			continue
+		}
+
+		accounts = append(accounts, types.ConnectedAccount{
+			AccountID: accountID,
+			IsValid:   isValid,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	response.Success(c, types.ListAccountsResponse{
+		Provider:   req.Provider,
+		UserID:     req.UserID,
+		ServerName: req.ServerName,
+		Accounts:   accounts,
 	})
 }
 
 // GetUserInfo retrieves user information from the platform
 // @Summary 获取用户信息
-// @Description 获取指定平台用户的详细信息
+// @Description 获取指定平台用户的详细信息，结果默认缓存15分钟，force_refresh为true时跳过缓存
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -359,7 +509,7 @@ func (h *AuthHandler) GetUserInfo(c *gin.Context) {
 	var req types.GetUserInfoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind get user info request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
@@ -367,13 +517,25 @@ func (h *AuthHandler) GetUserInfo(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	token, err := h.tokenManager.GetValidToken(ctx, req.UserID, req.Provider, req.ServerName)
+	token, err := h.tokenManager.GetValidToken(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get valid token", "provider", req.Provider, "user_id", req.UserID)
 		response.Error(c, errors.ErrTokenExpired)
 		return
 	}
 
+	// Profiles rarely change, serve them from the cache while the user stays authorized
+	if userInfo, ok := h.cachedUserInfo(ctx, &req); ok {
+		response.Success(c, types.GetUserInfoResponse{
+			Provider:   req.Provider,
+			UserID:     req.UserID,
+			ServerName: req.ServerName,
+			UserInfo:   userInfo,
+			Cached:     true,
+		})
+		return
+	}
+
 	// Get platform instance
 	platformInstance, err := h.platformRegistry.GetPlatform(req.Provider)
 	if err != nil {
@@ -383,7 +545,7 @@ func (h *AuthHandler) GetUserInfo(c *gin.Context) {
 	}
 
 	// Create OAuth service to get HTTP client with token
-	oauthConfig, err := h.config.GetServerOAuthConfig(req.Provider, req.ServerName, "")
+	oauthConfig, err := h.config().GetServerOAuthConfig(req.Provider, req.ServerName, "")
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get OAuth config", "provider", req.Provider, "server_name", req.ServerName)
 		response.ErrorWithDetail(c, errors.ErrInvalidProvider, err.Error())
@@ -391,6 +553,9 @@ func (h *AuthHandler) GetUserInfo(c *gin.Context) {
 	}
 
 	oauthService := oauth.NewOAuthService(oauthConfig)
+	oauthService.SetUserAgent(h.config().GetUserAgent(req.Provider, req.ServerName))
+	oauthService.SetTimeout(h.config().GetProviderTimeout(req.Provider, req.ServerName))
+	oauthService.SetLogger(h.logger)
 	client := oauthService.CreateClient(ctx, token)
 
 	// Get user info from platform
@@ -402,6 +567,7 @@ func (h *AuthHandler) GetUserInfo(c *gin.Context) {
 	}
 
 	h.logger.Info(ctx, "user info retrieved successfully", "provider", req.Provider, "user_id", req.UserID, "platform_user_id", userInfo.ID)
+	h.cacheUserInfo(ctx, &req, userInfo)
 
 	userInfoResponse := types.GetUserInfoResponse{
 		Provider:   req.Provider,
@@ -413,6 +579,82 @@ func (h *AuthHandler) GetUserInfo(c *gin.Context) {
 	response.Success(c, userInfoResponse)
 }
 
+// RefreshAllTokens force-refreshes the tokens of every platform a user has authorized
+// @Summary 刷新用户所有平台的token
+// @Description 强制刷新指定用户在指定服务下已授权的所有平台（默认账号）的token，部分平台失败时仍返回200并在结果中标明每个平台的成功或失败，适合在批量操作前主动续期
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body types.RefreshAllTokensRequest true "刷新所有token请求参数"
+// @Success 200 {object} types.APIResponse{data=types.RefreshAllTokensResponse} "各平台刷新结果"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /auth/refresh-all [post]
+func (h *AuthHandler) RefreshAllTokens(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.RefreshAllTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind refresh all tokens request")
+		response.BindError(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	providers, err := h.storage.ListProvidersForUser(ctx, req.UserID, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to list providers for user", "user_id", req.UserID, "server_name", req.ServerName)
+		response.Error(c, errors.ErrInternalServer)
+		return
+	}
+	sort.Strings(providers)
+
+	// Refresh providers concurrently, each goroutine writes only its own slot so results
+	// keep the sorted provider order
+	results := make([]types.ProviderRefreshResult, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider string) {
+			defer wg.Done()
+			results[i] = types.ProviderRefreshResult{Provider: provider}
+			newToken, err := h.tokenManager.ForceRefreshToken(ctx, req.UserID, provider, req.ServerName)
+			if err != nil {
+				h.logger.Error(ctx, err, "failed to refresh token", "provider", provider, "user_id", req.UserID)
+				results[i].Error = err.Error()
+				return
+			}
+			if !newToken.Expiry.IsZero() {
+				results[i].ExpiresAt = newToken.Expiry.Unix()
+			}
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var successCount int
+	var errorCount int
+	for _, result := range results {
+		if result.Error != "" {
+			errorCount++
+		} else {
+			successCount++
+		}
+	}
+
+	h.logger.Info(ctx, "refresh all tokens completed", "user_id", req.UserID, "server_name", req.ServerName, "success_count", successCount, "error_count", errorCount)
+
+	response.Success(c, types.RefreshAllTokensResponse{
+		UserID:       req.UserID,
+		ServerName:   req.ServerName,
+		Providers:    results,
+		SuccessCount: successCount,
+		ErrorCount:   errorCount,
+		RefreshedAt:  time.Now().Unix(),
+	})
+}
+
 // RefreshToken handles manual token refresh requests
 // @Summary 手动刷新token
 // @Description 客户端主动刷新指定平台的访问token
@@ -431,7 +673,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req types.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind refresh token request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
@@ -439,7 +681,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	newToken, err := h.tokenManager.ForceRefreshToken(ctx, req.UserID, req.Provider, req.ServerName)
+	newToken, err := h.tokenManager.ForceRefreshToken(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to refresh token", "provider", req.Provider, "user_id", req.UserID)
 		if err.Error() == "OAuth token not found" {
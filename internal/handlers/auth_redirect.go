@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/config"
+	"social/internal/oauth"
+	"social/internal/types"
+	"social/pkg/errors"
+	"social/pkg/response"
+)
+
+// Callback statuses passed to the configured redirect URLs
+const (
+	callbackStatusSuccess = "success"
+	callbackStatusFailed  = "failed"
+)
+
+// CallbackRedirect handles the OAuth callback in the browser
+// @Summary 浏览器OAuth回调
+// @Description 作为第三方平台的redirect_uri使用（{base_url}/auth/callback/{provider}），完成授权码交换后重定向到服务配置的 callback_redirect 地址，附带 status 和 provider 参数，失败时还附带 error 错误码。服务未配置重定向地址时返回与 POST /auth/callback 相同的JSON
+// @Tags 认证
+// @Produce json
+// @Param provider path string true "平台名称"
+// @Param code query string false "授权码"
+// @Param state query string true "状态参数"
+// @Param error query string false "平台返回的错误，如用户拒绝授权"
+// @Success 302 "重定向到配置的成功或失败地址"
+// @Success 200 {object} types.APIResponse{data=types.CallbackResponse} "OAuth callback completed"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /auth/callback/{provider} [get]
+func (h *AuthHandler) CallbackRedirect(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	provider := c.Param("provider")
+	if _, ok := (config.ServerOAuthConfig{}).Provider(provider); !ok {
+		response.Error(c, errors.ErrInvalidProvider)
+		return
+	}
+
+	// The server, and so where to redirect, is only known once the state is decoded
+	state := c.Query("state")
+	statePayload, err := oauth.DecodeState(state)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to decode state", "provider", provider)
+		response.Error(c, errors.ErrInvalidState)
+		return
+	}
+	redirect := h.config().Servers[statePayload.ServerName].CallbackRedirect
+
+	if platformErr := c.Query("error"); platformErr != "" {
+		h.logger.Warn(ctx, "OAuth authorization denied", "provider", provider, "server_name", statePayload.ServerName, "error", platformErr, "error_description", c.Query("error_description"))
+		h.callbackFailed(c, redirect, provider, errors.ErrAuthorizationDenied, platformErr)
+		return
+	}
+
+	req := types.CallbackRequest{
+		Provider:   provider,
+		ServerName: statePayload.ServerName,
+		UserID:     statePayload.UserID,
+		AccountID:  statePayload.AccountID,
+		State:      state,
+		Code:       c.Query("code"),
+		// The token exchange must repeat the redirect_uri of the authorization, which is this endpoint
+		RedirectURI: strings.TrimSuffix(h.config().Server.BaseURL, "/") + c.Request.URL.Path,
+	}
+	if req.Code == "" {
+		h.callbackFailed(c, redirect, provider, errors.ErrInvalidRequest, "code is required")
+		return
+	}
+
+	callbackResponse, appErr, detail := h.completeCallback(ctx, &req)
+	if appErr != nil {
+		h.callbackFailed(c, redirect, provider, appErr, detail)
+		return
+	}
+
+	if redirect.SuccessURL == "" {
+		response.SuccessWithMessage(c, "OAuth callback completed successfully", callbackResponse)
+		return
+	}
+	response.Redirect(c, callbackRedirectURL(redirect.SuccessURL, url.Values{
+		"status":   {callbackStatusSuccess},
+		"provider": {provider},
+	}))
+}
+
+// callbackFailed redirects to the server's failure URL with the error code, or responds with
+// the error when no failure URL is configured
+func (h *AuthHandler) callbackFailed(c *gin.Context, redirect config.CallbackRedirectConfig, provider string, appErr *errors.AppError, detail string) {
+	if redirect.FailureURL == "" {
+		if detail != "" {
+			response.ErrorWithDetail(c, appErr, detail)
+		} else {
+			response.Error(c, appErr)
+		}
+		return
+	}
+	response.Redirect(c, callbackRedirectURL(redirect.FailureURL, url.Values{
+		"status":   {callbackStatusFailed},
+		"provider": {provider},
+		"error":    {appErr.Code},
+	}))
+}
+
+// callbackRedirectURL adds params to the query of a configured redirect URL, keeping its own params
+func callbackRedirectURL(redirectURL string, params url.Values) string {
+	parsed, err := url.Parse(redirectURL)
+	if err != nil {
+		// Validated when the configuration is loaded
+		return redirectURL
+	}
+	query := parsed.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
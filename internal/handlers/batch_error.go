@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	stderrors "errors"
+
+	"social/internal/types"
+	"social/pkg/errors"
+)
+
+// authAppError maps a failure to create an authenticated client to the API error reported for it.
+// Anything but a missing token or a timeout is a failed refresh, so the token is no longer usable.
+func authAppError(timeoutErr *errors.TimeoutError, err error) *errors.AppError {
+	switch {
+	case timeoutErr != nil:
+		return timeoutErr.AppError()
+	case stderrors.Is(err, errors.ErrTokenNotFound):
+		return errors.ErrTokenNotFound
+	default:
+		return errors.NewAppError(errors.ErrTokenExpired.Code, "authentication failed: "+err.Error(), errors.ErrTokenExpired.Status)
+	}
+}
+
+// errorCategory tells batch callers how to recover from a failed platform with the given error code
+func errorCategory(code string) string {
+	switch code {
+	case errors.ErrTokenNotFound.Code, errors.ErrTokenExpired.Code, errors.ErrPlatformAuthFailed.Code, errors.ErrInsufficientScope.Code:
+		return types.ErrorCategoryReauthorize
+	case errors.ErrRateLimited.Code, errors.ErrTimeout.Code, errors.ErrUpstream.Code, errors.ErrServiceUnavailable.Code:
+		return types.ErrorCategoryRetry
+	default:
+		return types.ErrorCategoryPermanent
+	}
+}
+
+// failPlatformPosts records a platform's failure in its batch recent posts result
+func failPlatformPosts(result types.PlatformPosts, appErr *errors.AppError) types.PlatformPosts {
+	result.Error = appErr.Message
+	result.ErrorCode = appErr.Code
+	result.ErrorCategory = errorCategory(appErr.Code)
+	if !appErr.ResetAt.IsZero() {
+		result.ResetAt = appErr.ResetAt.Unix()
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"social/internal/config"
+	"social/internal/platforms"
+	"social/internal/types"
+	"social/pkg/httpx"
+)
+
+// Share callback delivery settings
+const (
+	callbackMaxAttempts    = 3
+	callbackInitialBackoff = 2 * time.Second
+	callbackTimeout        = 10 * time.Second
+)
+
+// callbackClient delivers share callbacks. The URL comes from the caller, so the client only
+// connects to public addresses and doesn't go through a proxy, and redirects are not followed so
+// the callback can't be bounced to a different host
+var callbackClient = sync.OnceValue(func() *http.Client {
+	dialer := &net.Dialer{Timeout: callbackTimeout, Control: platforms.PublicDialControl}
+	return &http.Client{
+		Timeout: callbackTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSClientConfig:     &tls.Config{MinVersion: httpx.MinTLSVersion()},
+			TLSHandshakeTimeout: callbackTimeout,
+			ForceAttemptHTTP2:   true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+})
+
+// notifyShareCallback posts the share response to the caller's callback URL, retrying with
+// exponential backoff. It runs detached from the request and only logs failures.
+func (h *ShareHandler) notifyShareCallback(ctx context.Context, callbackURL string, shareResponse types.ShareResponse) {
+	payload, err := json.Marshal(shareResponse)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to marshal share callback payload", "provider", shareResponse.Provider, "user_id", shareResponse.UserID)
+		return
+	}
+
+	backoff := callbackInitialBackoff
+	for attempt := 1; attempt <= callbackMaxAttempts; attempt++ {
+		err = h.postCallback(ctx, callbackURL, payload)
+		if err == nil {
+			h.logger.Info(ctx, "share callback delivered", "provider", shareResponse.Provider, "user_id", shareResponse.UserID, "attempt", attempt)
+			return
+		}
+
+		if attempt < callbackMaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	h.logger.Error(ctx, err, "failed to deliver share callback", "provider", shareResponse.Provider, "user_id", shareResponse.UserID, "attempts", callbackMaxAttempts)
+}
+
+// postCallback makes a single callback delivery attempt
+func (h *ShareHandler) postCallback(ctx context.Context, callbackURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := callbackClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send callback request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// validateCallbackURL checks that rawURL can be used as a share callback, production only
+// delivers callbacks over https since the payload carries post IDs and URLs
+func validateCallbackURL(rawURL string) error {
+	if !isHTTPURL(rawURL) {
+		return fmt.Errorf("callback_url must be an http or https url")
+	}
+	if config.IsProduction() && !strings.HasPrefix(strings.ToLower(rawURL), "https:") {
+		return fmt.Errorf("callback_url must be an https url")
+	}
+	return nil
+}
+
+// isHTTPURL reports whether rawURL is an absolute http or https URL
+func isHTTPURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/types"
+	"social/pkg/response"
+)
+
+// ListPlatforms handles platform capability requests
+// @Summary 获取支持的平台及其能力
+// @Description 返回所有已注册的平台及各自支持的操作，如是否可删除、发布是否必须带媒体、是否支持统计和最近内容，客户端可据此调整界面。
+// @Description 可通过能力参数筛选平台，多个参数同时满足才返回，如 ?supports_video=true&supports_scheduling=true
+// @Tags 平台
+// @Produce json
+// @Param can_share query bool false "按是否可以发布筛选"
+// @Param can_delete query bool false "按是否可以删除筛选"
+// @Param requires_media query bool false "按发布是否必须带媒体筛选"
+// @Param supports_stats query bool false "按是否支持统计筛选"
+// @Param supports_recent_posts query bool false "按是否支持最近内容筛选"
+// @Param supports_scheduling query bool false "按是否支持定时发布筛选"
+// @Param supports_video query bool false "按是否支持视频筛选"
+// @Success 200 {object} types.APIResponse{data=types.ListPlatformsResponse} "平台能力列表"
+// @Failure 400 {object} types.APIResponse "筛选参数无效"
+// @Router /api/platforms [get]
+func (h *ShareHandler) ListPlatforms(c *gin.Context) {
+	var filter types.PlatformCapabilityFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.BadRequest(c, "capability filters must be true or false")
+		return
+	}
+
+	names := h.registry.GetSupportedPlatforms()
+	sort.Strings(names)
+
+	platformInfos := make([]types.PlatformInfo, 0, len(names))
+	for _, name := range names {
+		platform, err := h.registry.GetPlatform(name)
+		if err != nil {
+			continue
+		}
+		capabilities := platform.Capabilities()
+		if !filter.Matches(capabilities) {
+			continue
+		}
+		platformInfos = append(platformInfos, types.PlatformInfo{
+			Name:         name,
+			Capabilities: capabilities,
+		})
+	}
+
+	response.Success(c, types.ListPlatformsResponse{
+		Platforms: platformInfos,
+	})
+}
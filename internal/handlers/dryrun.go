@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/types"
+	"social/pkg/errors"
+	"social/pkg/response"
+)
+
+// dryRunShare runs the pre-flight checks of a share request without posting it:
+// the token must be valid and the platform must accept the content and media combination.
+// The response echoes what would be posted, with an empty media ID.
+func (h *ShareHandler) dryRunShare(c *gin.Context, req *types.ShareRequest) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.config().ShareTimeout(req.Provider, req.ServerName))
+	defer cancel()
+
+	if _, err := h.shareClient(ctx, req); err != nil {
+		h.logger.Error(ctx, err, "dry run failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
+		if timeoutErr := timeoutError(ctx, err, operationShare, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
+		}
+		return
+	}
+
+	platform, err := h.registry.GetPlatform(req.Provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		response.Error(c, errors.ErrPlatformNotSupported)
+		return
+	}
+
+	if validator, ok := platform.(types.ShareValidator); ok {
+		if err := validator.Validate(req); err != nil {
+			response.UnprocessableEntity(c, err.Error())
+			return
+		}
+	}
+
+	h.logger.Info(ctx, "share dry run passed", "provider", req.Provider, "user_id", req.UserID)
+
+	response.SuccessWithMessage(c, "dry run succeeded, nothing was posted", types.ShareResponse{
+		Provider:    req.Provider,
+		UserID:      req.UserID,
+		ServerName:  req.ServerName,
+		Content:     req.Content,
+		MediaURL:    req.MediaURL,
+		Tags:        req.Tags,
+		Status:      types.PostStatusDryRun,
+		ScheduledAt: req.ScheduledAt,
+	})
+}
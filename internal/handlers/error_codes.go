@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"social/internal/types"
+	"social/pkg/errors"
+	"social/pkg/response"
+)
+
+// ListErrorCodes handles error code list requests
+// @Summary 获取错误码列表
+// @Description 返回API可能返回的所有错误码及其HTTP状态码和默认说明，由错误定义生成，客户端可据此按错误码处理错误
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} types.APIResponse{data=types.ListErrorCodesResponse} "错误码列表"
+// @Router /api/errors [get]
+func (h *ShareHandler) ListErrorCodes(c *gin.Context) {
+	catalog := errors.Catalog()
+
+	codes := make([]types.ErrorCodeInfo, 0, len(catalog))
+	for _, appErr := range catalog {
+		codes = append(codes, types.ErrorCodeInfo{
+			Code:        appErr.Code,
+			Status:      appErr.Status,
+			Description: appErr.Message,
+		})
+	}
+
+	response.Success(c, types.ListErrorCodesResponse{
+		Errors: codes,
+	})
+}
@@ -2,26 +2,39 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"social/internal/config"
+	"social/internal/platforms"
 	"social/internal/storage"
+	"social/internal/types"
+	"social/pkg/breaker"
 	ctxutil "social/pkg/context"
 	"social/pkg/logger"
 	"social/pkg/response"
 )
 
+// readinessTimeout bounds all checks of a readiness request
+const readinessTimeout = 5 * time.Second
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
+	configs config.ConfigProvider
 	storage storage.Storage
 	logger  *logger.Logger
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(storage storage.Storage, logger *logger.Logger) *HealthHandler {
+func NewHealthHandler(configs config.ConfigProvider, storage storage.Storage, logger *logger.Logger) *HealthHandler {
 	return &HealthHandler{
+		configs: configs,
 		storage: storage,
 		logger:  logger,
 	}
@@ -53,3 +66,122 @@ func (h *HealthHandler) Health(c *gin.Context) {
 		"timestamp": time.Now().UTC(),
 	})
 }
+
+// Ready performs a readiness check
+// @Summary 就绪检查
+// @Description 检查存储连接和配置校验，providers=true时同时检查已配置平台的API和Token地址是否可达；同时返回已调用过的平台的熔断器状态（熔断中为down）；关键组件（存储、配置）不可用时返回503，平台不可达和熔断只在组件状态中体现
+// @Tags 系统
+// @Produce json
+// @Param providers query bool false "是否检查平台地址可达性"
+// @Success 200 {object} types.APIResponse{data=types.ReadinessResponse} "已就绪"
+// @Failure 503 {object} types.APIResponse{data=types.ReadinessResponse} "关键组件不可用"
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	requestID := uuid.New().String()
+	ctx := ctxutil.WithRequestID(c.Request.Context(), requestID)
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	cfg := h.configs.Get()
+	components := map[string]types.ComponentStatus{
+		"storage": componentStatus(h.storage.Health(ctx), true),
+		"config":  componentStatus(config.NewConfigValidator(cfg).ValidateAll(), true),
+	}
+
+	for provider, status := range breakerStatuses() {
+		components["breaker:"+provider] = status
+	}
+
+	if c.Query("providers") == "true" {
+		for provider, status := range h.providerStatuses(ctx, cfg.ConfiguredProviders()) {
+			components["provider:"+provider] = status
+		}
+	}
+
+	ready := true
+	for name, component := range components {
+		if component.Status == types.ComponentStatusDown {
+			h.logger.Warn(ctx, "readiness check component down", "component", name, "critical", component.Critical, "error", component.Error)
+			if component.Critical {
+				ready = false
+			}
+		}
+	}
+
+	readiness := types.ReadinessResponse{
+		Ready:      ready,
+		Timestamp:  time.Now().Unix(),
+		Components: components,
+	}
+	if !ready {
+		response.ServiceUnavailableWithData(c, "service not ready", readiness)
+		return
+	}
+	response.Success(c, readiness)
+}
+
+// providerStatuses checks in parallel that the hosts of each provider can be reached
+func (h *HealthHandler) providerStatuses(ctx context.Context, providers []string) map[string]types.ComponentStatus {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	statuses := make(map[string]types.ComponentStatus, len(providers))
+
+	for _, provider := range providers {
+		if !platforms.HasKnownHosts(provider) {
+			statuses[provider] = types.ComponentStatus{Status: types.ComponentStatusSkipped}
+			continue
+		}
+
+		wg.Add(1)
+		go func(provider string) {
+			defer wg.Done()
+			status := componentStatus(reachabilityError(platforms.CheckReachable(ctx, provider)), false)
+			mu.Lock()
+			statuses[provider] = status
+			mu.Unlock()
+		}(provider)
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+// breakerStatuses reports the circuit breaker of each provider that has made API calls, an open
+// breaker is down until it lets a probe through
+func breakerStatuses() map[string]types.ComponentStatus {
+	statuses := make(map[string]types.ComponentStatus)
+	for provider, status := range breaker.Statuses() {
+		if status.State == breaker.StateOpen {
+			statuses[provider] = types.ComponentStatus{
+				Status: types.ComponentStatusDown,
+				Error:  fmt.Sprintf("circuit open after %d consecutive failures until %s", status.Failures, status.OpenUntil.UTC().Format(time.RFC3339)),
+			}
+			continue
+		}
+		statuses[provider] = types.ComponentStatus{Status: types.ComponentStatusUp}
+	}
+	return statuses
+}
+
+// reachabilityError combines the failures of unreachable hosts into one error, or nil if all were reached
+func reachabilityError(failures map[string]error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(failures))
+	for host, err := range failures {
+		messages = append(messages, fmt.Sprintf("%s: %v", host, err))
+	}
+	sort.Strings(messages)
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+// componentStatus converts the result of a component check to its status
+func componentStatus(err error, critical bool) types.ComponentStatus {
+	if err != nil {
+		return types.ComponentStatus{Status: types.ComponentStatusDown, Critical: critical, Error: err.Error()}
+	}
+	return types.ComponentStatus{Status: types.ComponentStatusUp, Critical: critical}
+}
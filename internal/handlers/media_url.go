@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+
+	"social/internal/types"
+)
+
+// resolveMediaURLs turns relative media URLs of a share request into absolute ones against
+// media.base_url, so platforms always download from a full URL
+func (h *ShareHandler) resolveMediaURLs(req *types.ShareRequest) error {
+	var err error
+	if req.MediaURL, err = h.resolveMediaURL(req.MediaURL); err != nil {
+		return err
+	}
+	for i, mediaURL := range req.MediaURLs {
+		if req.MediaURLs[i], err = h.resolveMediaURL(mediaURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveMediaURL resolves a relative or protocol-relative media URL, absolute ones are returned unchanged
+func (h *ShareHandler) resolveMediaURL(mediaURL string) (string, error) {
+	if mediaURL == "" {
+		return "", nil
+	}
+
+	ref, err := url.Parse(mediaURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid media url %q: %w", mediaURL, err)
+	}
+	if ref.IsAbs() {
+		return mediaURL, nil
+	}
+
+	baseURL := h.config().Media.BaseURL
+	if baseURL == "" {
+		return "", fmt.Errorf("relative media url %q can't be resolved, media.base_url is not configured", mediaURL)
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid media base url: %w", err)
+	}
+
+	resolved := base.ResolveReference(ref)
+	if !isHTTPURL(resolved.String()) {
+		return "", fmt.Errorf("media url %q doesn't resolve to an http or https url", mediaURL)
+	}
+	return resolved.String(), nil
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+
+	"social/internal/platforms"
+	"social/internal/types"
+	"social/pkg/breaker"
+	"social/pkg/errors"
+	"social/pkg/quota"
+)
+
+// platformAppError maps a categorized platform API failure, an operation the platform doesn't
+// offer or a platform whose circuit breaker is open to the API error reported for it, carrying the
+// platform's original message. Rate limited errors carry the reset time of the platform's rate
+// limit window recorded in ctx. It returns nil for other errors and for upstream failures, which
+// are reported as internal errors.
+func platformAppError(ctx context.Context, err error) *errors.AppError {
+	if openErr, ok := breaker.AsOpenError(err); ok {
+		return errors.NewAppError(errors.ErrServiceUnavailable.Code, openErr.Error(), errors.ErrServiceUnavailable.Status)
+	}
+
+	if exhaustedErr, ok := quota.AsExhaustedError(err); ok {
+		return errors.NewAppError(errors.ErrRateLimited.Code, exhaustedErr.Error(), errors.ErrRateLimited.Status).WithResetAt(exhaustedErr.ResetAt)
+	}
+
+	if stderrors.Is(err, types.ErrOperationNotSupported) {
+		return errors.NewAppError(errors.ErrPlatformNotSupported.Code, err.Error(), errors.ErrPlatformNotSupported.Status)
+	}
+
+	platformErr, ok := platforms.AsPlatformError(err)
+	if !ok {
+		return nil
+	}
+
+	var appErr *errors.AppError
+	switch platformErr.Category {
+	case platforms.CategoryRateLimited:
+		appErr = errors.ErrRateLimited
+	case platforms.CategoryAuthFailed:
+		appErr = errors.ErrPlatformAuthFailed
+	case platforms.CategorySuspended:
+		appErr = errors.ErrAccountSuspended
+	case platforms.CategoryNotFound:
+		appErr = errors.ErrNotFound
+	case platforms.CategoryInvalidContent:
+		appErr = errors.ErrUnprocessableEntity
+	case platforms.CategoryAccessLevel:
+		appErr = errors.ErrAccessLevel
+	default:
+		return nil
+	}
+
+	mapped := errors.NewAppError(appErr.Code, platformErr.Error(), appErr.Status)
+	if platformErr.Category == platforms.CategoryRateLimited {
+		if resetAt, ok := quota.ResetAt(ctx); ok {
+			mapped.ResetAt = resetAt
+		}
+	}
+	return mapped
+}
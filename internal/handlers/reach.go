@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"social/internal/storage"
+	"social/internal/types"
+)
+
+// Reach check tuning
+const (
+	// reachMaxSamples is how many recently checked posts make up a user's baseline
+	reachMaxSamples = 50
+
+	// reachMinSamples is how many other posts are needed before the baseline is trusted
+	reachMinSamples = 5
+
+	// reachMinEngagements keeps posts with too little engagement out of the ratio comparison
+	reachMinEngagements = 3
+
+	// reachLowRatio flags a post whose impressions per engagement fall below this share of the baseline
+	reachLowRatio = 0.3
+)
+
+// checkReach records the post's reach in the user's baseline and returns a warning if its
+// impressions per engagement are anomalously low compared to the user's other posts, which
+// suggests the post is shown to engaged followers but suppressed elsewhere. It is best-effort:
+// storage errors are logged and skipped.
+func (h *ShareHandler) checkReach(ctx context.Context, req *types.StatsRequest, stats types.StatsData) []string {
+	if req.MediaID == "" || stats.Views == 0 {
+		// Impressions aren't available for every access level, nothing to compare
+		return nil
+	}
+
+	samples, err := h.storage.GetReachSamples(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to get reach samples", "provider", req.Provider, "user_id", req.UserID)
+		return nil
+	}
+
+	engagements := stats.Likes + stats.Retweets + stats.Replies + stats.Shares
+	current := storage.ReachSample{
+		Impressions: stats.Views,
+		Engagements: engagements,
+		SampledAt:   time.Now().Unix(),
+	}
+
+	// Compare against the other posts before recording this one
+	baseline, baselineSize := reachBaseline(samples, req.MediaID)
+
+	samples[req.MediaID] = current
+	pruneReachSamples(samples)
+	if err := h.storage.SaveReachSamples(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName, samples); err != nil {
+		h.logger.Error(ctx, err, "failed to save reach samples", "provider", req.Provider, "user_id", req.UserID)
+	}
+
+	if baselineSize < reachMinSamples || engagements < reachMinEngagements {
+		return nil
+	}
+
+	ratio := float64(current.Impressions) / float64(engagements)
+	if ratio >= baseline*reachLowRatio {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("reach looks unusually low: %.0f impressions per engagement against a baseline of %.0f across %d recent posts, the post may have limited visibility",
+		ratio, baseline, baselineSize)}
+}
+
+// reachBaseline returns the median impressions per engagement of the samples other than
+// excludeID, and the number of samples it was computed from
+func reachBaseline(samples map[string]storage.ReachSample, excludeID string) (float64, int) {
+	var ratios []float64
+	for mediaID, sample := range samples {
+		if mediaID == excludeID || sample.Engagements < reachMinEngagements {
+			continue
+		}
+		ratios = append(ratios, float64(sample.Impressions)/float64(sample.Engagements))
+	}
+
+	if len(ratios) == 0 {
+		return 0, 0
+	}
+
+	sort.Float64s(ratios)
+	middle := len(ratios) / 2
+	if len(ratios)%2 == 0 {
+		return (ratios[middle-1] + ratios[middle]) / 2, len(ratios)
+	}
+	return ratios[middle], len(ratios)
+}
+
+// pruneReachSamples drops the least recently checked samples beyond reachMaxSamples
+func pruneReachSamples(samples map[string]storage.ReachSample) {
+	if len(samples) <= reachMaxSamples {
+		return
+	}
+
+	mediaIDs := make([]string, 0, len(samples))
+	for mediaID := range samples {
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+	sort.Slice(mediaIDs, func(i, j int) bool {
+		return samples[mediaIDs[i]].SampledAt > samples[mediaIDs[j]].SampledAt
+	})
+
+	for _, mediaID := range mediaIDs[reachMaxSamples:] {
+		delete(samples, mediaID)
+	}
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/middleware"
+	"social/internal/oauth"
+	"social/internal/platforms"
+	"social/internal/storage"
+	"social/internal/types"
+	"social/pkg/errors"
+	"social/pkg/metrics"
+	"social/pkg/response"
+)
+
+// scheduleShare stores a share request with a future scheduled_at and responds with its job ID
+func (h *ShareHandler) scheduleShare(c *gin.Context, req *types.ShareRequest) {
+	ctx := c.Request.Context()
+
+	if _, err := h.registry.GetPlatform(req.Provider); err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		response.Error(c, errors.ErrPlatformNotSupported)
+		return
+	}
+
+	// Fail now rather than at publish time if the user never authorized the platform
+	if err := h.checkShareCredentials(ctx, req); err != nil {
+		h.logger.Error(ctx, err, "token not found for scheduled share", "provider", req.Provider, "user_id", req.UserID)
+		response.Error(c, errors.ErrTokenNotFound)
+		return
+	}
+
+	jobID, err := oauth.RandStringURLSafe(16)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to generate job id")
+		response.InternalServerError(c, "failed to generate job id")
+		return
+	}
+
+	post := &storage.ScheduledPost{
+		ID:          jobID,
+		ScheduledAt: req.ScheduledAt,
+		CreatedAt:   time.Now().Unix(),
+		Request:     *req,
+	}
+	if err := h.storage.SaveScheduledPost(ctx, post); err != nil {
+		h.logger.Error(ctx, err, "failed to save scheduled post", "provider", req.Provider, "user_id", req.UserID)
+		response.InternalServerError(c, "failed to schedule share")
+		return
+	}
+
+	h.logger.Info(ctx, "share scheduled", "job_id", jobID, "provider", req.Provider, "user_id", req.UserID, "scheduled_at", req.ScheduledAt)
+
+	response.SuccessWithMessage(c, "share scheduled", types.ShareResponse{
+		Provider:    req.Provider,
+		UserID:      req.UserID,
+		ServerName:  req.ServerName,
+		Content:     req.Content,
+		MediaURL:    req.MediaURL,
+		Tags:        req.Tags,
+		Status:      types.PostStatusScheduled,
+		JobID:       jobID,
+		ScheduledAt: req.ScheduledAt,
+	})
+}
+
+// CancelScheduled handles scheduled share cancellation requests
+// @Summary 取消定时发布
+// @Description 按job_id取消尚未发布的定时分享，已发布、已取消或不属于该用户的任务返回404
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param request body types.CancelScheduledRequest true "取消定时发布请求参数"
+// @Success 200 {object} types.APIResponse{data=types.CancelScheduledResponse} "取消成功"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 404 {object} types.ErrorResponse "任务不存在或已发布"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /api/scheduled/cancel [post]
+func (h *ShareHandler) CancelScheduled(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.CancelScheduledRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind cancel scheduled request")
+		response.BindError(c, err)
+		return
+	}
+
+	post, err := h.storage.TakeScheduledPost(ctx, req.JobID)
+	if err != nil {
+		if stderrors.Is(err, storage.ErrScheduledPostNotFound) {
+			response.Error(c, errors.ErrScheduledPostNotFound)
+			return
+		}
+		h.logger.Error(ctx, err, "failed to cancel scheduled post", "job_id", req.JobID)
+		response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		return
+	}
+
+	// Jobs of other users are reported as missing and put back untouched
+	if post.Request.UserID != req.UserID || post.Request.ServerName != req.ServerName {
+		if err := h.storage.SaveScheduledPost(ctx, post); err != nil {
+			h.logger.Error(ctx, err, "failed to restore scheduled post", "job_id", req.JobID)
+		}
+		response.Error(c, errors.ErrScheduledPostNotFound)
+		return
+	}
+
+	h.logger.Info(ctx, "scheduled share cancelled", "job_id", req.JobID, "provider", post.Request.Provider, "user_id", req.UserID)
+
+	response.Success(c, types.CancelScheduledResponse{
+		JobID:       post.ID,
+		Provider:    post.Request.Provider,
+		ScheduledAt: post.ScheduledAt,
+		Cancelled:   true,
+	})
+}
+
+// ListFailedScheduled handles requests listing scheduled shares that failed to publish
+// @Summary 查询发布失败的定时任务
+// @Description 列出该用户到点发布失败的定时分享（如token过期、平台不可用），包含失败原因和时间，可通过 /api/scheduled/retry 重试
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param request body types.ListFailedScheduledRequest true "查询请求参数"
+// @Success 200 {object} types.APIResponse{data=types.ListFailedScheduledResponse} "发布失败的定时任务"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /api/scheduled/failed [post]
+func (h *ShareHandler) ListFailedScheduled(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.ListFailedScheduledRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind list failed scheduled request")
+		response.BindError(c, err)
+		return
+	}
+
+	posts, err := h.storage.ListFailedScheduledPosts(ctx)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to list failed scheduled posts", "user_id", req.UserID)
+		response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		return
+	}
+
+	failed := []types.FailedScheduledShare{}
+	for _, post := range posts {
+		if post.Request.UserID != req.UserID || post.Request.ServerName != req.ServerName {
+			continue
+		}
+		failed = append(failed, types.FailedScheduledShare{
+			JobID:       post.ID,
+			Provider:    post.Request.Provider,
+			Content:     post.Request.Content,
+			MediaURL:    post.Request.MediaURL,
+			ScheduledAt: post.ScheduledAt,
+			FailedAt:    post.FailedAt,
+			Error:       post.Error,
+		})
+	}
+
+	response.Success(c, types.ListFailedScheduledResponse{
+		Failed: failed,
+		Total:  len(failed),
+	})
+}
+
+// RetryScheduled handles requests retrying a scheduled share that failed to publish
+// @Summary 重试发布失败的定时任务
+// @Description 将发布失败的定时分享移出失败列表并重新排入调度，job_id不变；不属于该用户或已重试的任务返回404
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param request body types.RetryScheduledRequest true "重试请求参数"
+// @Success 200 {object} types.APIResponse{data=types.ShareResponse} "已重新排入调度"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 404 {object} types.ErrorResponse "任务不存在或已重试"
+// @Failure 422 {object} types.ErrorResponse "定时发布未启用"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /api/scheduled/retry [post]
+func (h *ShareHandler) RetryScheduled(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.RetryScheduledRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind retry scheduled request")
+		response.BindError(c, err)
+		return
+	}
+
+	if !h.config().Scheduler.Enabled {
+		response.UnprocessableEntity(c, "scheduled posting is disabled on this server")
+		return
+	}
+
+	failed, err := h.storage.TakeFailedScheduledPost(ctx, req.JobID)
+	if err != nil {
+		if stderrors.Is(err, storage.ErrScheduledPostNotFound) {
+			response.Error(c, errors.ErrFailedScheduledPostNotFound)
+			return
+		}
+		h.logger.Error(ctx, err, "failed to take failed scheduled post", "job_id", req.JobID)
+		response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		return
+	}
+
+	// Jobs of other users are reported as missing and put back untouched
+	if failed.Request.UserID != req.UserID || failed.Request.ServerName != req.ServerName {
+		if err := h.storage.SaveFailedScheduledPost(ctx, failed); err != nil {
+			h.logger.Error(ctx, err, "failed to restore failed scheduled post", "job_id", req.JobID)
+		}
+		response.Error(c, errors.ErrFailedScheduledPostNotFound)
+		return
+	}
+
+	post := failed.ScheduledPost
+	post.ScheduledAt = max(req.ScheduledAt, time.Now().Unix())
+	if err := h.storage.SaveScheduledPost(ctx, &post); err != nil {
+		h.logger.Error(ctx, err, "failed to reschedule failed scheduled post", "job_id", req.JobID)
+		// Keep it in the dead-letter list rather than losing it
+		if err := h.storage.SaveFailedScheduledPost(ctx, failed); err != nil {
+			h.logger.Error(ctx, err, "failed to restore failed scheduled post", "job_id", req.JobID)
+		}
+		response.InternalServerError(c, "failed to reschedule share")
+		return
+	}
+
+	h.logger.Info(ctx, "failed scheduled share retried", "job_id", post.ID, "provider", post.Request.Provider, "user_id", req.UserID, "scheduled_at", post.ScheduledAt)
+
+	response.SuccessWithMessage(c, "share rescheduled", types.ShareResponse{
+		Provider:    post.Request.Provider,
+		UserID:      post.Request.UserID,
+		ServerName:  post.Request.ServerName,
+		Content:     post.Request.Content,
+		MediaURL:    post.Request.MediaURL,
+		Tags:        post.Request.Tags,
+		Status:      types.PostStatusScheduled,
+		JobID:       post.ID,
+		ScheduledAt: post.ScheduledAt,
+	})
+}
+
+// Scheduler publishes scheduled shares once their time has come
+type Scheduler struct {
+	handler     *ShareHandler
+	maintenance *middleware.MaintenanceMiddleware
+	interval    time.Duration
+}
+
+// NewScheduler creates a new scheduled share worker publishing through the share handler
+func NewScheduler(handler *ShareHandler, maintenance *middleware.MaintenanceMiddleware, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		handler:     handler,
+		maintenance: maintenance,
+		interval:    interval,
+	}
+}
+
+// Run publishes due shares every interval until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	s.handler.logger.Info(ctx, "scheduler started", "interval", s.interval.String())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.handler.logger.Info(context.Background(), "scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue claims and publishes every scheduled share whose time has come
+func (s *Scheduler) publishDue(ctx context.Context) {
+	// Due posts are held while writes are blocked and published once maintenance ends
+	if enabled, _ := s.maintenance.Enabled(); enabled {
+		return
+	}
+
+	posts, err := s.handler.storage.ListDueScheduledPosts(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			s.handler.logger.Error(ctx, err, "failed to list due scheduled posts")
+		}
+		return
+	}
+
+	for _, due := range posts {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Claiming removes the job, so a cancelled job or one claimed by another instance is skipped
+		post, err := s.handler.storage.TakeScheduledPost(ctx, due.ID)
+		if err != nil {
+			if !stderrors.Is(err, storage.ErrScheduledPostNotFound) {
+				s.handler.logger.Error(ctx, err, "failed to claim scheduled post", "job_id", due.ID)
+			}
+			continue
+		}
+
+		s.handler.publishScheduled(ctx, post)
+	}
+}
+
+// publishScheduled shares a claimed scheduled post through the normal platform path.
+// Results are logged and, on success, delivered to the request's callback URL; failed posts
+// are moved to the dead-letter list so they can be retried.
+func (h *ShareHandler) publishScheduled(ctx context.Context, post *storage.ScheduledPost) {
+	req := &post.Request
+
+	ctx, cancel := context.WithTimeout(ctx, h.config().ShareTimeout(req.Provider, req.ServerName))
+	defer cancel()
+
+	client, err := h.shareClient(ctx, req)
+	if err != nil {
+		h.logger.Error(ctx, err, "scheduled share failed to authenticate", "job_id", post.ID, "provider", req.Provider, "user_id", req.UserID)
+		h.deadLetter(ctx, post, err)
+		return
+	}
+
+	platform, err := h.registry.GetPlatform(req.Provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "job_id", post.ID, "provider", req.Provider)
+		h.deadLetter(ctx, post, err)
+		return
+	}
+
+	if xPlatform, ok := platform.(*platforms.XPlatform); ok {
+		if err := xPlatform.CheckAccountStatus(ctx, client); err != nil {
+			h.logger.Error(ctx, err, "scheduled share account status check failed", "job_id", post.ID, "provider", req.Provider, "user_id", req.UserID)
+			h.deadLetter(ctx, post, err)
+			return
+		}
+	}
+
+	ctx, shareWarnings := types.WithShareWarnings(ctx)
+	ctx, uploads := types.WithUploadCounter(ctx)
+	ctx = h.withMediaSizeLimit(ctx, req.Provider)
+	mediaID, err := platform.Share(ctx, client, req)
+	metrics.RecordShare(req.Provider, err)
+	if err != nil {
+		if timeoutErr := timeoutError(ctx, err, operationShare, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			err = timeoutErr
+		}
+		h.logger.Error(ctx, err, "scheduled share failed", "job_id", post.ID, "provider", req.Provider, "user_id", req.UserID)
+		h.deadLetter(ctx, post, err)
+		return
+	}
+
+	postStatus := h.resolvePostStatus(ctx, platform, client, req.Provider, mediaID)
+	status := postStatus.Status
+	h.logger.Info(ctx, "scheduled share published", "job_id", post.ID, "provider", req.Provider, "user_id", req.UserID, "media_id", mediaID, "delay_seconds", time.Now().Unix()-post.ScheduledAt)
+
+	shareResponse := types.ShareResponse{
+		Provider:    req.Provider,
+		UserID:      req.UserID,
+		ServerName:  req.ServerName,
+		Content:     req.Content,
+		MediaURL:    req.MediaURL,
+		Tags:        req.Tags,
+		MediaID:     mediaID,
+		Status:      status,
+		Warnings:    shareWarnings.Messages(),
+		PostRef:     platforms.ParsePostRef(req.Provider, mediaID),
+		JobID:       post.ID,
+		ScheduledAt: post.ScheduledAt,
+
+		ProcessingError: postStatus.ProcessingError,
+		UploadedBytes:   uploads.Bytes(),
+	}
+
+	if req.VerifyAfterShare {
+		shareResponse.Verification = h.verifyPost(ctx, platform, client, req.Provider, mediaID, status)
+	}
+
+	if req.CallbackURL != "" {
+		h.notifyShareCallback(context.WithoutCancel(ctx), req.CallbackURL, shareResponse)
+	}
+}
+
+// deadLetter records a scheduled post that failed to publish in the dead-letter list
+func (h *ShareHandler) deadLetter(ctx context.Context, post *storage.ScheduledPost, err error) {
+	failed := &storage.FailedScheduledPost{
+		ScheduledPost: *post,
+		Error:         err.Error(),
+		FailedAt:      time.Now().Unix(),
+	}
+
+	// Save even if the share timed out, the post would be lost otherwise
+	if saveErr := h.storage.SaveFailedScheduledPost(context.WithoutCancel(ctx), failed); saveErr != nil {
+		h.logger.Error(ctx, saveErr, "failed to save failed scheduled post", "job_id", post.ID, "provider", post.Request.Provider)
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"social/internal/types"
+	"social/pkg/errors"
+)
+
+// scopeError returns an ErrInsufficientScope error naming the scopes the operation requires if err
+// is a provider's missing scope rejection, or nil for any other error, so clients can ask the user
+// to re-authorize with the right scopes
+func (h *ShareHandler) scopeError(err error, provider, serverName, operation string) *errors.AppError {
+	if !stderrors.Is(err, types.ErrInsufficientScope) {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s %s was rejected for a missing scope, re-authorize to grant it", provider, operation)
+	if scopes := h.config().RequiredScopes(provider, serverName, operation); len(scopes) > 0 {
+		message = fmt.Sprintf("%s %s requires scope(s) %s, re-authorize to grant them", provider, operation, strings.Join(scopes, ", "))
+	}
+
+	return errors.NewAppError(errors.ErrInsufficientScope.Code, message, errors.ErrInsufficientScope.Status)
+}
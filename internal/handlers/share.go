@@ -2,8 +2,14 @@ package handlers
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,32 +21,49 @@ import (
 	"social/internal/types"
 	"social/pkg/errors"
 	"social/pkg/logger"
+	"social/pkg/metrics"
+	"social/pkg/quota"
 	"social/pkg/response"
 )
 
 // ShareHandler handles content sharing requests
 type ShareHandler struct {
-	config       *config.Config
+	configs      config.ConfigProvider
 	storage      storage.Storage
 	registry     *platforms.Registry
 	logger       *logger.Logger
 	tokenManager *oauth.TokenManager
+	jobs         *shareJobs
 }
 
 // NewShareHandler creates a new share handler
-func NewShareHandler(cfg *config.Config, storage storage.Storage, registry *platforms.Registry, logger *logger.Logger) *ShareHandler {
+func NewShareHandler(configs config.ConfigProvider, storage storage.Storage, registry *platforms.Registry, logger *logger.Logger) *ShareHandler {
 	return &ShareHandler{
-		config:       cfg,
+		configs:      configs,
 		storage:      storage,
 		registry:     registry,
 		logger:       logger,
-		tokenManager: oauth.NewTokenManager(cfg, storage, logger),
+		tokenManager: oauth.NewTokenManager(configs, storage, logger),
+		jobs:         newShareJobs(),
 	}
 }
 
+// config returns the current configuration
+func (h *ShareHandler) config() *config.Config {
+	return h.configs.Get()
+}
+
+// newSanitizer creates the content sanitizer, or nil when sanitization is disabled
+func newSanitizer(cfg config.SanitizationConfig) *platforms.Sanitizer {
+	if !cfg.Enabled {
+		return nil
+	}
+	return platforms.NewSanitizer(cfg.ZeroWidth, cfg.Control)
+}
+
 // Share handles share requests
 // @Summary 分享内容到社交媒体平台
-// @Description 将内容分享到指定的社交媒体平台
+// @Description 将内容分享到指定的社交媒体平台；async为true时立即返回status为queued的job_id，在后台发布，进度和结果通过GET /api/share/progress/{job_id}获取
 // @Tags 分享
 // @Accept json
 // @Produce json
@@ -48,7 +71,12 @@ func NewShareHandler(cfg *config.Config, storage storage.Storage, registry *plat
 // @Success 200 {object} types.APIResponse{data=types.ShareResponse} "分享成功"
 // @Failure 400 {object} types.ErrorResponse "请求参数错误"
 // @Failure 401 {object} types.ErrorResponse "未授权"
+// @Failure 403 {object} types.ErrorResponse "token缺少该操作所需的权限（错误信息中列出需要的scope），或平台账户已被暂停"
+// @Failure 413 {object} types.ErrorResponse "媒体超过平台的大小上限"
+// @Failure 422 {object} types.ErrorResponse "内容或选项不被目标平台接受"
+// @Failure 429 {object} types.ErrorResponse "平台限流，请稍后再试"
 // @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Failure 504 {object} types.ErrorResponse "操作超时，返回超时的操作及其时限"
 // @Router /api/share [post]
 func (h *ShareHandler) Share(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -56,19 +84,111 @@ func (h *ShareHandler) Share(c *gin.Context) {
 	var req types.ShareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind share request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
+		return
+	}
+
+	if err := h.resolveMediaURLs(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	newSanitizer(h.config().Sanitization).ShareRequest(&req)
+
+	// Engagement settings are Instagram-only options
+	if req.Provider != "instagram" && (req.DisableComments || req.HideLikeCounts) {
+		response.UnprocessableEntity(c, "disable_comments and hide_like_counts are only supported for instagram")
+		return
+	}
+	if req.Provider != "instagram" && len(req.MediaURLs) > 0 {
+		response.UnprocessableEntity(c, "media_urls is only supported for instagram")
+		return
+	}
+	if req.Provider != "pinterest" && req.BoardID != "" {
+		response.UnprocessableEntity(c, "board_id is only supported for pinterest")
+		return
+	}
+	if req.Provider != "reddit" && req.Subreddit != "" {
+		response.UnprocessableEntity(c, "subreddit is only supported for reddit")
+		return
+	}
+	if req.Provider != "discord" && (req.ChannelID != "" || req.WebhookURL != "") {
+		response.UnprocessableEntity(c, "channel_id and webhook_url are only supported for discord")
+		return
+	}
+	if req.Provider != "facebook" && req.PageID != "" {
+		response.UnprocessableEntity(c, "page_id is only supported for facebook")
+		return
+	}
+	if req.InstanceURL != "" {
+		if req.Provider != "mastodon" {
+			response.UnprocessableEntity(c, "instance_url is only supported for mastodon")
+			return
+		}
+		// Tokens are issued by the server's configured instance and aren't valid anywhere else
+		if !sameInstance(req.InstanceURL, h.config().GetInstanceURL(req.Provider, req.ServerName)) {
+			response.UnprocessableEntity(c, "instance_url must match the mastodon instance configured for the server")
+			return
+		}
+	}
+	if req.Provider != "x" && (req.NumberThread || req.ThreadNumberFormat != "") {
+		response.UnprocessableEntity(c, "number_thread and thread_number_format are only supported for x")
+		return
+	}
+	if req.Provider != "youtube" && req.NotifySubscribers != nil {
+		response.UnprocessableEntity(c, "notify_subscribers is only supported for youtube")
+		return
+	}
+	if req.VerifyAfterShare && !h.config().ShareVerification.Enabled {
+		response.UnprocessableEntity(c, "verify_after_share is disabled on this server")
+		return
+	}
+	if req.CallbackURL != "" {
+		if err := validateCallbackURL(req.CallbackURL); err != nil {
+			response.UnprocessableEntity(c, err.Error())
+			return
+		}
+	}
+	if req.Async && (req.VerifyAfterShare || req.ValidateLink) {
+		response.UnprocessableEntity(c, "verify_after_share and validate_link are not supported with async")
+		return
+	}
+
+	// Pre-flight only, nothing is posted or scheduled
+	if req.DryRun {
+		h.dryRunShare(c, &req)
+		return
+	}
+
+	// Posts scheduled for the future are stored and published by the scheduler, unless the
+	// platform schedules them itself
+	scheduledNatively := req.ScheduledAt > time.Now().Unix() && h.schedulesNatively(req.Provider)
+	if req.ScheduledAt > time.Now().Unix() && !scheduledNatively {
+		if !h.config().Scheduler.Enabled {
+			response.UnprocessableEntity(c, "scheduled_at is not supported, scheduled posting is disabled on this server")
+			return
+		}
+		h.scheduleShare(c, &req)
+		return
+	}
+
+	// Long uploads run in the background, progress is streamed by ShareProgress
+	if req.Async && !scheduledNatively {
+		h.shareInBackground(c, &req)
 		return
 	}
 
 	// Get authenticated client with automatic token refresh
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, h.config().ShareTimeout(req.Provider, req.ServerName))
 	defer cancel()
 
-	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, req.UserID, req.Provider, req.ServerName)
+	client, err := h.shareClient(ctx, &req)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
 		if err.Error() == "token not found" {
 			response.Error(c, errors.ErrTokenNotFound)
+		} else if timeoutErr := timeoutError(ctx, err, operationShare, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
 		} else {
 			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
 		}
@@ -90,8 +210,12 @@ func (h *ShareHandler) Share(c *gin.Context) {
 			if err := xPlatform.CheckAccountStatus(ctx, client); err != nil {
 				h.logger.Error(ctx, err, "account status check failed", "provider", req.Provider, "user_id", req.UserID)
 				// Return a more specific error for account issues
-				if strings.Contains(err.Error(), "suspended") {
-					response.ErrorWithDetail(c, errors.ErrInternalServer, "账户已被暂停，请联系 X (Twitter) 客服解决")
+				if timeoutErr := timeoutError(ctx, err, operationShare, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+					response.Error(c, timeoutErr.AppError())
+				} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationShare); scopeErr != nil {
+					response.Error(c, scopeErr)
+				} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+					response.Error(c, platformErr)
 				} else {
 					response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("账户状态检查失败: %v", err))
 				}
@@ -100,20 +224,41 @@ func (h *ShareHandler) Share(c *gin.Context) {
 		}
 	}
 
+	// Check the link preview before posting, problems are reported as warnings
+	var warnings []string
+	if req.Provider == "x" && req.ValidateLink {
+		if link := platforms.PrimaryLink(req.Content); link != "" {
+			warnings = platforms.CheckLinkCard(ctx, link)
+			for _, warning := range warnings {
+				h.logger.Warn(ctx, "link preview check", "provider", req.Provider, "user_id", req.UserID, "warning", warning)
+			}
+		}
+	}
+
 	// Share content
 	h.logger.Info(ctx, "sharing content", "provider", req.Provider, "user_id", req.UserID)
+	ctx, shareWarnings := types.WithShareWarnings(ctx)
+	ctx, uploads := types.WithUploadCounter(ctx)
+	ctx = h.withMediaSizeLimit(ctx, req.Provider)
 	mediaID, err := platform.Share(ctx, client, &req)
+	metrics.RecordShare(req.Provider, err)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to share content", "provider", req.Provider, "user_id", req.UserID)
 
 		// Provide more specific error messages based on error type
 		errorMsg := err.Error()
-		if strings.Contains(errorMsg, "account suspended") {
-			response.ErrorWithDetail(c, errors.ErrInternalServer, "账户已被暂停，请联系 X (Twitter) 客服解决")
-		} else if strings.Contains(errorMsg, "authentication failed") {
-			response.ErrorWithDetail(c, errors.ErrInternalServer, "认证失败，请重新授权")
-		} else if strings.Contains(errorMsg, "rate limit exceeded") {
-			response.ErrorWithDetail(c, errors.ErrInternalServer, "请求过于频繁，请稍后再试")
+		if timeoutErr := timeoutError(ctx, err, operationShare, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationShare); scopeErr != nil {
+			response.Error(c, scopeErr)
+		} else if stderrors.Is(err, types.ErrMediaTooLarge) {
+			response.Error(c, errors.NewAppError(errors.ErrMediaTooLarge.Code, errorMsg, errors.ErrMediaTooLarge.Status))
+		} else if types.IsProcessingError(err) {
+			response.Error(c, errors.NewAppError(errors.ErrProcessingFailed.Code, errorMsg, errors.ErrProcessingFailed.Status))
+		} else if types.IsValidationError(err) {
+			response.UnprocessableEntity(c, errorMsg)
+		} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+			response.Error(c, platformErr)
 		} else {
 			response.ErrorWithDetail(c, errors.ErrInternalServer, errorMsg)
 		}
@@ -122,16 +267,561 @@ func (h *ShareHandler) Share(c *gin.Context) {
 
 	h.logger.Info(ctx, "content shared successfully", "provider", req.Provider, "user_id", req.UserID)
 
+	// Problems the platform worked around, like media it had to leave out, are surfaced in the message
+	message := "content shared successfully"
+	if platformWarnings := shareWarnings.Messages(); len(platformWarnings) > 0 {
+		for _, warning := range platformWarnings {
+			h.logger.Warn(ctx, "content shared with warning", "provider", req.Provider, "user_id", req.UserID, "warning", warning)
+		}
+		warnings = append(warnings, platformWarnings...)
+		message = "content shared with warnings: " + strings.Join(platformWarnings, "; ")
+	}
+
+	postStatus := h.resolvePostStatus(ctx, platform, client, req.Provider, mediaID)
+	status := postStatus.Status
+	var scheduledAt int64
+	if scheduledNatively {
+		status = types.PostStatusScheduled
+		scheduledAt = req.ScheduledAt
+	}
+
 	shareResponse := types.ShareResponse{
+		Provider:    req.Provider,
+		UserID:      req.UserID,
+		ServerName:  req.ServerName,
+		Content:     req.Content,
+		MediaURL:    req.MediaURL,
+		Tags:        req.Tags,
+		MediaID:     mediaID,
+		Status:      status,
+		Warnings:    warnings,
+		PostRef:     platforms.ParsePostRef(req.Provider, mediaID),
+		ScheduledAt: scheduledAt,
+
+		ProcessingError: postStatus.ProcessingError,
+		UploadedBytes:   uploads.Bytes(),
+	}
+
+	if req.VerifyAfterShare {
+		shareResponse.Verification = h.verifyPost(ctx, platform, client, req.Provider, mediaID, status)
+	}
+
+	response.AddWarning(c, warnings...)
+	response.AddWarning(c, verificationWarnings(req.Provider, shareResponse.Verification)...)
+
+	if req.CallbackURL != "" {
+		// Deliver in the background, detached from the request's cancellation
+		go h.notifyShareCallback(context.WithoutCancel(ctx), req.CallbackURL, shareResponse)
+	}
+
+	response.SuccessWithMessage(c, message, shareResponse)
+}
+
+// BatchShare handles batch share requests
+// @Summary 批量分享内容
+// @Description 一次请求向多个平台分享内容，按priority从小到大依次发布，相同priority的平台并发发布，部分失败时仍返回200并在结果中标明每个平台的成功或失败；on_error为stop_on_error时第一个失败之后的平台被跳过，required平台失败或被跳过时failed为true
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param request body types.BatchShareRequest true "批量分享请求参数"
+// @Success 200 {object} types.APIResponse{data=types.BatchShareResponse} "各平台发布结果"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Router /api/batch-share [post]
+func (h *ShareHandler) BatchShare(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.BatchShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind batch share request")
+		response.BindError(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.config().Timeouts.BatchShare)
+	defer cancel()
+
+	var successCount int
+	var errorCount int
+	var skippedCount int
+	var failed bool
+	var stopped bool
+
+	// Platforms are shared in priority order, those with the same priority in the order listed
+	ordered := slices.Clone(req.Platforms)
+	slices.SortStableFunc(ordered, func(a, b types.BatchSharePlatform) int {
+		return a.Priority - b.Priority
+	})
+
+	// Platforms with the same priority are shared concurrently and a priority starts once the
+	// previous one has finished, so stop_on_error can skip the rest after a failure. Each
+	// goroutine writes only its own slot, results keep the priority order. A failure on one
+	// platform doesn't affect the others unless the batch stops on errors
+	sanitizer := newSanitizer(h.config().Sanitization)
+	platformResults := make([]types.PlatformShareResult, len(ordered))
+	for start := 0; start < len(ordered); {
+		end := start + 1
+		for end < len(ordered) && ordered[end].Priority == ordered[start].Priority {
+			end++
+		}
+
+		if stopped {
+			for i := start; i < end; i++ {
+				skippedCount++
+				failed = failed || ordered[i].Required
+				platformResults[i] = types.PlatformShareResult{
+					Provider: ordered[i].Provider,
+					Error:    "skipped after an earlier platform failed",
+					Status:   types.PostStatusSkipped,
+					Required: ordered[i].Required,
+				}
+			}
+			start = end
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				platformResults[i] = h.batchShareToPlatform(ctx, &req, &ordered[i], sanitizer)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, result := range platformResults[start:end] {
+			if result.Error != "" {
+				errorCount++
+				failed = failed || result.Required
+				stopped = stopped || req.OnError == types.BatchStopOnError
+			} else {
+				successCount++
+			}
+			for _, warning := range result.Warnings {
+				response.AddWarning(c, result.Provider+": "+warning)
+			}
+		}
+		start = end
+	}
+
+	h.logger.Info(ctx, "batch share completed", "user_id", req.UserID, "success_count", successCount, "error_count", errorCount, "skipped_count", skippedCount, "failed", failed)
+
+	batchResponse := types.BatchShareResponse{
+		UserID:       req.UserID,
+		ServerName:   req.ServerName,
+		Platforms:    platformResults,
+		SuccessCount: successCount,
+		ErrorCount:   errorCount,
+		SkippedCount: skippedCount,
+		Failed:       failed,
+	}
+	response.Success(c, batchResponse)
+}
+
+// batchShareToPlatform shares one platform of a batch. It runs on its own goroutine, so a panic
+// is recovered and reported as that platform's failure instead of taking down the server
+func (h *ShareHandler) batchShareToPlatform(ctx context.Context, req *types.BatchShareRequest, platformReq *types.BatchSharePlatform, sanitizer *platforms.Sanitizer) (result types.PlatformShareResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error(ctx, fmt.Errorf("panic: %v", r), "batch share panicked", "provider", platformReq.Provider, "user_id", req.UserID, "stack", string(debug.Stack()))
+			result = types.PlatformShareResult{
+				Provider: platformReq.Provider,
+				Error:    "internal error",
+				Status:   types.PostStatusFailed,
+			}
+		}
+		result.Required = platformReq.Required
+	}()
+
+	// Platforms of a batch share concurrently, each records its own rate limit window
+	ctx = quota.WithRecorder(ctx)
+
+	shareReq := types.ShareRequest{
+		Provider:     platformReq.Provider,
+		UserID:       req.UserID,
+		AccountID:    platformReq.AccountID,
+		ServerName:   req.ServerName,
+		Content:      platformReq.Content,
+		MediaURL:     platformReq.MediaURL,
+		Title:        platformReq.Title,
+		Desc:         platformReq.Desc,
+		Tags:         platformReq.Tags,
+		Privacy:      platformReq.Privacy,
+		MediaAltText: platformReq.MediaAltText,
+		BoardID:      platformReq.BoardID,
+		Subreddit:    platformReq.Subreddit,
+		ChannelID:    platformReq.ChannelID,
+		WebhookURL:   platformReq.WebhookURL,
+		PageID:       platformReq.PageID,
+	}
+	sanitizer.ShareRequest(&shareReq)
+
+	if err := h.resolveMediaURLs(&shareReq); err != nil {
+		return types.PlatformShareResult{
+			Provider: shareReq.Provider,
+			Error:    err.Error(),
+			Status:   types.PostStatusFailed,
+		}
+	}
+	return h.shareToPlatform(ctx, &shareReq, operationBatchShare, h.config().Timeouts.BatchShare)
+}
+
+// shareClient returns the client to share req with: a plain one when the platform posts the request
+// without the user's token, such as a Discord webhook, otherwise an authenticated one
+func (h *ShareHandler) shareClient(ctx context.Context, req *types.ShareRequest) (*http.Client, error) {
+	if platform, err := h.registry.GetPlatform(req.Provider); err == nil {
+		if sharer, ok := platform.(types.AnonymousSharer); ok && sharer.SharesWithoutAuth(req) {
+			return &http.Client{Timeout: h.config().GetProviderTimeout(req.Provider, req.ServerName)}, nil
+		}
+	}
+	return h.tokenManager.CreateAuthenticatedClient(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+}
+
+// checkShareCredentials checks that req can be authenticated when it is posted later, the same way
+// shareClient authenticates it then
+func (h *ShareHandler) checkShareCredentials(ctx context.Context, req *types.ShareRequest) error {
+	if platform, err := h.registry.GetPlatform(req.Provider); err == nil {
+		if sharer, ok := platform.(types.AnonymousSharer); ok && sharer.SharesWithoutAuth(req) {
+			return nil
+		}
+	}
+	return h.tokenManager.CheckCredentials(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+}
+
+// withMediaSizeLimit applies the configured media download limit of a provider, if any
+func (h *ShareHandler) withMediaSizeLimit(ctx context.Context, provider string) context.Context {
+	if maxSize := h.config().MediaMaxSize(provider); maxSize > 0 {
+		return types.WithMediaSizeLimit(ctx, maxSize)
+	}
+	return ctx
+}
+
+// shareToPlatform shares content to a single platform and records the outcome instead of writing
+// an error response, for use by batch sharing and background jobs. operation and deadline describe
+// the time limit ctx is bound by, for the timeout error.
+func (h *ShareHandler) shareToPlatform(ctx context.Context, req *types.ShareRequest, operation string, deadline time.Duration) types.PlatformShareResult {
+	result := types.PlatformShareResult{Provider: req.Provider}
+
+	client, err := h.shareClient(ctx, req)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
+		if timeoutErr := timeoutError(ctx, err, operation, deadline); timeoutErr != nil {
+			result.Error = timeoutErr.Error()
+		} else {
+			result.Error = fmt.Sprintf("authentication failed: %v", err)
+		}
+		return result
+	}
+
+	platform, err := h.registry.GetPlatform(req.Provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		result.Error = "platform not supported"
+		return result
+	}
+
+	if xPlatform, ok := platform.(*platforms.XPlatform); ok {
+		if err := xPlatform.CheckAccountStatus(ctx, client); err != nil {
+			h.logger.Error(ctx, err, "account status check failed", "provider", req.Provider, "user_id", req.UserID)
+			result.Error = fmt.Sprintf("account status check failed: %v", err)
+			return result
+		}
+	}
+
+	h.logger.Info(ctx, "sharing content", "provider", req.Provider, "user_id", req.UserID)
+	ctx, shareWarnings := types.WithShareWarnings(ctx)
+	ctx, uploads := types.WithUploadCounter(ctx)
+	ctx = h.withMediaSizeLimit(ctx, req.Provider)
+	mediaID, err := platform.Share(ctx, client, req)
+	metrics.RecordShare(req.Provider, err)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to share content", "provider", req.Provider, "user_id", req.UserID)
+		if timeoutErr := timeoutError(ctx, err, operation, deadline); timeoutErr != nil {
+			result.Error = timeoutErr.Error()
+		} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationShare); scopeErr != nil {
+			result.Error = scopeErr.Message
+		} else {
+			result.Error = err.Error()
+		}
+		if appErr := platformAppError(ctx, err); appErr != nil && !appErr.ResetAt.IsZero() {
+			result.ResetAt = appErr.ResetAt.Unix()
+		}
+		stderrors.As(err, &result.ProcessingError)
+		result.Status = types.PostStatusFailed
+		return result
+	}
+
+	result.MediaID = mediaID
+	result.Warnings = shareWarnings.Messages()
+	result.UploadedBytes = uploads.Bytes()
+	result.URL = platforms.PostURL(req.Provider, mediaID)
+	result.PostRef = platforms.ParsePostRef(req.Provider, mediaID)
+	postStatus := h.resolvePostStatus(ctx, platform, client, req.Provider, mediaID)
+	result.Status = postStatus.Status
+	result.ProcessingError = postStatus.ProcessingError
+
+	return result
+}
+
+// DeletePost handles post deletion requests
+// @Summary 删除已发布内容
+// @Description 删除通过本服务分享的内容，Instagram和TikTok的API不支持删除，会返回PLATFORM_NOT_SUPPORTED
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param request body types.DeletePostRequest true "删除内容请求参数"
+// @Success 200 {object} types.APIResponse{data=types.DeletePostResponse} "删除成功"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误或平台不支持删除"
+// @Failure 401 {object} types.ErrorResponse "未授权"
+// @Failure 403 {object} types.ErrorResponse "token缺少该操作所需的权限，错误信息中列出需要的scope"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Failure 504 {object} types.ErrorResponse "操作超时，返回超时的操作及其时限"
+// @Router /api/delete-post [post]
+func (h *ShareHandler) DeletePost(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.DeletePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind delete post request")
+		response.BindError(c, err)
+		return
+	}
+
+	// Get authenticated client with automatic token refresh
+	ctx, cancel := context.WithTimeout(ctx, h.config().ShareTimeout(req.Provider, req.ServerName))
+	defer cancel()
+
+	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
+		if err.Error() == "token not found" {
+			response.Error(c, errors.ErrTokenNotFound)
+		} else if timeoutErr := timeoutError(ctx, err, operationDeletePost, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
+		}
+		return
+	}
+
+	// Get platform implementation
+	platform, err := h.registry.GetPlatform(req.Provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		response.Error(c, errors.ErrPlatformNotSupported)
+		return
+	}
+
+	h.logger.Info(ctx, "deleting post", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+	if err := platform.DeletePost(ctx, client, req.MediaID); err != nil {
+		h.logger.Error(ctx, err, "failed to delete post", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+		if stderrors.Is(err, types.ErrOperationNotSupported) {
+			response.Error(c, errors.NewAppError(errors.ErrPlatformNotSupported.Code, err.Error(), errors.ErrPlatformNotSupported.Status))
+		} else if timeoutErr := timeoutError(ctx, err, operationDeletePost, h.config().ShareTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationDelete); scopeErr != nil {
+			response.Error(c, scopeErr)
+		} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+			response.Error(c, platformErr)
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		}
+		return
+	}
+
+	h.logger.Info(ctx, "post deleted successfully", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+
+	response.SuccessWithMessage(c, "post deleted successfully", types.DeletePostResponse{
 		Provider:   req.Provider,
 		UserID:     req.UserID,
 		ServerName: req.ServerName,
-		Content:    req.Content,
-		MediaURL:   req.MediaURL,
-		Tags:       req.Tags,
-		MediaID:    mediaID,
+		MediaID:    req.MediaID,
+		Deleted:    true,
+	})
+}
+
+// schedulesNatively reports whether a provider publishes scheduled posts itself
+func (h *ShareHandler) schedulesNatively(provider string) bool {
+	platform, err := h.registry.GetPlatform(provider)
+	if err != nil {
+		return false
 	}
-	response.SuccessWithMessage(c, "content shared successfully", shareResponse)
+	scheduler, ok := platform.(types.NativeScheduler)
+	return ok && scheduler.SchedulesNatively()
+}
+
+// resolvePostStatus determines whether a shared post is live.
+// Platforms that publish synchronously are published as soon as Share returns;
+// for asynchronous platforms the status is queried and assumed processing if unknown.
+func (h *ShareHandler) resolvePostStatus(ctx context.Context, platform types.Platform, client *http.Client, provider, mediaID string) types.PostStatusDetail {
+	checker, ok := platform.(types.PostStatusChecker)
+	if !ok {
+		return types.PostStatusDetail{Status: types.PostStatusPublished}
+	}
+
+	if mediaID == "" {
+		return types.PostStatusDetail{Status: types.PostStatusProcessing}
+	}
+
+	status, err := checker.GetPostStatus(ctx, client, mediaID)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to get post status", "provider", provider, "media_id", mediaID, "error", err)
+		return types.PostStatusDetail{Status: types.PostStatusProcessing}
+	}
+
+	if status.ProcessingError != nil {
+		h.logger.Warn(ctx, "platform failed to process post", "provider", provider, "media_id", mediaID, "reason", status.ProcessingError.Reason)
+	}
+	return status
+}
+
+// GetPostStatus handles post status requests
+// @Summary 查询内容发布状态
+// @Description 查询已分享内容在平台上的发布状态（published/processing/scheduled/failed），用于异步处理的平台轮询
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param request body types.PostStatusRequest true "发布状态请求参数"
+// @Success 200 {object} types.APIResponse{data=types.PostStatusResponse} "发布状态"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 401 {object} types.ErrorResponse "未授权"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Failure 504 {object} types.ErrorResponse "操作超时，返回超时的操作及其时限"
+// @Router /api/post-status [post]
+func (h *ShareHandler) GetPostStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.PostStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind post status request")
+		response.BindError(c, err)
+		return
+	}
+
+	// Get authenticated client with automatic token refresh
+	ctx, cancel := context.WithTimeout(ctx, h.config().ReadTimeout(req.Provider, req.ServerName))
+	defer cancel()
+
+	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
+		if err.Error() == "token not found" {
+			response.Error(c, errors.ErrTokenNotFound)
+		} else if timeoutErr := timeoutError(ctx, err, operationPostStatus, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
+		}
+		return
+	}
+
+	// Get platform implementation
+	platform, err := h.registry.GetPlatform(req.Provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		response.Error(c, errors.ErrPlatformNotSupported)
+		return
+	}
+
+	status := types.PostStatusDetail{Status: types.PostStatusPublished}
+	if checker, ok := platform.(types.PostStatusChecker); ok {
+		status, err = checker.GetPostStatus(ctx, client, req.MediaID)
+		if err != nil {
+			h.logger.Error(ctx, err, "failed to get post status", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+			if timeoutErr := timeoutError(ctx, err, operationPostStatus, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+				response.Error(c, timeoutErr.AppError())
+			} else {
+				response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+			}
+			return
+		}
+	}
+
+	response.Success(c, types.PostStatusResponse{
+		Provider:   req.Provider,
+		UserID:     req.UserID,
+		ServerName: req.ServerName,
+		MediaID:    req.MediaID,
+		Status:     status.Status,
+
+		ProcessingError: status.ProcessingError,
+	})
+}
+
+// GetPost handles single post requests
+// @Summary 获取单个帖子
+// @Description 按分享返回的媒体ID获取帖子的当前状态，包括内容、媒体、链接和统计信息
+// @Tags 统计
+// @Accept json
+// @Produce json
+// @Param request body types.PostRequest true "帖子请求参数"
+// @Success 200 {object} types.APIResponse{data=types.PostResponse} "帖子信息"
+// @Failure 400 {object} types.ErrorResponse "请求参数错误"
+// @Failure 401 {object} types.ErrorResponse "未授权"
+// @Failure 403 {object} types.ErrorResponse "token缺少该操作所需的权限，错误信息中列出需要的scope"
+// @Failure 404 {object} types.ErrorResponse "帖子不存在"
+// @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Failure 504 {object} types.ErrorResponse "操作超时，返回超时的操作及其时限"
+// @Router /api/post [post]
+func (h *ShareHandler) GetPost(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req types.PostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, err, "failed to bind post request")
+		response.BindError(c, err)
+		return
+	}
+
+	// Get authenticated client with automatic token refresh
+	ctx, cancel := context.WithTimeout(ctx, h.config().ReadTimeout(req.Provider, req.ServerName))
+	defer cancel()
+
+	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
+		if err.Error() == "token not found" {
+			response.Error(c, errors.ErrTokenNotFound)
+		} else if timeoutErr := timeoutError(ctx, err, operationGetPost, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
+		}
+		return
+	}
+
+	// Get platform implementation
+	platform, err := h.registry.GetPlatform(req.Provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		response.Error(c, errors.ErrPlatformNotSupported)
+		return
+	}
+
+	h.logger.Info(ctx, "getting post", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+	post, err := platform.GetPost(ctx, client, req.MediaID)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to get post", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+		if timeoutErr := timeoutError(ctx, err, operationGetPost, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationStats); scopeErr != nil {
+			response.Error(c, scopeErr)
+		} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+			response.Error(c, platformErr)
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		}
+		return
+	}
+
+	response.AddWarning(c, statsWarnings(req.Provider, post.Stats)...)
+	response.Success(c, types.PostResponse{
+		Provider:   req.Provider,
+		UserID:     req.UserID,
+		ServerName: req.ServerName,
+		Post:       post,
+	})
 }
 
 // GetStats handles statistics requests
@@ -144,7 +834,9 @@ func (h *ShareHandler) Share(c *gin.Context) {
 // @Success 200 {object} types.APIResponse{data=types.StatsResponse} "统计信息"
 // @Failure 400 {object} types.ErrorResponse "请求参数错误"
 // @Failure 401 {object} types.ErrorResponse "未授权"
+// @Failure 403 {object} types.ErrorResponse "token缺少该操作所需的权限，错误信息中列出需要的scope"
 // @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Failure 504 {object} types.ErrorResponse "操作超时，返回超时的操作及其时限"
 // @Router /api/stats [post]
 func (h *ShareHandler) GetStats(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -152,19 +844,26 @@ func (h *ShareHandler) GetStats(c *gin.Context) {
 	var req types.StatsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind stats request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
+		return
+	}
+
+	if req.Provider != "x" && req.CheckReach {
+		response.UnprocessableEntity(c, "check_reach is only supported for x")
 		return
 	}
 
 	// Get authenticated client with automatic token refresh
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, h.config().ReadTimeout(req.Provider, req.ServerName))
 	defer cancel()
 
-	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, req.UserID, req.Provider, req.ServerName)
+	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
 		if err.Error() == "token not found" {
 			response.Error(c, errors.ErrTokenNotFound)
+		} else if timeoutErr := timeoutError(ctx, err, operationStats, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
 		} else {
 			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
 		}
@@ -179,16 +878,42 @@ func (h *ShareHandler) GetStats(c *gin.Context) {
 		return
 	}
 
+	// Serve recently fetched stats from the cache to spare the platform's rate limits
+	stats, cached := h.cachedStats(ctx, &req)
+	if cached {
+		h.logger.Info(ctx, "statistics served from cache", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
+		h.respondStats(ctx, c, &req, stats, true)
+		return
+	}
+
 	// Get statistics
 	h.logger.Info(ctx, "getting statistics", "provider", req.Provider, "user_id", req.UserID, "media_id", req.MediaID)
-	stats, err := platform.GetStats(ctx, client, req.MediaID)
+	stats, err = platform.GetStats(ctx, client, req.MediaID)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get statistics", "provider", req.Provider, "user_id", req.UserID)
-		response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		if timeoutErr := timeoutError(ctx, err, operationStats, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationStats); scopeErr != nil {
+			response.Error(c, scopeErr)
+		} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+			response.Error(c, platformErr)
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		}
 		return
 	}
 
 	h.logger.Info(ctx, "statistics retrieved successfully", "provider", req.Provider, "user_id", req.UserID)
+	h.cacheStats(ctx, &req, stats)
+	h.respondStats(ctx, c, &req, stats, false)
+}
+
+// respondStats writes the stats response, running the reach check if requested
+func (h *ShareHandler) respondStats(ctx context.Context, c *gin.Context, req *types.StatsRequest, stats types.StatsData, cached bool) {
+	var warnings []string
+	if req.CheckReach {
+		warnings = h.checkReach(ctx, req, stats)
+	}
 
 	statsResponse := types.StatsResponse{
 		Provider:   req.Provider,
@@ -196,7 +921,11 @@ func (h *ShareHandler) GetStats(c *gin.Context) {
 		ServerName: req.ServerName,
 		MediaID:    req.MediaID,
 		Stats:      stats,
+		Cached:     cached,
+		Warnings:   warnings,
 	}
+	response.AddWarning(c, statsWarnings(req.Provider, stats)...)
+	response.AddWarning(c, warnings...)
 	response.Success(c, statsResponse)
 }
 
@@ -210,7 +939,9 @@ func (h *ShareHandler) GetStats(c *gin.Context) {
 // @Success 200 {object} types.APIResponse{data=types.GetRecentPostsResponse} "获取成功"
 // @Failure 400 {object} types.ErrorResponse "请求参数错误"
 // @Failure 401 {object} types.ErrorResponse "未授权"
+// @Failure 403 {object} types.ErrorResponse "token缺少该操作所需的权限，错误信息中列出需要的scope"
 // @Failure 500 {object} types.ErrorResponse "服务器内部错误"
+// @Failure 504 {object} types.ErrorResponse "操作超时，返回超时的操作及其时限"
 // @Router /api/recent-posts [post]
 func (h *ShareHandler) GetRecentPosts(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -218,7 +949,7 @@ func (h *ShareHandler) GetRecentPosts(c *gin.Context) {
 	var req types.GetRecentPostsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind recent posts request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
@@ -228,14 +959,16 @@ func (h *ShareHandler) GetRecentPosts(c *gin.Context) {
 	}
 
 	// Get authenticated client with automatic token refresh
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, h.config().ReadTimeout(req.Provider, req.ServerName))
 	defer cancel()
 
-	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, req.UserID, req.Provider, req.ServerName)
+	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", req.Provider, "user_id", req.UserID)
 		if err.Error() == "token not found" {
 			response.Error(c, errors.ErrTokenNotFound)
+		} else if timeoutErr := timeoutError(ctx, err, operationRecentPosts, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
 		} else {
 			response.ErrorWithDetail(c, errors.ErrInternalServer, fmt.Sprintf("authentication failed: %v", err))
 		}
@@ -255,10 +988,20 @@ func (h *ShareHandler) GetRecentPosts(c *gin.Context) {
 	posts, err := platform.GetRecentPosts(ctx, client, req.Limit, req.StartTime, req.EndTime)
 	if err != nil {
 		h.logger.Error(ctx, err, "failed to get recent posts", "provider", req.Provider, "user_id", req.UserID)
-		response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		if timeoutErr := timeoutError(ctx, err, operationRecentPosts, h.config().ReadTimeout(req.Provider, req.ServerName)); timeoutErr != nil {
+			response.Error(c, timeoutErr.AppError())
+		} else if scopeErr := h.scopeError(err, req.Provider, req.ServerName, config.ScopeOperationRecentPosts); scopeErr != nil {
+			response.Error(c, scopeErr)
+		} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+			response.Error(c, platformErr)
+		} else {
+			response.ErrorWithDetail(c, errors.ErrInternalServer, err.Error())
+		}
 		return
 	}
 
+	posts = filterPostsByMediaType(posts, req.MediaTypeFilter)
+
 	h.logger.Info(ctx, "recent posts retrieved successfully", "provider", req.Provider, "user_id", req.UserID, "count", len(posts))
 
 	recentPostsResponse := types.GetRecentPostsResponse{
@@ -268,9 +1011,66 @@ func (h *ShareHandler) GetRecentPosts(c *gin.Context) {
 		Posts:      posts,
 		Total:      len(posts),
 	}
+	response.AddWarning(c, postsWarnings(req.Provider, posts)...)
 	response.Success(c, recentPostsResponse)
 }
 
+// getPlatformRecentPosts gets recent posts from a single platform for batch requests,
+// recording failures in the result instead of writing an error response. It runs on its own
+// goroutine, so a panic is recovered and reported as that platform's failure instead of taking down the server
+func (h *ShareHandler) getPlatformRecentPosts(ctx context.Context, userID, serverName, provider string, limit int, startTime, endTime int64) (result types.PlatformPosts) {
+	result = types.PlatformPosts{
+		Provider:   provider,
+		UserID:     userID,
+		ServerName: serverName,
+		Posts:      []types.Post{},
+	}
+
+	base := result
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error(ctx, fmt.Errorf("panic: %v", r), "batch recent posts panicked", "provider", provider, "user_id", userID, "stack", string(debug.Stack()))
+			result = failPlatformPosts(base, errors.NewAppError(errors.ErrInternalServer.Code, "internal error", errors.ErrInternalServer.Status))
+		}
+	}()
+
+	// Platforms of a batch are fetched concurrently, each records its own rate limit window
+	ctx = quota.WithRecorder(ctx)
+
+	// Get authenticated client for this platform
+	client, err := h.tokenManager.CreateAuthenticatedClient(ctx, userID, provider, serverName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to create authenticated client", "provider", provider, "user_id", userID)
+		return failPlatformPosts(result, authAppError(timeoutError(ctx, err, operationBatchRecentPosts, h.config().Timeouts.BatchRead), err))
+	}
+
+	// Get platform implementation
+	platform, err := h.registry.GetPlatform(provider)
+	if err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", provider)
+		return failPlatformPosts(result, errors.NewAppError(errors.ErrPlatformNotSupported.Code, "platform not supported", errors.ErrPlatformNotSupported.Status))
+	}
+
+	// Get recent posts for this platform
+	h.logger.Info(ctx, "getting recent posts", "provider", provider, "user_id", userID, "limit", limit)
+	posts, err := platform.GetRecentPosts(ctx, client, limit, startTime, endTime)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to get recent posts", "provider", provider, "user_id", userID)
+		if timeoutErr := timeoutError(ctx, err, operationBatchRecentPosts, h.config().Timeouts.BatchRead); timeoutErr != nil {
+			return failPlatformPosts(result, timeoutErr.AppError())
+		} else if scopeErr := h.scopeError(err, provider, serverName, config.ScopeOperationRecentPosts); scopeErr != nil {
+			return failPlatformPosts(result, scopeErr)
+		} else if platformErr := platformAppError(ctx, err); platformErr != nil {
+			return failPlatformPosts(result, platformErr)
+		}
+		return failPlatformPosts(result, errors.NewAppError(errors.ErrUpstream.Code, err.Error(), errors.ErrUpstream.Status))
+	}
+
+	result.Posts = posts
+	result.Total = len(posts)
+	return result
+}
+
 // BatchGetRecentPosts handles batch recent posts requests
 // @Summary 批量获取最近发布的内容
 // @Description 批量获取多个平台最近发布的内容列表，支持定时后驱
@@ -289,7 +1089,7 @@ func (h *ShareHandler) BatchGetRecentPosts(c *gin.Context) {
 	var req types.BatchGetRecentPostsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, err, "failed to bind batch recent posts request")
-		response.BadRequest(c, "invalid request format")
+		response.BindError(c, err)
 		return
 	}
 
@@ -301,75 +1101,37 @@ func (h *ShareHandler) BatchGetRecentPosts(c *gin.Context) {
 	}
 
 	// Get authenticated client with automatic token refresh
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, h.config().Timeouts.BatchRead)
 	defer cancel()
 
-	var platformResults []types.PlatformPosts
+	// Fetch platforms concurrently, each goroutine writes only its own slot so
+	// results keep the request's platform order regardless of completion order
+	platformResults := make([]types.PlatformPosts, len(req.Platforms))
+	var wg sync.WaitGroup
+	for i, platformReq := range req.Platforms {
+		wg.Add(1)
+		go func(i int, provider string, limit int) {
+			defer wg.Done()
+			platformResults[i] = h.getPlatformRecentPosts(ctx, req.UserID, req.ServerName, provider, limit, req.StartTime, req.EndTime)
+		}(i, platformReq.Provider, platformReq.Limit)
+	}
+	wg.Wait()
+
 	var totalPosts int
 	var successCount int
 	var errorCount int
-
-	// Process each platform
-	for _, platformReq := range req.Platforms {
-		// Get authenticated client for this platform
-		client, err := h.tokenManager.CreateAuthenticatedClient(ctx, req.UserID, platformReq.Provider, req.ServerName)
-		if err != nil {
-			h.logger.Error(ctx, err, "failed to create authenticated client", "provider", platformReq.Provider, "user_id", req.UserID)
-			platformResults = append(platformResults, types.PlatformPosts{
-				Provider:   platformReq.Provider,
-				UserID:     req.UserID,
-				ServerName: req.ServerName,
-				Posts:      []types.Post{},
-				Total:      0,
-				Error:      fmt.Sprintf("authentication failed: %v", err),
-			})
-			errorCount++
-			continue
-		}
-
-		// Get platform implementation
-		platform, err := h.registry.GetPlatform(platformReq.Provider)
-		if err != nil {
-			h.logger.Error(ctx, err, "platform not found", "provider", platformReq.Provider)
-			platformResults = append(platformResults, types.PlatformPosts{
-				Provider:   platformReq.Provider,
-				UserID:     req.UserID,
-				ServerName: req.ServerName,
-				Posts:      []types.Post{},
-				Total:      0,
-				Error:      "platform not supported",
-			})
+	var totals types.EngagementTotals
+	for _, result := range platformResults {
+		if result.Error != "" {
 			errorCount++
 			continue
 		}
-
-		// Get recent posts for this platform
-		h.logger.Info(ctx, "getting recent posts", "provider", platformReq.Provider, "user_id", req.UserID, "limit", platformReq.Limit)
-		posts, err := platform.GetRecentPosts(ctx, client, platformReq.Limit, req.StartTime, req.EndTime)
-		if err != nil {
-			h.logger.Error(ctx, err, "failed to get recent posts", "provider", platformReq.Provider, "user_id", req.UserID)
-			platformResults = append(platformResults, types.PlatformPosts{
-				Provider:   platformReq.Provider,
-				UserID:     req.UserID,
-				ServerName: req.ServerName,
-				Posts:      []types.Post{},
-				Total:      0,
-				Error:      err.Error(),
-			})
-			errorCount++
-			continue
-		}
-
-		// Success
-		platformResults = append(platformResults, types.PlatformPosts{
-			Provider:   platformReq.Provider,
-			UserID:     req.UserID,
-			ServerName: req.ServerName,
-			Posts:      posts,
-			Total:      len(posts),
-		})
-		totalPosts += len(posts)
+		totalPosts += result.Total
 		successCount++
+		response.AddWarning(c, postsWarnings(result.Provider, result.Posts)...)
+		for _, post := range result.Posts {
+			totals.Add(post.Stats)
+		}
 	}
 
 	h.logger.Info(ctx, "batch recent posts completed", "user_id", req.UserID, "success_count", successCount, "error_count", errorCount, "total_posts", totalPosts)
@@ -381,6 +1143,36 @@ func (h *ShareHandler) BatchGetRecentPosts(c *gin.Context) {
 		TotalPosts:   totalPosts,
 		SuccessCount: successCount,
 		ErrorCount:   errorCount,
+		Totals:       totals,
 	}
 	response.Success(c, batchResponse)
 }
+
+// sameInstance reports whether two instance URLs point at the same https host
+func sameInstance(a, b string) bool {
+	parsedA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	parsedB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return parsedA.Scheme == "https" && strings.EqualFold(parsedA.Host, parsedB.Host)
+}
+
+// filterPostsByMediaType keeps the posts of the given media type, all posts when it is empty.
+// Platforms differ in case (Instagram reports VIDEO), so types are compared case-insensitively.
+func filterPostsByMediaType(posts []types.Post, mediaType string) []types.Post {
+	if mediaType == "" {
+		return posts
+	}
+
+	filtered := make([]types.Post, 0, len(posts))
+	for _, post := range posts {
+		if strings.EqualFold(post.MediaType, mediaType) {
+			filtered = append(filtered, post)
+		}
+	}
+	return filtered
+}
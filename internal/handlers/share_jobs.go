@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/oauth"
+	"social/internal/types"
+	"social/pkg/errors"
+	"social/pkg/quota"
+	"social/pkg/response"
+)
+
+const (
+	// shareJobRetention is how long a finished background share stays available to progress streams
+	shareJobRetention = 10 * time.Minute
+	// shareProgressKeepalive is how often an idle progress stream sends a comment, so proxies
+	// don't close it while a platform processes the upload
+	shareProgressKeepalive = 15 * time.Second
+)
+
+// shareJob is a share running in the background. Every change closes and replaces changed, which
+// wakes up the progress streams waiting on it.
+type shareJob struct {
+	mu       sync.Mutex
+	progress types.ShareProgress
+	result   *types.PlatformShareResult
+	changed  chan struct{}
+}
+
+// setProgress records how many media bytes have been uploaded out of the total
+func (j *shareJob) setProgress(sent, total int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress.UploadedBytes = sent
+	j.progress.TotalBytes = total
+	j.progress.Percent = int(sent * 100 / total)
+	j.notify()
+}
+
+// finish records the outcome of the share
+func (j *shareJob) finish(result types.PlatformShareResult) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.result = &result
+	j.notify()
+}
+
+// notify wakes up the waiting streams, the caller holds mu
+func (j *shareJob) notify() {
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// snapshot returns the current progress, the result once finished, and a channel closed on the next change
+func (j *shareJob) snapshot() (types.ShareProgress, *types.PlatformShareResult, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.progress, j.result, j.changed
+}
+
+// shareJobs tracks the background shares of this instance by job ID
+type shareJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*shareJob
+}
+
+// newShareJobs creates an empty job tracker
+func newShareJobs() *shareJobs {
+	return &shareJobs{jobs: make(map[string]*shareJob)}
+}
+
+// add starts tracking a job
+func (s *shareJobs) add(jobID string) *shareJob {
+	job := &shareJob{
+		progress: types.ShareProgress{JobID: jobID},
+		changed:  make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[jobID] = job
+	return job
+}
+
+// get returns a tracked job
+func (s *shareJobs) get(jobID string) (*shareJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[jobID]
+	return job, ok
+}
+
+// finish records the outcome of a job and forgets it after shareJobRetention
+func (s *shareJobs) finish(jobID string, job *shareJob, result types.PlatformShareResult) {
+	job.finish(result)
+	time.AfterFunc(shareJobRetention, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.jobs, jobID)
+	})
+}
+
+// shareInBackground starts sharing req as a background job and responds with its job ID right away.
+// Problems found without calling the platform are still returned as errors.
+func (h *ShareHandler) shareInBackground(c *gin.Context, req *types.ShareRequest) {
+	ctx := c.Request.Context()
+
+	if _, err := h.registry.GetPlatform(req.Provider); err != nil {
+		h.logger.Error(ctx, err, "platform not found", "provider", req.Provider)
+		response.Error(c, errors.ErrPlatformNotSupported)
+		return
+	}
+
+	if err := h.checkShareCredentials(ctx, req); err != nil {
+		h.logger.Error(ctx, err, "token not found for background share", "provider", req.Provider, "user_id", req.UserID)
+		response.Error(c, errors.ErrTokenNotFound)
+		return
+	}
+
+	jobID, err := oauth.RandStringURLSafe(16)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to generate job id")
+		response.InternalServerError(c, "failed to generate job id")
+		return
+	}
+
+	// The job outlives the request, it keeps the request's values like the request ID but not its cancellation
+	job := h.jobs.add(jobID)
+	go h.runShareJob(context.WithoutCancel(ctx), jobID, job, *req)
+
+	h.logger.Info(ctx, "share queued", "job_id", jobID, "provider", req.Provider, "user_id", req.UserID)
+
+	response.SuccessWithMessage(c, "share queued", types.ShareResponse{
+		Provider:   req.Provider,
+		UserID:     req.UserID,
+		ServerName: req.ServerName,
+		Content:    req.Content,
+		MediaURL:   req.MediaURL,
+		Tags:       req.Tags,
+		Status:     types.PostStatusQueued,
+		JobID:      jobID,
+	})
+}
+
+// runShareJob shares a background job, records its result and delivers the callback, if any
+func (h *ShareHandler) runShareJob(ctx context.Context, jobID string, job *shareJob, req types.ShareRequest) {
+	result := h.shareJobToPlatform(ctx, job, &req)
+	h.jobs.finish(jobID, job, result)
+
+	if result.Error != "" {
+		h.logger.Warn(ctx, "background share failed", "job_id", jobID, "provider", req.Provider, "user_id", req.UserID, "error", result.Error)
+		return
+	}
+	h.logger.Info(ctx, "background share finished", "job_id", jobID, "provider", req.Provider, "user_id", req.UserID, "media_id", result.MediaID)
+
+	if req.CallbackURL != "" {
+		h.notifyShareCallback(ctx, req.CallbackURL, types.ShareResponse{
+			Provider:   req.Provider,
+			UserID:     req.UserID,
+			ServerName: req.ServerName,
+			Content:    req.Content,
+			MediaURL:   req.MediaURL,
+			Tags:       req.Tags,
+			MediaID:    result.MediaID,
+			Status:     result.Status,
+			Warnings:   result.Warnings,
+			PostRef:    result.PostRef,
+			JobID:      jobID,
+
+			ProcessingError: result.ProcessingError,
+			UploadedBytes:   result.UploadedBytes,
+		})
+	}
+}
+
+// shareJobToPlatform shares a background job within the provider's share timeout, reporting upload
+// progress to the job. A panic is recovered and reported as the job's failure.
+func (h *ShareHandler) shareJobToPlatform(ctx context.Context, job *shareJob, req *types.ShareRequest) (result types.PlatformShareResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error(ctx, fmt.Errorf("panic: %v", r), "background share panicked", "provider", req.Provider, "user_id", req.UserID, "stack", string(debug.Stack()))
+			result = types.PlatformShareResult{
+				Provider: req.Provider,
+				Error:    "internal error",
+				Status:   types.PostStatusFailed,
+			}
+		}
+	}()
+
+	deadline := h.config().ShareTimeout(req.Provider, req.ServerName)
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	ctx = quota.WithRecorder(ctx)
+	ctx = types.WithUploadProgress(ctx, job.setProgress)
+	return h.shareToPlatform(ctx, req, operationShare, deadline)
+}
+
+// ShareProgress streams the progress of a background share job
+// @Summary 获取后台分享任务的进度
+// @Description 以Server-Sent Events推送async分享任务的进度：progress事件为媒体上传进度（YouTube、TikTok、X上传媒体时更新），任务结束时发送done事件（数据为发布结果，失败时error不为空）后关闭连接。任务保存在接受该分享的实例内存中，结束10分钟后过期，多实例部署时须将请求路由到同一实例
+// @Tags 分享
+// @Produce text/event-stream
+// @Param job_id path string true "后台分享任务ID"
+// @Success 200 {object} types.ShareProgress "progress事件，done事件的数据为types.PlatformShareResult"
+// @Failure 404 {object} types.ErrorResponse "任务不存在或已过期"
+// @Router /api/share/progress/{job_id} [get]
+func (h *ShareHandler) ShareProgress(c *gin.Context) {
+	job, ok := h.jobs.get(c.Param("job_id"))
+	if !ok {
+		response.NotFound(c, "share job not found")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	// Keep proxies like nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+
+	keepalive := time.NewTicker(shareProgressKeepalive)
+	defer keepalive.Stop()
+
+	var last *types.ShareProgress
+	for {
+		progress, result, changed := job.snapshot()
+		if last == nil || progress != *last {
+			c.SSEvent("progress", progress)
+			last = &progress
+		}
+		if result != nil {
+			c.SSEvent("done", result)
+			c.Writer.Flush()
+			return
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-changed:
+		case <-keepalive.C:
+			_, _ = c.Writer.WriteString(": keepalive\n\n")
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/config"
+	"social/internal/platforms"
+	"social/internal/storage"
+	"social/internal/types"
+	"social/pkg/logger"
+	"social/pkg/validator"
+)
+
+// orderedPlatform wraps a registered platform and returns its recent posts only once release is
+// closed, closing done when it has returned, so tests control the order platforms finish in
+type orderedPlatform struct {
+	types.Platform
+	release <-chan struct{}
+	done    chan struct{}
+}
+
+func (p *orderedPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	defer close(p.done)
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return []types.Post{{ID: p.GetName() + "-post"}}, nil
+}
+
+// newBatchRecentPostsRouter serves BatchGetRecentPosts from registry, with every provider sandboxed
+// so no stored token is needed
+func newBatchRecentPostsRouter(t *testing.T, registry *platforms.Registry) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	if err := validator.RegisterProvider(registry.Resolve); err != nil {
+		t.Fatalf("RegisterProvider: %v", err)
+	}
+
+	cfg := &config.Config{
+		Sandbox:  config.SandboxConfig{Enabled: true},
+		Timeouts: config.TimeoutsConfig{BatchRead: 5 * time.Second},
+	}
+	handler := NewShareHandler(config.NewAtomicProvider(cfg), storage.NewMemoryStorage(), registry, logger.NewLogger(logger.Config{Level: "error"}))
+
+	router := gin.New()
+	router.POST("/api/batch-recent-posts", handler.BatchGetRecentPosts)
+	return router
+}
+
+// postBatchRecentPosts requests the recent posts of providers and returns the response recorder
+func postBatchRecentPosts(router *gin.Engine, providers ...string) *httptest.ResponseRecorder {
+	var platformsJSON []string
+	for _, provider := range providers {
+		platformsJSON = append(platformsJSON, `{"provider":"`+provider+`"}`)
+	}
+	body := `{"user_id":"user123","server_name":"myapp","platforms":[` + strings.Join(platformsJSON, ",") + `]}`
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/batch-recent-posts", strings.NewReader(body)))
+	return recorder
+}
+
+// decodeBatchRecentPosts decodes a successful batch recent posts response
+func decodeBatchRecentPosts(t *testing.T, recorder *httptest.ResponseRecorder) types.BatchGetRecentPostsResponse {
+	t.Helper()
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", recorder.Code, recorder.Body.String())
+	}
+
+	var resp struct {
+		Data types.BatchGetRecentPostsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp.Data
+}
+
+// replaceWithOrdered swaps the named platform for an orderedPlatform waiting on release
+func replaceWithOrdered(t *testing.T, registry *platforms.Registry, name string, release <-chan struct{}) *orderedPlatform {
+	t.Helper()
+	platform, err := registry.GetPlatform(name)
+	if err != nil {
+		t.Fatalf("GetPlatform(%s): %v", name, err)
+	}
+	stub := &orderedPlatform{Platform: platform, release: release, done: make(chan struct{})}
+	if err := registry.Replace(stub); err != nil {
+		t.Fatalf("Replace(%s): %v", name, err)
+	}
+	return stub
+}
+
+func TestBatchGetRecentPostsKeepsRequestOrder(t *testing.T) {
+	registry := platforms.NewRegistry()
+
+	// Each platform waits for the next one in the request to finish, so they complete in reverse order
+	providers := []string{"x", "youtube", "tiktok", "mastodon"}
+	released := make(chan struct{})
+	close(released)
+	release := (<-chan struct{})(released)
+	for i := len(providers) - 1; i >= 0; i-- {
+		release = replaceWithOrdered(t, registry, providers[i], release).done
+	}
+
+	resp := decodeBatchRecentPosts(t, postBatchRecentPosts(newBatchRecentPostsRouter(t, registry), providers...))
+
+	if len(resp.Platforms) != len(providers) {
+		t.Fatalf("got %d platforms, want %d", len(resp.Platforms), len(providers))
+	}
+	for i, provider := range providers {
+		result := resp.Platforms[i]
+		if result.Provider != provider {
+			t.Errorf("platforms[%d].provider = %s, want %s", i, result.Provider, provider)
+		}
+		if result.Error != "" {
+			t.Errorf("platforms[%d] failed: %s", i, result.Error)
+		}
+	}
+	if resp.SuccessCount != len(providers) {
+		t.Errorf("success_count = %d, want %d", resp.SuccessCount, len(providers))
+	}
+}
+
+func TestBatchGetRecentPostsValidatesProviders(t *testing.T) {
+	registry := platforms.NewRegistry()
+	released := make(chan struct{})
+	close(released)
+	replaceWithOrdered(t, registry, "x", released)
+	router := newBatchRecentPostsRouter(t, registry)
+
+	// Aliases are canonicalized before tokens and platforms are looked up
+	resp := decodeBatchRecentPosts(t, postBatchRecentPosts(router, "twitter"))
+	if len(resp.Platforms) != 1 || resp.Platforms[0].Provider != "x" {
+		t.Fatalf("platforms = %+v, want a single x result", resp.Platforms)
+	}
+	if resp.Platforms[0].Error != "" {
+		t.Fatalf("twitter alias failed: %s", resp.Platforms[0].Error)
+	}
+
+	if recorder := postBatchRecentPosts(router, "x", "bogus"); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("unknown provider status = %d, want 400, body %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+// panickingPlatform wraps a registered platform and panics when asked for recent posts
+type panickingPlatform struct {
+	types.Platform
+}
+
+func (p *panickingPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	panic("recent posts exploded")
+}
+
+func TestBatchGetRecentPostsRecoversPlatformPanic(t *testing.T) {
+	registry := platforms.NewRegistry()
+	released := make(chan struct{})
+	close(released)
+	replaceWithOrdered(t, registry, "x", released)
+
+	youtube, err := registry.GetPlatform("youtube")
+	if err != nil {
+		t.Fatalf("GetPlatform(youtube): %v", err)
+	}
+	if err := registry.Replace(&panickingPlatform{Platform: youtube}); err != nil {
+		t.Fatalf("Replace(youtube): %v", err)
+	}
+
+	resp := decodeBatchRecentPosts(t, postBatchRecentPosts(newBatchRecentPostsRouter(t, registry), "x", "youtube"))
+
+	if resp.Platforms[0].Error != "" {
+		t.Errorf("x failed: %s", resp.Platforms[0].Error)
+	}
+	if resp.Platforms[1].Provider != "youtube" || resp.Platforms[1].Error == "" {
+		t.Errorf("platforms[1] = %+v, want a youtube failure", resp.Platforms[1])
+	}
+	if resp.SuccessCount != 1 || resp.ErrorCount != 1 {
+		t.Errorf("success_count = %d, error_count = %d, want 1 and 1", resp.SuccessCount, resp.ErrorCount)
+	}
+}
+
+func TestShareClientSkipsTokenForDiscordWebhook(t *testing.T) {
+	handler := NewShareHandler(config.NewAtomicProvider(&config.Config{}), storage.NewMemoryStorage(), platforms.NewRegistry(), logger.NewLogger(logger.Config{Level: "error"}))
+
+	webhook := &types.ShareRequest{Provider: "discord", UserID: "user123", ServerName: "myapp", WebhookURL: "https://discord.com/api/webhooks/1/token"}
+	if _, err := handler.shareClient(context.Background(), webhook); err != nil {
+		t.Fatalf("webhook share needs no token, got %v", err)
+	}
+
+	channel := &types.ShareRequest{Provider: "discord", UserID: "user123", ServerName: "myapp", ChannelID: "123"}
+	if _, err := handler.shareClient(context.Background(), channel); err == nil {
+		t.Fatal("channel share without a bot or user token succeeded, want an error")
+	}
+}
+
+func TestCheckShareCredentials(t *testing.T) {
+	cfg := &config.Config{Sandbox: config.SandboxConfig{Enabled: true, Providers: []string{"youtube"}}}
+	handler := NewShareHandler(config.NewAtomicProvider(cfg), storage.NewMemoryStorage(), platforms.NewRegistry(), logger.NewLogger(logger.Config{Level: "error"}))
+
+	tests := []struct {
+		name    string
+		req     types.ShareRequest
+		wantErr bool
+	}{
+		{name: "sandboxed provider", req: types.ShareRequest{Provider: "youtube"}},
+		{name: "discord webhook", req: types.ShareRequest{Provider: "discord", WebhookURL: "https://discord.com/api/webhooks/1/token"}},
+		{name: "no stored token", req: types.ShareRequest{Provider: "x"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.UserID = "user123"
+			tt.req.ServerName = "myapp"
+			err := handler.checkShareCredentials(context.Background(), &tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkShareCredentials() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+
+	"social/internal/types"
+)
+
+// cachedStats returns the cached stats of the requested post, unless the cache is disabled or
+// the request forces a refresh. Cache failures are logged and treated as a miss.
+func (h *ShareHandler) cachedStats(ctx context.Context, req *types.StatsRequest) (types.StatsData, bool) {
+	if !h.config().StatsCache.Enabled || req.ForceRefresh || req.MediaID == "" {
+		return types.StatsData{}, false
+	}
+
+	stats, ok, err := h.storage.GetCachedStats(ctx, req.Provider, req.MediaID)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to get cached stats", "provider", req.Provider, "media_id", req.MediaID)
+		return types.StatsData{}, false
+	}
+	return stats, ok
+}
+
+// cacheStats caches stats fetched from the platform for the configured TTL
+func (h *ShareHandler) cacheStats(ctx context.Context, req *types.StatsRequest, stats types.StatsData) {
+	cacheConfig := h.config().StatsCache
+	if !cacheConfig.Enabled || req.MediaID == "" || !stats.HasStats {
+		return
+	}
+
+	if err := h.storage.SaveCachedStats(ctx, req.Provider, req.MediaID, stats, cacheConfig.TTL); err != nil {
+		h.logger.Error(ctx, err, "failed to cache stats", "provider", req.Provider, "media_id", req.MediaID)
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"social/pkg/errors"
+)
+
+// Operation names reported in timeout errors
+const (
+	operationShare            = "share"
+	operationBatchShare       = "batch share"
+	operationDeletePost       = "delete post"
+	operationPostStatus       = "post status"
+	operationGetPost          = "get post"
+	operationStats            = "stats"
+	operationRecentPosts      = "recent posts"
+	operationBatchRecentPosts = "batch recent posts"
+)
+
+// timeoutError returns a TimeoutError naming the operation and its deadline if err was caused
+// by the operation's context deadline, or nil for any other error
+func timeoutError(ctx context.Context, err error, operation string, deadline time.Duration) *errors.TimeoutError {
+	if stderrors.Is(err, context.DeadlineExceeded) || stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.NewTimeoutError(operation, deadline)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+
+	"social/internal/types"
+)
+
+// cachedUserInfo returns the cached profile of the requested user, unless the cache is disabled
+// or the request forces a refresh. Cache failures are logged and treated as a miss.
+func (h *AuthHandler) cachedUserInfo(ctx context.Context, req *types.GetUserInfoRequest) (types.UserInfo, bool) {
+	if !h.config().UserInfoCache.Enabled || req.ForceRefresh {
+		return types.UserInfo{}, false
+	}
+
+	userInfo, ok, err := h.storage.GetCachedUserInfo(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName)
+	if err != nil {
+		h.logger.Error(ctx, err, "failed to get cached user info", "provider", req.Provider, "user_id", req.UserID)
+		return types.UserInfo{}, false
+	}
+	return userInfo, ok
+}
+
+// cacheUserInfo caches a profile fetched from the platform for the configured TTL
+func (h *AuthHandler) cacheUserInfo(ctx context.Context, req *types.GetUserInfoRequest, userInfo types.UserInfo) {
+	cacheConfig := h.config().UserInfoCache
+	if !cacheConfig.Enabled {
+		return
+	}
+
+	if err := h.storage.SaveCachedUserInfo(ctx, types.AccountUserID(req.UserID, req.AccountID), req.Provider, req.ServerName, userInfo, cacheConfig.TTL); err != nil {
+		h.logger.Error(ctx, err, "failed to cache user info", "provider", req.Provider, "user_id", req.UserID)
+	}
+}
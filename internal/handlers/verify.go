@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"social/internal/types"
+)
+
+// verifyPost reads a freshly shared post back by its media ID to confirm it is live
+func (h *ShareHandler) verifyPost(ctx context.Context, platform types.Platform, client *http.Client, provider, mediaID, status string) *types.PostVerification {
+	verification := &types.PostVerification{CheckedAt: time.Now().Unix()}
+
+	// Posts that are still being processed can't be read back yet
+	if status != types.PostStatusPublished {
+		verification.Error = fmt.Sprintf("post is %s", status)
+		return verification
+	}
+	if mediaID == "" {
+		verification.Error = "platform returned no media id"
+		return verification
+	}
+
+	if _, err := platform.GetPost(ctx, client, mediaID); err != nil {
+		h.logger.Warn(ctx, "post verification failed", "provider", provider, "media_id", mediaID, "error", err)
+		verification.Error = err.Error()
+		return verification
+	}
+
+	verification.Verified = true
+	return verification
+}
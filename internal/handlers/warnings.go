@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"social/internal/types"
+)
+
+// statsWarnings describes stats that came back incomplete, so callers don't read missing metrics as zeros
+func statsWarnings(provider string, stats types.StatsData) []string {
+	if !stats.HasStats {
+		return []string{fmt.Sprintf("%s stats are unavailable", provider)}
+	}
+	if stats.Partial {
+		return []string{fmt.Sprintf("%s stats are partial, missing: %s", provider, strings.Join(stats.MissingMetrics, ", "))}
+	}
+	return nil
+}
+
+// postsWarnings reports posts whose stats could not be fetched
+func postsWarnings(provider string, posts []types.Post) []string {
+	var missing int
+	for _, post := range posts {
+		if !post.Stats.HasStats {
+			missing++
+		}
+	}
+	if missing == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s stats are unavailable for %d of %d posts", provider, missing, len(posts))}
+}
+
+// verificationWarnings reports a post that could not be read back after sharing
+func verificationWarnings(provider string, verification *types.PostVerification) []string {
+	if verification == nil || verification.Verified {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s post could not be verified: %s", provider, verification.Error)}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+
+	"social/pkg/response"
+)
+
+// AdminAuth creates a middleware that requires the X-Admin-Token header to match
+// the configured admin token. Admin endpoints are disabled when no token is set.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			response.Forbidden(c, "admin endpoints are disabled")
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Token")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			response.Unauthorized(c, "invalid admin token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social/pkg/errors"
+	"social/pkg/response"
+)
+
+// BodyLimit creates a middleware that caps request bodies at maxBytes. Requests declaring a larger
+// Content-Length are rejected with 413 up front, reading past the limit of other bodies fails.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			response.Error(c, errors.NewAppError(errors.ErrRequestTooLarge.Code, fmt.Sprintf("request body is %d bytes, larger than the %d byte limit", c.Request.ContentLength, maxBytes), errors.ErrRequestTooLarge.Status))
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	corsAllowedMethods = "GET, POST, PUT, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type, Authorization, X-User-ID, X-Admin-Token, X-Request-ID, Idempotency-Key"
+	corsExposedHeaders = "X-Request-ID, Retry-After"
+)
+
+// CORS creates a middleware that allows browser requests from the given origins and answers
+// preflight requests. An origin of "*" allows any origin, the config validator only accepts it
+// outside production. Requests from other origins get no CORS headers and are blocked by the browser.
+func CORS(allowedOrigins []string, maxAge time.Duration) gin.HandlerFunc {
+	allowAll := slices.Contains(allowedOrigins, "*")
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		origins[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", "Origin")
+		if !allowAll && !origins[origin] {
+			if c.Request.Method == http.MethodOptions {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", origin)
+		c.Header("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		if c.Request.Method == http.MethodOptions {
+			c.Header("Access-Control-Allow-Methods", corsAllowedMethods)
+			c.Header("Access-Control-Allow-Headers", corsAllowedHeaders)
+			if maxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
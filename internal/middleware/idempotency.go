@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social/internal/storage"
+	"social/pkg/errors"
+	"social/pkg/logger"
+	"social/pkg/response"
+)
+
+// Idempotency-Key request and replay response headers
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const maxIdempotencyKeyLength = 255
+
+// idempotencyPendingTTL is how long a key stays reserved by a request in flight. It is longer than
+// any share deadline, and frees the keys of requests lost together with their instance.
+const idempotencyPendingTTL = 15 * time.Minute
+
+// IdempotencyMiddleware makes retried write requests safe. The first request made with an
+// Idempotency-Key runs normally and its successful response is stored; requests repeating the key
+// get that response back instead of running again.
+type IdempotencyMiddleware struct {
+	store   storage.Storage
+	enabled bool
+	ttl     time.Duration
+	logger  *logger.Logger
+}
+
+// NewIdempotencyMiddleware creates a new idempotency middleware remembering responses for ttl
+func NewIdempotencyMiddleware(store storage.Storage, enabled bool, ttl time.Duration, logger *logger.Logger) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store:   store,
+		enabled: enabled,
+		ttl:     ttl,
+		logger:  logger,
+	}
+}
+
+// Handle creates a middleware that replays the stored response of a repeated Idempotency-Key.
+// Keys are scoped to the server_name and user_id of the JSON body, and reusing a key with a
+// different body is rejected. Failed requests release their key so they can be retried.
+func (m *IdempotencyMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if !m.enabled || key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		if len(key) > maxIdempotencyKeyLength {
+			response.BadRequest(c, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+			c.Abort()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			// Restore the body for the handler
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				response.BadRequest(c, "failed to read request body")
+				c.Abort()
+				return
+			}
+		}
+
+		var identity struct {
+			ServerName string `json:"server_name"`
+			UserID     string `json:"user_id"`
+		}
+		_ = json.Unmarshal(body, &identity)
+		storeKey := fmt.Sprintf("%s:%s:%s", identity.ServerName, identity.UserID, key)
+
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		pendingTTL := min(idempotencyPendingTTL, m.ttl)
+		existing, err := m.store.ReserveIdempotencyKey(ctx, storeKey, &storage.IdempotencyRecord{Fingerprint: fingerprint}, pendingTTL)
+		if err != nil {
+			m.logger.Error(ctx, err, "failed to reserve idempotency key", "user_id", identity.UserID, "server_name", identity.ServerName)
+			response.InternalServerError(c, "failed to check idempotency key")
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				response.Error(c, errors.ErrIdempotencyKeyReused)
+			case !existing.Completed:
+				response.Error(c, errors.ErrIdempotencyKeyInUse)
+			default:
+				m.logger.Info(ctx, "replaying idempotent response", "user_id", identity.UserID, "server_name", identity.ServerName, "path", c.Request.URL.Path)
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, "application/json; charset=utf-8", existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Store the outcome even if the client has gone away, its retry is what needs it
+		saveCtx := context.WithoutCancel(ctx)
+		if status := recorder.Status(); status >= 200 && status < 300 {
+			record := &storage.IdempotencyRecord{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				StatusCode:  status,
+				Body:        recorder.body.Bytes(),
+			}
+			if err := m.store.SaveIdempotencyRecord(saveCtx, storeKey, record, m.ttl); err != nil {
+				m.logger.Error(ctx, err, "failed to save idempotency record", "user_id", identity.UserID, "server_name", identity.ServerName)
+			}
+			return
+		}
+
+		// Nothing was posted, so a retry with the same key should run again
+		if err := m.store.DeleteIdempotencyRecord(saveCtx, storeKey); err != nil {
+			m.logger.Error(ctx, err, "failed to release idempotency key", "user_id", identity.UserID, "server_name", identity.ServerName)
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the response body written through it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the response and the copy
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the response and the copy
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"social/pkg/errors"
+	"social/pkg/logger"
+	"social/pkg/response"
+)
+
+// MaintenanceMiddleware blocks write endpoints while maintenance mode is on
+type MaintenanceMiddleware struct {
+	mu      sync.RWMutex
+	enabled bool
+	message string
+	logger  *logger.Logger
+}
+
+// NewMaintenanceMiddleware creates a new maintenance middleware
+func NewMaintenanceMiddleware(enabled bool, message string, logger *logger.Logger) *MaintenanceMiddleware {
+	return &MaintenanceMiddleware{
+		enabled: enabled,
+		message: message,
+		logger:  logger,
+	}
+}
+
+// Enabled reports whether maintenance mode is on and the message returned to clients
+func (m *MaintenanceMiddleware) Enabled() (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.message
+}
+
+// Set turns maintenance mode on or off, an empty message keeps the current one
+func (m *MaintenanceMiddleware) Set(enabled bool, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = enabled
+	if message != "" {
+		m.message = message
+	}
+}
+
+// BlockWrites creates a middleware that rejects requests with 503 while maintenance mode is on
+func (m *MaintenanceMiddleware) BlockWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled, message := m.Enabled()
+		if enabled {
+			m.logger.Warn(c.Request.Context(), "write rejected due to maintenance mode", "path", c.Request.URL.Path)
+			response.Error(c, errors.NewAppError(errors.ErrMaintenanceMode.Code, message, errors.ErrMaintenanceMode.Status))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
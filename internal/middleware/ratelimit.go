@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"social/pkg/errors"
+	"social/pkg/logger"
+	"social/pkg/response"
+)
+
+// tokenBucketScript atomically refills and takes a token from a bucket stored as a hash.
+// Returns {allowed, retry_after_ms}.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or burst
+local ts = tonumber(data[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+
+return {allowed, retry}
+`)
+
+// limit is a token bucket rate and size
+type limit struct {
+	rate  float64 // tokens per millisecond
+	burst int
+}
+
+// newLimit converts a per-minute rate to a limit
+func newLimit(requestsPerMinute, burst int) limit {
+	return limit{
+		rate:  float64(requestsPerMinute) / float64(time.Minute/time.Millisecond),
+		burst: burst,
+	}
+}
+
+// refill returns how long an empty bucket takes to fill up
+func (l limit) refill() time.Duration {
+	return time.Duration(float64(l.burst)/l.rate) * time.Millisecond
+}
+
+// bucket is an in-process token bucket used when Redis isn't available
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  limit
+}
+
+// RateLimiter limits requests per server, user and provider with a token bucket. Buckets are kept
+// in Redis so limits are shared across instances, or in process without Redis. Servers can be given
+// their own limits, so tenants on different tiers get different rates.
+type RateLimiter struct {
+	client       *redis.Client
+	limit        limit            // default limit
+	serverLimits map[string]limit // limits of servers with their own tier
+	logger       *logger.Logger
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewRateLimiter creates a new rate limiter, client may be nil for in-process limiting
+func NewRateLimiter(client *redis.Client, requestsPerMinute, burst int, logger *logger.Logger) *RateLimiter {
+	return &RateLimiter{
+		client:       client,
+		limit:        newLimit(requestsPerMinute, burst),
+		serverLimits: make(map[string]limit),
+		logger:       logger,
+		buckets:      make(map[string]*bucket),
+	}
+}
+
+// SetServerLimit overrides the default limit for requests made for a server.
+// It must be called before the middleware handles requests.
+func (l *RateLimiter) SetServerLimit(serverName string, requestsPerMinute, burst int) {
+	l.serverLimits[serverName] = newLimit(requestsPerMinute, burst)
+}
+
+// Limit creates a middleware that rejects requests over the limit with 429
+func (l *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		identity := l.identify(c)
+		key := fmt.Sprintf("ratelimit:%s", identity.key())
+
+		allowed, retryAfter, err := l.take(ctx, key, l.limitFor(identity.serverName))
+		if err != nil {
+			// Fail open so a Redis outage doesn't take down the API
+			l.logger.Error(ctx, err, "rate limit check failed", "key", key)
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			l.logger.Warn(ctx, "rate limit exceeded", "key", key, "retry_after", seconds)
+			response.Error(c, errors.ErrRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// limitFor returns the limit of a server, or the default one
+func (l *RateLimiter) limitFor(serverName string) limit {
+	if serverLimit, ok := l.serverLimits[serverName]; ok {
+		return serverLimit
+	}
+	return l.limit
+}
+
+// requestIdentity is who a request is made for
+type requestIdentity struct {
+	serverName string
+	userID     string
+	provider   string
+	clientIP   string
+}
+
+// key returns the bucket key, server_name:user_id:provider for identified users
+// and the client IP otherwise
+func (i requestIdentity) key() string {
+	if i.userID == "" {
+		return "ip:" + i.clientIP
+	}
+	return fmt.Sprintf("%s:%s:%s", i.serverName, i.userID, i.provider)
+}
+
+// identify reads the server_name, user_id and provider fields of the JSON body. The
+// X-User-ID header takes precedence over the body's user_id.
+func (l *RateLimiter) identify(c *gin.Context) requestIdentity {
+	identity := requestIdentity{clientIP: c.ClientIP()}
+
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		// Restore the body for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err == nil {
+			var payload struct {
+				ServerName string `json:"server_name"`
+				UserID     string `json:"user_id"`
+				Provider   string `json:"provider"`
+			}
+			if json.Unmarshal(body, &payload) == nil {
+				identity.serverName = payload.ServerName
+				identity.userID = payload.UserID
+				identity.provider = payload.Provider
+			}
+		}
+	}
+
+	if userID := c.GetHeader("X-User-ID"); userID != "" {
+		identity.userID = userID
+	}
+
+	return identity
+}
+
+// take removes a token from the bucket, returning how long to wait if none is left
+func (l *RateLimiter) take(ctx context.Context, key string, lim limit) (bool, time.Duration, error) {
+	if l.client == nil {
+		allowed, retryAfter := l.takeLocal(key, time.Now(), lim)
+		return allowed, retryAfter, nil
+	}
+
+	now := time.Now().UnixMilli()
+	result, err := tokenBucketScript.Run(ctx, l.client, []string{key}, lim.rate, lim.burst, now).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// takeLocal is the in-process equivalent of tokenBucketScript
+func (l *RateLimiter) takeLocal(key string, now time.Time, lim limit) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		l.pruneLocked(now)
+		b = &bucket{tokens: float64(lim.burst), last: now}
+		l.buckets[key] = b
+	}
+	b.limit = lim
+
+	elapsed := float64(now.Sub(b.last).Milliseconds())
+	b.tokens = math.Min(float64(lim.burst), b.tokens+math.Max(0, elapsed)*lim.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	retry := math.Ceil((1 - b.tokens) / lim.rate)
+	return false, time.Duration(retry) * time.Millisecond
+}
+
+// pruneLocked drops buckets that have refilled completely once there are many,
+// caller must hold mu
+func (l *RateLimiter) pruneLocked(now time.Time) {
+	if len(l.buckets) < 10000 {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > b.limit.refill() {
+			delete(l.buckets, key)
+		}
+	}
+}
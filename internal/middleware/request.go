@@ -1,13 +1,19 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	ctxutil "social/pkg/context"
 	"social/pkg/logger"
+	"social/pkg/quota"
+	"social/pkg/tracing"
 )
 
 // RequestMiddleware handles request ID generation and logging
@@ -28,13 +34,29 @@ func (m *RequestMiddleware) RequestID() gin.HandlerFunc {
 		// Generate request ID
 		requestID := uuid.New().String()
 
-		// Add request ID to context
+		// Add request ID to context, along with a recorder for the rate limit windows of platform
+		// calls so rate limited errors can tell clients when to retry
 		ctx := ctxutil.WithRequestID(c.Request.Context(), requestID)
+		ctx = quota.WithRecorder(ctx)
 		c.Request = c.Request.WithContext(ctx)
 
 		// Add request ID to response header
 		c.Header("X-Request-ID", requestID)
 
+		// Trace the request, platform calls and token refreshes made with its context become child spans
+		var span trace.Span
+		if tracing.Enabled() {
+			ctx, span = tracing.Start(ctx, c.Request.Method+" "+c.FullPath(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", c.Request.Method),
+					attribute.String("http.route", c.FullPath()),
+					attribute.String("request_id", requestID),
+				),
+			)
+			c.Request = c.Request.WithContext(ctx)
+		}
+
 		// Log request start
 		m.logger.Info(ctx, "request started",
 			"method", c.Request.Method,
@@ -51,6 +73,13 @@ func (m *RequestMiddleware) RequestID() gin.HandlerFunc {
 
 		// Log request completion
 		duration := time.Since(start)
+		if span != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", c.Writer.Status()))
+			if c.Writer.Status() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(c.Writer.Status()))
+			}
+			span.End()
+		}
 		m.logger.Info(ctx, "request completed",
 			"method", c.Request.Method,
 			"path", c.Request.URL.Path,
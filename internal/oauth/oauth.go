@@ -14,18 +14,27 @@ import (
 	"time"
 
 	"golang.org/x/oauth2"
+
+	"social/pkg/logger"
 )
 
 // StatePayload represents the encoded state parameter
 type StatePayload struct {
 	UserID     string `json:"uid"`
 	ServerName string `json:"server"`
+	AccountID  string `json:"aid,omitempty"`
 	Nonce      string `json:"n"`
 }
 
+// defaultRequestTimeout limits token requests of providers without a configured timeout
+const defaultRequestTimeout = 15 * time.Second
+
 // OAuthService handles OAuth operations
 type OAuthService struct {
-	config *oauth2.Config
+	config    *oauth2.Config
+	userAgent string
+	timeout   time.Duration
+	logger    *logger.Logger
 }
 
 // NewOAuthService creates a new OAuth service
@@ -33,6 +42,55 @@ func NewOAuthService(config *oauth2.Config) *OAuthService {
 	return &OAuthService{config: config}
 }
 
+// SetUserAgent sets the User-Agent sent on token requests and by clients created with CreateClient,
+// an empty string keeps Go's default
+func (s *OAuthService) SetUserAgent(userAgent string) {
+	s.userAgent = userAgent
+}
+
+// SetTimeout limits each request of token requests and clients created with CreateClient,
+// 0 keeps the default for token requests and leaves API requests bound only by their context
+func (s *OAuthService) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// SetLogger sets the logger token requests are logged to, without one nothing is logged
+func (s *OAuthService) SetLogger(logger *logger.Logger) {
+	s.logger = logger
+}
+
+// debug logs a token request detail at debug level, secrets must be passed through logger.Redact
+func (s *OAuthService) debug(ctx context.Context, message string, args ...interface{}) {
+	if s.logger != nil {
+		s.logger.Debug(ctx, message, args...)
+	}
+}
+
+// warn logs a token request problem that doesn't fail the request
+func (s *OAuthService) warn(ctx context.Context, message string, args ...interface{}) {
+	if s.logger != nil {
+		s.logger.Warn(ctx, message, args...)
+	}
+}
+
+// requestTimeout returns the timeout of token requests
+func (s *OAuthService) requestTimeout() time.Duration {
+	if s.timeout > 0 {
+		return s.timeout
+	}
+	return defaultRequestTimeout
+}
+
+// httpContext makes the oauth2 package send requests made with ctx with the configured User-Agent
+func (s *OAuthService) httpContext(ctx context.Context) context.Context {
+	if s.userAgent == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
+		Transport: &userAgentTransport{userAgent: s.userAgent},
+	})
+}
+
 // RandStringURLSafe generates a cryptographically secure random string
 func RandStringURLSafe(n int) (string, error) {
 	b := make([]byte, n)
@@ -49,8 +107,8 @@ func PKCEChallenge(verifier string) string {
 	return base64.RawURLEncoding.EncodeToString(h[:])
 }
 
-// EncodeState encodes user ID, server name and nonce into a state parameter
-func EncodeState(userID, serverName string) (string, error) {
+// EncodeState encodes user ID, server name, account ID and nonce into a state parameter
+func EncodeState(userID, serverName, accountID string) (string, error) {
 	nonce, err := RandStringURLSafe(12)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate nonce: %w", err)
@@ -59,6 +117,7 @@ func EncodeState(userID, serverName string) (string, error) {
 	payload := StatePayload{
 		UserID:     userID,
 		ServerName: serverName,
+		AccountID:  accountID,
 		Nonce:      nonce,
 	}
 
@@ -85,11 +144,25 @@ func DecodeState(raw string) (*StatePayload, error) {
 	return &payload, nil
 }
 
-// GenerateAuthURL generates an OAuth authorization URL
+// GenerateAuthURL generates an OAuth authorization URL. With usePKCE it also returns the PKCE
+// verifier, which has to be passed to ExchangeCode with the authorization code.
 func (s *OAuthService) GenerateAuthURL(state string, usePKCE bool) (string, string, error) {
-	var authURL string
-	var verifier string
+	var opts []oauth2.AuthCodeOption
+	switch s.config.Endpoint.AuthURL {
+	case "https://accounts.google.com/o/oauth2/auth":
+		// For Google OAuth (YouTube), we need prompt=consent to ensure refresh token is returned
+		opts = append(opts, oauth2.AccessTypeOffline, oauth2.SetAuthURLParam("prompt", "consent"))
+	case "https://www.reddit.com/api/v1/authorize":
+		// Reddit only returns a refresh token for permanent grants
+		opts = append(opts, oauth2.SetAuthURLParam("duration", "permanent"))
+	default:
+		// X, the provider requiring PKCE, grants offline access through its offline.access scope
+		if !usePKCE {
+			opts = append(opts, oauth2.AccessTypeOffline)
+		}
+	}
 
+	var verifier string
 	if usePKCE {
 		// Generate PKCE verifier and challenge
 		var err error
@@ -98,105 +171,83 @@ func (s *OAuthService) GenerateAuthURL(state string, usePKCE bool) (string, stri
 			return "", "", fmt.Errorf("failed to generate PKCE verifier: %w", err)
 		}
 
-		codeChallenge := PKCEChallenge(verifier)
-		authURL = s.config.AuthCodeURL(state,
-			oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+		opts = append(opts,
+			oauth2.SetAuthURLParam("code_challenge", PKCEChallenge(verifier)),
 			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
-			oauth2.SetAuthURLParam("response_type", "code"),
-			oauth2.SetAuthURLParam("scope", strings.Join(s.config.Scopes, " ")),
 		)
-	} else {
-		// Standard OAuth flow with offline access for refresh tokens
-		// For Google OAuth (YouTube), we need prompt=consent to ensure refresh token is returned
-		if s.config.Endpoint.AuthURL == "https://accounts.google.com/o/oauth2/auth" {
-			authURL = s.config.AuthCodeURL(state,
-				oauth2.AccessTypeOffline,
-				oauth2.SetAuthURLParam("prompt", "consent"),
-			)
-		} else {
-			authURL = s.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
-		}
 	}
 
-	return authURL, verifier, nil
+	return s.config.AuthCodeURL(state, opts...), verifier, nil
 }
 
 // ExchangeCode exchanges authorization code for access token
 func (s *OAuthService) ExchangeCode(ctx context.Context, code, verifier string) (*oauth2.Token, error) {
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	ctx, cancel := context.WithTimeout(s.httpContext(ctx), s.requestTimeout())
 	defer cancel()
 
-	fmt.Printf("DEBUG: Starting token exchange\n")
-	fmt.Printf("DEBUG: Code: %s\n", code)
-	fmt.Printf("DEBUG: Verifier: %s (length: %d)\n", verifier, len(verifier))
-	fmt.Printf("DEBUG: Token URL: %s\n", s.config.Endpoint.TokenURL)
-	fmt.Printf("DEBUG: Client ID: %s\n", s.config.ClientID)
+	s.debug(ctx, "starting token exchange", "token_url", s.config.Endpoint.TokenURL, "client_id", s.config.ClientID,
+		"code", logger.Redact(code), "verifier", logger.Redact(verifier))
 
 	var token *oauth2.Token
 	var err error
 
 	if verifier != "" {
 		// PKCE flow - X platform requires special handling
-		fmt.Printf("DEBUG: Using PKCE flow\n")
-
 		// For X platform, we need to use a custom token exchange
 		if s.config.Endpoint.TokenURL == "https://api.x.com/2/oauth2/token" {
-			fmt.Printf("DEBUG: Using custom X platform token exchange\n")
 			token, err = s.exchangeCodeWithPKCE(ctx, code, verifier)
 		} else {
 			token, err = s.config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
 		}
 	} else {
 		// Standard flow
-		fmt.Printf("DEBUG: Using standard flow\n")
 		token, err = s.config.Exchange(ctx, code)
 	}
 
 	// For Instagram, we need to exchange short-lived token for long-lived token
 	if err == nil && s.config.Endpoint.TokenURL == "https://api.instagram.com/oauth/access_token" {
-		fmt.Printf("DEBUG: Instagram detected, exchanging short-lived token for long-lived token\n")
 		longLivedToken, exchangeErr := s.exchangeInstagramToken(ctx, token.AccessToken)
 		if exchangeErr != nil {
-			fmt.Printf("DEBUG: Instagram token exchange failed: %v\n", exchangeErr)
 			// Continue with short-lived token if exchange fails
+			s.warn(ctx, "instagram long-lived token exchange failed, keeping the short-lived token", "error", exchangeErr)
+		} else {
+			token = longLivedToken
+		}
+	}
+
+	// Threads issues short-lived tokens the same way as Instagram
+	if err == nil && s.config.Endpoint.TokenURL == "https://graph.threads.net/oauth/access_token" {
+		longLivedToken, exchangeErr := s.exchangeThreadsToken(ctx, token.AccessToken)
+		if exchangeErr != nil {
+			// Continue with short-lived token if exchange fails
+			s.warn(ctx, "threads long-lived token exchange failed, keeping the short-lived token", "error", exchangeErr)
 		} else {
-			fmt.Printf("DEBUG: Instagram token exchange successful\n")
 			token = longLivedToken
 		}
 	}
 
 	// For Facebook, we need to exchange short-lived token for long-lived token
 	if err == nil && s.config.Endpoint.TokenURL == "https://graph.facebook.com/v18.0/oauth/access_token" {
-		fmt.Printf("DEBUG: Facebook detected, exchanging short-lived token for long-lived token\n")
 		longLivedToken, exchangeErr := s.exchangeFacebookToken(ctx, token.AccessToken)
 		if exchangeErr != nil {
-			fmt.Printf("DEBUG: Facebook token exchange failed: %v\n", exchangeErr)
 			// Continue with short-lived token if exchange fails
+			s.warn(ctx, "facebook long-lived token exchange failed, keeping the short-lived token", "error", exchangeErr)
 		} else {
-			fmt.Printf("DEBUG: Facebook token exchange successful\n")
 			token = longLivedToken
 		}
 	}
 
 	if err != nil {
-		fmt.Printf("DEBUG: Token exchange failed: %v\n", err)
 		return nil, fmt.Errorf("token exchange failed: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Token exchange successful\n")
-	fmt.Printf("DEBUG: Access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	s.debug(ctx, "token exchange successful", "access_token", logger.Redact(token.AccessToken), "token_type", token.TokenType, "expiry", token.Expiry)
 
 	return token, nil
 }
 
 // exchangeCodeWithPKCE performs custom token exchange for X platform
 func (s *OAuthService) exchangeCodeWithPKCE(ctx context.Context, code, verifier string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Custom X platform token exchange\n")
-	fmt.Printf("DEBUG: Code: %s\n", code)
-	fmt.Printf("DEBUG: Verifier: %s\n", verifier)
-
 	// Prepare the request data
 	data := url.Values{}
 	data.Set("code", code)
@@ -205,8 +256,6 @@ func (s *OAuthService) exchangeCodeWithPKCE(ctx context.Context, code, verifier
 	data.Set("redirect_uri", s.config.RedirectURL)
 	data.Set("code_verifier", verifier)
 
-	fmt.Printf("DEBUG: Request data: %s\n", data.Encode())
-
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "POST", s.config.Endpoint.TokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -221,11 +270,11 @@ func (s *OAuthService) exchangeCodeWithPKCE(ctx context.Context, code, verifier
 	auth := base64.StdEncoding.EncodeToString([]byte(s.config.ClientID + ":" + s.config.ClientSecret))
 	req.Header.Set("Authorization", "Basic "+auth)
 
-	fmt.Printf("DEBUG: Request URL: %s\n", req.URL.String())
-	fmt.Printf("DEBUG: Request headers: %v\n", req.Header)
+	// The query and headers carry the client secret and tokens
+	s.debug(ctx, "sending token request", "url", req.URL.Host+req.URL.Path)
 
 	// Send the request
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Timeout: s.requestTimeout()}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -240,8 +289,7 @@ func (s *OAuthService) exchangeCodeWithPKCE(ctx context.Context, code, verifier
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Response status: %d\n", resp.StatusCode)
-	fmt.Printf("DEBUG: Response body: %s\n", string(body))
+	s.debug(ctx, "token response received", "status", resp.StatusCode, "body_size", len(body))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("token exchange failed: status=%d body=%s", resp.StatusCode, string(body))
@@ -271,31 +319,33 @@ func (s *OAuthService) exchangeCodeWithPKCE(ctx context.Context, code, verifier
 		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
 	}
 
-	fmt.Printf("DEBUG: Token exchange successful\n")
-	fmt.Printf("DEBUG: Access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	s.debug(ctx, "token exchange successful", "access_token", logger.Redact(token.AccessToken), "token_type", token.TokenType, "expiry", token.Expiry)
 
 	return token, nil
 }
 
 // exchangeInstagramToken exchanges short-lived Instagram token for long-lived token
 func (s *OAuthService) exchangeInstagramToken(ctx context.Context, shortLivedToken string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Exchanging Instagram short-lived token for long-lived token\n")
-	fmt.Printf("DEBUG: Short-lived token: %s\n", shortLivedToken)
-
 	// Instagram uses a different endpoint for token exchange
 	// According to Instagram API docs: https://graph.instagram.com/access_token
-	exchangeURL := "https://graph.instagram.com/access_token"
+	return s.exchangeLongLivedToken(ctx, "Instagram", "https://graph.instagram.com/access_token", "ig_exchange_token", shortLivedToken)
+}
 
+// exchangeThreadsToken exchanges short-lived Threads token for long-lived token
+func (s *OAuthService) exchangeThreadsToken(ctx context.Context, shortLivedToken string) (*oauth2.Token, error) {
+	// Threads mirrors the Instagram exchange on its own graph host
+	return s.exchangeLongLivedToken(ctx, "Threads", "https://graph.threads.net/access_token", "th_exchange_token", shortLivedToken)
+}
+
+// exchangeLongLivedToken exchanges a short-lived token for a long-lived one on the
+// access_token endpoint Instagram and Threads share
+func (s *OAuthService) exchangeLongLivedToken(ctx context.Context, platform, exchangeURL, grantType, shortLivedToken string) (*oauth2.Token, error) {
 	// Prepare the request data
 	data := url.Values{}
-	data.Set("grant_type", "ig_exchange_token")
+	data.Set("grant_type", grantType)
 	data.Set("client_secret", s.config.ClientSecret)
 	data.Set("access_token", shortLivedToken)
 
-	fmt.Printf("DEBUG: Request data: %s\n", data.Encode())
-
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "GET", exchangeURL+"?"+data.Encode(), nil)
 	if err != nil {
@@ -305,11 +355,8 @@ func (s *OAuthService) exchangeInstagramToken(ctx context.Context, shortLivedTok
 	// Set headers
 	req.Header.Set("Accept", "application/json")
 
-	fmt.Printf("DEBUG: Request URL: %s\n", req.URL.String())
-	fmt.Printf("DEBUG: Request headers: %v\n", req.Header)
-
 	// Send the request
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Timeout: s.requestTimeout()}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -324,11 +371,8 @@ func (s *OAuthService) exchangeInstagramToken(ctx context.Context, shortLivedTok
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Response status: %d\n", resp.StatusCode)
-	fmt.Printf("DEBUG: Response body: %s\n", string(body))
-
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("token exchange failed: status=%d body=%s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%s token exchange failed: status=%d body=%s", platform, resp.StatusCode, string(body))
 	}
 
 	// Parse response
@@ -346,7 +390,7 @@ func (s *OAuthService) exchangeInstagramToken(ctx context.Context, shortLivedTok
 	token := &oauth2.Token{
 		AccessToken: tokenResponse.AccessToken,
 		TokenType:   tokenResponse.TokenType,
-		// For Instagram, the long-lived token itself can be used for refresh
+		// The long-lived token itself is used for refresh
 		RefreshToken: tokenResponse.AccessToken,
 	}
 
@@ -354,19 +398,11 @@ func (s *OAuthService) exchangeInstagramToken(ctx context.Context, shortLivedTok
 		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
 	}
 
-	fmt.Printf("DEBUG: Instagram token exchange successful\n")
-	fmt.Printf("DEBUG: Long-lived access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
-
 	return token, nil
 }
 
 // exchangeFacebookToken exchanges short-lived Facebook token for long-lived token
 func (s *OAuthService) exchangeFacebookToken(ctx context.Context, shortLivedToken string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Exchanging Facebook short-lived token for long-lived token\n")
-	fmt.Printf("DEBUG: Short-lived token: %s\n", shortLivedToken)
-
 	// Facebook uses a different endpoint for token exchange
 	// According to Facebook API docs: https://graph.facebook.com/oauth/access_token
 	exchangeURL := "https://graph.facebook.com/oauth/access_token"
@@ -378,8 +414,6 @@ func (s *OAuthService) exchangeFacebookToken(ctx context.Context, shortLivedToke
 	data.Set("client_secret", s.config.ClientSecret)
 	data.Set("fb_exchange_token", shortLivedToken)
 
-	fmt.Printf("DEBUG: Request data: %s\n", data.Encode())
-
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "GET", exchangeURL+"?"+data.Encode(), nil)
 	if err != nil {
@@ -389,11 +423,11 @@ func (s *OAuthService) exchangeFacebookToken(ctx context.Context, shortLivedToke
 	// Set headers
 	req.Header.Set("Accept", "application/json")
 
-	fmt.Printf("DEBUG: Request URL: %s\n", req.URL.String())
-	fmt.Printf("DEBUG: Request headers: %v\n", req.Header)
+	// The query and headers carry the client secret and tokens
+	s.debug(ctx, "sending token request", "url", req.URL.Host+req.URL.Path)
 
 	// Send the request
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Timeout: s.requestTimeout()}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -408,8 +442,7 @@ func (s *OAuthService) exchangeFacebookToken(ctx context.Context, shortLivedToke
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Response status: %d\n", resp.StatusCode)
-	fmt.Printf("DEBUG: Response body: %s\n", string(body))
+	s.debug(ctx, "token response received", "status", resp.StatusCode, "body_size", len(body))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("token exchange failed: status=%d body=%s", resp.StatusCode, string(body))
@@ -438,68 +471,55 @@ func (s *OAuthService) exchangeFacebookToken(ctx context.Context, shortLivedToke
 		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
 	}
 
-	fmt.Printf("DEBUG: Facebook token exchange successful\n")
-	fmt.Printf("DEBUG: Long-lived access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	s.debug(ctx, "facebook token exchange successful", "access_token", logger.Redact(token.AccessToken), "token_type", token.TokenType, "expiry", token.Expiry)
 
 	return token, nil
 }
 
 // RefreshToken refreshes an access token using refresh token
 func (s *OAuthService) RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Starting token refresh\n")
-	fmt.Printf("DEBUG: Refresh token: %s\n", refreshToken)
-	fmt.Printf("DEBUG: Token URL: %s\n", s.config.Endpoint.TokenURL)
-	fmt.Printf("DEBUG: Client ID: %s\n", s.config.ClientID)
+	s.debug(ctx, "starting token refresh", "token_url", s.config.Endpoint.TokenURL, "client_id", s.config.ClientID,
+		"refresh_token", logger.Redact(refreshToken))
 
 	// For X platform, we need to use a custom refresh token exchange
 	if s.config.Endpoint.TokenURL == "https://api.x.com/2/oauth2/token" {
-		fmt.Printf("DEBUG: Using custom X platform token refresh\n")
 		return s.refreshTokenWithX(ctx, refreshToken)
 	}
 
 	// For Instagram platform, we need to use Instagram-specific refresh endpoint
 	if s.config.Endpoint.TokenURL == "https://api.instagram.com/oauth/access_token" {
-		fmt.Printf("DEBUG: Using Instagram platform token refresh\n")
 		return s.refreshTokenWithInstagram(ctx, refreshToken)
 	}
 
+	// For Threads platform, we need to use Threads-specific refresh endpoint
+	if s.config.Endpoint.TokenURL == "https://graph.threads.net/oauth/access_token" {
+		return s.refreshTokenWithThreads(ctx, refreshToken)
+	}
+
 	// For Facebook platform, we need to use Facebook-specific refresh endpoint
 	if s.config.Endpoint.TokenURL == "https://graph.facebook.com/v18.0/oauth/access_token" {
-		fmt.Printf("DEBUG: Using Facebook platform token refresh\n")
 		return s.refreshTokenWithFacebook(ctx, refreshToken)
 	}
 
 	// For other platforms, use standard OAuth2 refresh
-	fmt.Printf("DEBUG: Using standard OAuth2 token refresh\n")
-	token, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	token, err := s.config.TokenSource(s.httpContext(ctx), &oauth2.Token{RefreshToken: refreshToken}).Token()
 	if err != nil {
-		fmt.Printf("DEBUG: Token refresh failed: %v\n", err)
 		return nil, fmt.Errorf("token refresh failed: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Token refresh successful\n")
-	fmt.Printf("DEBUG: New access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	s.debug(ctx, "token refresh successful", "access_token", logger.Redact(token.AccessToken), "token_type", token.TokenType, "expiry", token.Expiry)
 
 	return token, nil
 }
 
 // refreshTokenWithX performs custom token refresh for X platform
 func (s *OAuthService) refreshTokenWithX(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Custom X platform token refresh\n")
-	fmt.Printf("DEBUG: Refresh token: %s\n", refreshToken)
-
 	// Prepare the request data
 	data := url.Values{}
 	data.Set("refresh_token", refreshToken)
 	data.Set("grant_type", "refresh_token")
 	data.Set("client_id", s.config.ClientID)
 
-	fmt.Printf("DEBUG: Request data: %s\n", data.Encode())
-
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "POST", s.config.Endpoint.TokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
@@ -514,11 +534,11 @@ func (s *OAuthService) refreshTokenWithX(ctx context.Context, refreshToken strin
 	auth := base64.StdEncoding.EncodeToString([]byte(s.config.ClientID + ":" + s.config.ClientSecret))
 	req.Header.Set("Authorization", "Basic "+auth)
 
-	fmt.Printf("DEBUG: Request URL: %s\n", req.URL.String())
-	fmt.Printf("DEBUG: Request headers: %v\n", req.Header)
+	// The query and headers carry the client secret and tokens
+	s.debug(ctx, "sending token request", "url", req.URL.Host+req.URL.Path)
 
 	// Send the request
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Timeout: s.requestTimeout()}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -533,8 +553,7 @@ func (s *OAuthService) refreshTokenWithX(ctx context.Context, refreshToken strin
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Response status: %d\n", resp.StatusCode)
-	fmt.Printf("DEBUG: Response body: %s\n", string(body))
+	s.debug(ctx, "token response received", "status", resp.StatusCode, "body_size", len(body))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("token refresh failed: status=%d body=%s", resp.StatusCode, string(body))
@@ -564,30 +583,31 @@ func (s *OAuthService) refreshTokenWithX(ctx context.Context, refreshToken strin
 		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
 	}
 
-	fmt.Printf("DEBUG: Token refresh successful\n")
-	fmt.Printf("DEBUG: New access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	s.debug(ctx, "token refresh successful", "access_token", logger.Redact(token.AccessToken), "token_type", token.TokenType, "expiry", token.Expiry)
 
 	return token, nil
 }
 
 // refreshTokenWithInstagram performs custom token refresh for Instagram platform
 func (s *OAuthService) refreshTokenWithInstagram(ctx context.Context, accessToken string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Custom Instagram platform token refresh\n")
-	fmt.Printf("DEBUG: Access token: %s\n", accessToken)
-
 	// Instagram uses a different refresh endpoint and parameters
 	// According to Instagram API docs: https://graph.instagram.com/refresh_access_token
-	refreshURL := "https://graph.instagram.com/refresh_access_token"
+	return s.refreshLongLivedToken(ctx, "Instagram", "https://graph.instagram.com/refresh_access_token", "ig_refresh_token", accessToken)
+}
+
+// refreshTokenWithThreads performs custom token refresh for Threads platform
+func (s *OAuthService) refreshTokenWithThreads(ctx context.Context, accessToken string) (*oauth2.Token, error) {
+	return s.refreshLongLivedToken(ctx, "Threads", "https://graph.threads.net/refresh_access_token", "th_refresh_token", accessToken)
+}
 
+// refreshLongLivedToken refreshes a long-lived token on the refresh_access_token endpoint
+// Instagram and Threads share
+func (s *OAuthService) refreshLongLivedToken(ctx context.Context, platform, refreshURL, grantType, accessToken string) (*oauth2.Token, error) {
 	// Prepare the request data
 	data := url.Values{}
-	data.Set("grant_type", "ig_refresh_token")
+	data.Set("grant_type", grantType)
 	data.Set("access_token", accessToken)
 
-	fmt.Printf("DEBUG: Request data: %s\n", data.Encode())
-
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "GET", refreshURL+"?"+data.Encode(), nil)
 	if err != nil {
@@ -597,11 +617,8 @@ func (s *OAuthService) refreshTokenWithInstagram(ctx context.Context, accessToke
 	// Set headers
 	req.Header.Set("Accept", "application/json")
 
-	fmt.Printf("DEBUG: Request URL: %s\n", req.URL.String())
-	fmt.Printf("DEBUG: Request headers: %v\n", req.Header)
-
 	// Send the request
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Timeout: s.requestTimeout()}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -616,11 +633,8 @@ func (s *OAuthService) refreshTokenWithInstagram(ctx context.Context, accessToke
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Response status: %d\n", resp.StatusCode)
-	fmt.Printf("DEBUG: Response body: %s\n", string(body))
-
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("token refresh failed: status=%d body=%s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%s token refresh failed: status=%d body=%s", platform, resp.StatusCode, string(body))
 	}
 
 	// Parse response
@@ -638,7 +652,7 @@ func (s *OAuthService) refreshTokenWithInstagram(ctx context.Context, accessToke
 	token := &oauth2.Token{
 		AccessToken: tokenResponse.AccessToken,
 		TokenType:   tokenResponse.TokenType,
-		// Instagram doesn't provide refresh token in refresh response
+		// No refresh token is provided in the refresh response
 		// The new access token becomes the new long-lived token
 	}
 
@@ -646,19 +660,11 @@ func (s *OAuthService) refreshTokenWithInstagram(ctx context.Context, accessToke
 		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
 	}
 
-	fmt.Printf("DEBUG: Instagram token refresh successful\n")
-	fmt.Printf("DEBUG: New access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
-
 	return token, nil
 }
 
 // refreshTokenWithFacebook performs custom token refresh for Facebook platform
 func (s *OAuthService) refreshTokenWithFacebook(ctx context.Context, accessToken string) (*oauth2.Token, error) {
-	fmt.Printf("DEBUG: Custom Facebook platform token refresh\n")
-	fmt.Printf("DEBUG: Access token: %s\n", accessToken)
-
 	// Facebook uses the same endpoint for token exchange and refresh
 	// According to Facebook API docs: https://graph.facebook.com/oauth/access_token
 	refreshURL := "https://graph.facebook.com/oauth/access_token"
@@ -670,8 +676,6 @@ func (s *OAuthService) refreshTokenWithFacebook(ctx context.Context, accessToken
 	data.Set("client_secret", s.config.ClientSecret)
 	data.Set("fb_exchange_token", accessToken)
 
-	fmt.Printf("DEBUG: Request data: %s\n", data.Encode())
-
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "GET", refreshURL+"?"+data.Encode(), nil)
 	if err != nil {
@@ -681,11 +685,11 @@ func (s *OAuthService) refreshTokenWithFacebook(ctx context.Context, accessToken
 	// Set headers
 	req.Header.Set("Accept", "application/json")
 
-	fmt.Printf("DEBUG: Request URL: %s\n", req.URL.String())
-	fmt.Printf("DEBUG: Request headers: %v\n", req.Header)
+	// The query and headers carry the client secret and tokens
+	s.debug(ctx, "sending token request", "url", req.URL.Host+req.URL.Path)
 
 	// Send the request
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Timeout: s.requestTimeout()}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -700,8 +704,7 @@ func (s *OAuthService) refreshTokenWithFacebook(ctx context.Context, accessToken
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Response status: %d\n", resp.StatusCode)
-	fmt.Printf("DEBUG: Response body: %s\n", string(body))
+	s.debug(ctx, "token response received", "status", resp.StatusCode, "body_size", len(body))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("token refresh failed: status=%d body=%s", resp.StatusCode, string(body))
@@ -730,16 +733,16 @@ func (s *OAuthService) refreshTokenWithFacebook(ctx context.Context, accessToken
 		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
 	}
 
-	fmt.Printf("DEBUG: Facebook token refresh successful\n")
-	fmt.Printf("DEBUG: New access token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Token type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	s.debug(ctx, "facebook token refresh successful", "access_token", logger.Redact(token.AccessToken), "token_type", token.TokenType, "expiry", token.Expiry)
 
 	return token, nil
 }
 
 // CreateClient creates an HTTP client with automatic token refresh
 func (s *OAuthService) CreateClient(ctx context.Context, token *oauth2.Token) *http.Client {
+	ctx = s.httpContext(ctx)
 	ts := s.config.TokenSource(ctx, token)
-	return oauth2.NewClient(ctx, ts)
+	client := oauth2.NewClient(ctx, ts)
+	client.Timeout = s.timeout
+	return client
 }
@@ -0,0 +1,89 @@
+package oauth
+
+import (
+	"context"
+	"time"
+
+	"social/internal/storage"
+	"social/pkg/logger"
+)
+
+// RefreshWorker proactively refreshes stored tokens before they expire,
+// so the first request after expiry doesn't have to wait for a refresh
+type RefreshWorker struct {
+	tokenManager *TokenManager
+	storage      storage.Storage
+	logger       *logger.Logger
+	interval     time.Duration
+	window       time.Duration
+}
+
+// NewRefreshWorker creates a new background token refresh worker
+func NewRefreshWorker(tokenManager *TokenManager, storage storage.Storage, logger *logger.Logger, interval, window time.Duration) *RefreshWorker {
+	return &RefreshWorker{
+		tokenManager: tokenManager,
+		storage:      storage,
+		logger:       logger,
+		interval:     interval,
+		window:       window,
+	}
+}
+
+// Run scans tokens every interval until the context is cancelled
+func (w *RefreshWorker) Run(ctx context.Context) {
+	w.logger.Info(ctx, "token refresh worker started", "interval", w.interval.String(), "window", w.window.String())
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.scan(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info(context.Background(), "token refresh worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan refreshes every stored token that expires within the refresh window
+func (w *RefreshWorker) scan(ctx context.Context) {
+	records, err := w.storage.ListTokens(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error(ctx, err, "failed to list tokens for refresh")
+		}
+		return
+	}
+
+	deadline := time.Now().Add(w.window)
+	var refreshed, failed int
+
+	for _, record := range records {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Tokens without a known expiry are refreshed lazily on use instead
+		if record.Token == nil || record.Token.Expiry.IsZero() || record.Token.Expiry.After(deadline) {
+			continue
+		}
+
+		refreshCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		_, err := w.tokenManager.refreshToken(refreshCtx, record.UserID, record.Provider, record.ServerName, record.Token)
+		cancel()
+
+		if err != nil {
+			w.logger.Error(ctx, err, "background token refresh failed", "provider", record.Provider, "user_id", record.UserID, "server_name", record.ServerName)
+			failed++
+			continue
+		}
+		refreshed++
+	}
+
+	if refreshed > 0 || failed > 0 {
+		w.logger.Info(ctx, "token refresh scan completed", "scanned", len(records), "refreshed", refreshed, "failed", failed)
+	}
+}
@@ -2,41 +2,57 @@ package oauth
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 
 	"social/internal/config"
 	"social/internal/storage"
 	"social/pkg/errors"
 	"social/pkg/logger"
+	"social/pkg/metrics"
+	"social/pkg/tracing"
 )
 
 // TokenManager handles token operations including refresh
 type TokenManager struct {
-	config  *config.Config
+	configs config.ConfigProvider
 	storage storage.Storage
 	logger  *logger.Logger
 }
 
 // NewTokenManager creates a new token manager
-func NewTokenManager(cfg *config.Config, storage storage.Storage, logger *logger.Logger) *TokenManager {
+func NewTokenManager(configs config.ConfigProvider, storage storage.Storage, logger *logger.Logger) *TokenManager {
 	return &TokenManager{
-		config:  cfg,
+		configs: configs,
 		storage: storage,
 		logger:  logger,
 	}
 }
 
+// config returns the current configuration
+func (tm *TokenManager) config() *config.Config {
+	return tm.configs.Get()
+}
+
 // GetValidToken retrieves a valid token, refreshing if necessary
 // This method ensures the returned token is valid and not expired
 func (tm *TokenManager) GetValidToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error) {
 	// Get current token from storage
 	token, err := tm.storage.GetToken(ctx, userID, provider, serverName)
 	if err != nil {
-		tm.logger.Error(ctx, err, "token not found", "provider", provider, "user_id", userID, "server_name", serverName)
+		if stderrors.Is(err, storage.ErrInvalidToken) {
+			// Corrupted records can't be used or refreshed, the user has to re-authorize
+			tm.logger.Error(ctx, err, "stored token is invalid, re-authorization required", "provider", provider, "user_id", userID, "server_name", serverName)
+		} else {
+			tm.logger.Error(ctx, err, "token not found", "provider", provider, "user_id", userID, "server_name", serverName)
+		}
 		return nil, errors.ErrTokenNotFound
 	}
 
@@ -60,15 +76,96 @@ func (tm *TokenManager) GetValidToken(ctx context.Context, userID, provider, ser
 	return token, nil
 }
 
-// refreshToken refreshes an expired token
-func (tm *TokenManager) refreshToken(ctx context.Context, userID, provider, serverName string, currentToken *oauth2.Token) (*oauth2.Token, error) {
-	// For Instagram, the refresh token is actually the current access token
-	if provider == "instagram" {
+// Refresh lock timing. The lock outlives any refresh request so it isn't taken over mid-refresh,
+// and a refresh lost together with its instance frees the lock once it expires.
+const (
+	refreshLockTTL  = 30 * time.Second
+	refreshLockWait = 15 * time.Second
+	refreshLockPoll = 200 * time.Millisecond
+)
+
+// refreshToken refreshes an expired token. Concurrent refreshes of the same token, in this or
+// other instances, are serialized by a lock in storage: only one of them calls the provider and
+// the others wait for it and use the token it saved. Providers rotating refresh tokens would
+// otherwise invalidate the tokens the other refreshes got.
+func (tm *TokenManager) refreshToken(ctx context.Context, userID, provider, serverName string, currentToken *oauth2.Token) (token *oauth2.Token, err error) {
+	ctx, span := tracing.Start(ctx, "token.refresh", trace.WithAttributes(
+		attribute.String("provider", provider),
+		attribute.String("server_name", serverName),
+	))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	lockName := fmt.Sprintf("refresh:%s:%s:%s", serverName, provider, userID)
+	owner, err := RandStringURLSafe(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh lock owner: %w", err)
+	}
+
+	deadline := time.Now().Add(refreshLockWait)
+	for {
+		acquired, err := tm.storage.AcquireLock(ctx, lockName, owner, refreshLockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire refresh lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for a concurrent %s token refresh", provider)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(refreshLockPoll):
+		}
+
+		// The lock holder saves the refreshed token before releasing the lock
+		if refreshed, ok := tm.refreshedToken(ctx, userID, provider, serverName, currentToken); ok {
+			tm.logger.Info(ctx, "token refreshed by a concurrent request", "provider", provider, "user_id", userID, "server_name", serverName)
+			return refreshed, nil
+		}
+	}
+	defer func() {
+		if err := tm.storage.ReleaseLock(context.WithoutCancel(ctx), lockName, owner); err != nil {
+			tm.logger.Error(ctx, err, "failed to release refresh lock", "provider", provider, "user_id", userID, "server_name", serverName)
+		}
+	}()
+
+	// The previous lock holder may have refreshed the token between our read and taking the lock
+	if refreshed, ok := tm.refreshedToken(ctx, userID, provider, serverName, currentToken); ok {
+		return refreshed, nil
+	}
+
+	return tm.doRefreshToken(ctx, userID, provider, serverName, currentToken)
+}
+
+// refreshedToken returns the stored token if it has been replaced since currentToken was read and
+// is still valid. It reads past any token cache, other instances' saves don't invalidate it.
+func (tm *TokenManager) refreshedToken(ctx context.Context, userID, provider, serverName string, currentToken *oauth2.Token) (*oauth2.Token, bool) {
+	store := tm.storage
+	if cached, ok := store.(interface{ Unwrap() storage.Storage }); ok {
+		store = cached.Unwrap()
+	}
+
+	token, err := store.GetToken(ctx, userID, provider, serverName)
+	if err != nil || token.AccessToken == currentToken.AccessToken || tm.isTokenExpired(token) {
+		return nil, false
+	}
+	return token, true
+}
+
+// doRefreshToken exchanges the refresh token for a new token and saves it
+func (tm *TokenManager) doRefreshToken(ctx context.Context, userID, provider, serverName string, currentToken *oauth2.Token) (*oauth2.Token, error) {
+	// For Instagram and Threads, the refresh token is actually the current access token
+	if provider == "instagram" || provider == "threads" {
 		if currentToken.AccessToken == "" {
-			tm.logger.Error(ctx, errors.ErrTokenNotFound, "instagram access token not found", "provider", provider, "user_id", userID)
-			return nil, fmt.Errorf("instagram access token not available")
+			tm.logger.Error(ctx, errors.ErrTokenNotFound, "long-lived access token not found", "provider", provider, "user_id", userID)
+			return nil, fmt.Errorf("%s access token not available", provider)
 		}
-		// For Instagram, we use the current access token as the refresh token
+		// The long-lived access token is used as the refresh token
 		currentToken.RefreshToken = currentToken.AccessToken
 	} else {
 		// Check if refresh token exists for other platforms
@@ -79,7 +176,7 @@ func (tm *TokenManager) refreshToken(ctx context.Context, userID, provider, serv
 	}
 
 	// Get OAuth config
-	oauthConfig, err := tm.config.GetServerOAuthConfig(provider, serverName, "")
+	oauthConfig, err := tm.config().GetServerOAuthConfig(provider, serverName, "")
 	if err != nil {
 		tm.logger.Error(ctx, err, "failed to get OAuth config", "provider", provider, "server_name", serverName)
 		return nil, fmt.Errorf("failed to get OAuth config: %w", err)
@@ -87,20 +184,26 @@ func (tm *TokenManager) refreshToken(ctx context.Context, userID, provider, serv
 
 	// Create OAuth service
 	oauthService := NewOAuthService(oauthConfig)
+	oauthService.SetUserAgent(tm.config().GetUserAgent(provider, serverName))
+	oauthService.SetTimeout(tm.config().GetProviderTimeout(provider, serverName))
+	oauthService.SetLogger(tm.logger)
 
 	// Refresh token
 	newToken, err := oauthService.RefreshToken(ctx, currentToken.RefreshToken)
 	if err != nil {
+		metrics.RecordTokenRefresh(provider, err)
 		tm.logger.Error(ctx, err, "token refresh failed", "provider", provider, "user_id", userID)
 		return nil, fmt.Errorf("token refresh failed: %w", err)
 	}
 
 	// Save new token to storage
 	if err := tm.storage.SaveToken(ctx, userID, provider, serverName, newToken); err != nil {
+		metrics.RecordTokenRefresh(provider, err)
 		tm.logger.Error(ctx, err, "failed to save refreshed token", "provider", provider, "user_id", userID)
 		return nil, fmt.Errorf("failed to save refreshed token: %w", err)
 	}
 
+	metrics.RecordTokenRefresh(provider, nil)
 	return newToken, nil
 }
 
@@ -110,6 +213,11 @@ func (tm *TokenManager) isTokenExpired(token *oauth2.Token) bool {
 		return true
 	}
 
+	// Tokens without an expiry or a refresh token never expire (Mastodon)
+	if token.Expiry.IsZero() && token.RefreshToken == "" {
+		return false
+	}
+
 	// If no expiry time is set, consider it expired
 	if token.Expiry.IsZero() {
 		return true
@@ -120,9 +228,38 @@ func (tm *TokenManager) isTokenExpired(token *oauth2.Token) bool {
 	return time.Now().Add(expiryBuffer).After(token.Expiry)
 }
 
+// CheckCredentials reports whether CreateAuthenticatedClient has credentials to work with, without
+// refreshing or calling the provider: sandboxed providers and bot tokens need no user token, others
+// need a stored one. It is the pre-check of shares that are posted later.
+func (tm *TokenManager) CheckCredentials(ctx context.Context, userID, provider, serverName string) error {
+	if tm.config().Sandbox.IsSandboxed(provider) || tm.config().GetBotToken(provider, serverName) != "" {
+		return nil
+	}
+
+	if _, err := tm.storage.GetToken(ctx, userID, provider, serverName); err != nil {
+		tm.logger.Error(ctx, err, "token not found", "provider", provider, "user_id", userID, "server_name", serverName)
+		return errors.ErrTokenNotFound
+	}
+	return nil
+}
+
 // CreateAuthenticatedClient creates an HTTP client with automatic token refresh
 // This method ensures the client always has a valid token
 func (tm *TokenManager) CreateAuthenticatedClient(ctx context.Context, userID, provider, serverName string) (*http.Client, error) {
+	// Sandbox platforms never call the provider, so no token is needed
+	if tm.config().Sandbox.IsSandboxed(provider) {
+		return &http.Client{}, nil
+	}
+
+	// Bots post with the server's bot token, so no user token is needed
+	if botToken := tm.config().GetBotToken(provider, serverName); botToken != "" {
+		client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: botToken, TokenType: "Bot"}))
+		client.Timeout = tm.config().GetProviderTimeout(provider, serverName)
+		withCircuitBreaker(client, provider)
+		withRateLimits(client, provider, serverName+":bot")
+		return client, nil
+	}
+
 	// Get a valid token (refreshing if necessary)
 	token, err := tm.GetValidToken(ctx, userID, provider, serverName)
 	if err != nil {
@@ -130,16 +267,45 @@ func (tm *TokenManager) CreateAuthenticatedClient(ctx context.Context, userID, p
 	}
 
 	// Get OAuth config
-	oauthConfig, err := tm.config.GetServerOAuthConfig(provider, serverName, "")
+	oauthConfig, err := tm.config().GetServerOAuthConfig(provider, serverName, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OAuth config: %w", err)
 	}
 
 	// Create OAuth service
 	oauthService := NewOAuthService(oauthConfig)
+	oauthService.SetUserAgent(tm.config().GetUserAgent(provider, serverName))
+	oauthService.SetTimeout(tm.config().GetProviderTimeout(provider, serverName))
+	oauthService.SetLogger(tm.logger)
 
 	// Create client with automatic token refresh
 	client := oauthService.CreateClient(ctx, token)
+	withCircuitBreaker(client, provider)
+	withRateLimits(client, provider, serverName+":"+userID)
+
+	// Self-hosted providers are addressed at their default instance and routed to the server's one
+	if instanceURL := tm.config().GetInstanceURL(provider, serverName); instanceURL != "" && instanceURL != config.DefaultMastodonInstance {
+		if parsed, err := url.Parse(instanceURL); err == nil {
+			defaultInstance, _ := url.Parse(config.DefaultMastodonInstance)
+			client.Transport = &hostOverrideTransport{
+				base:        client.Transport,
+				defaultHost: defaultInstance.Host,
+				host:        parsed.Host,
+			}
+		}
+	}
+
+	// Route API calls to the configured host if the default one is overridden
+	if host := tm.config().GetProviderAPIHost(provider, serverName); host != "" {
+		defaultHost := config.ProviderAPIHosts[provider][0]
+		if host != defaultHost {
+			client.Transport = &hostOverrideTransport{
+				base:        client.Transport,
+				defaultHost: defaultHost,
+				host:        host,
+			}
+		}
+	}
 
 	return client, nil
 }
@@ -154,6 +320,21 @@ func (tm *TokenManager) IsTokenValid(ctx context.Context, userID, provider, serv
 	return !tm.isTokenExpired(token), nil
 }
 
+// GetTokenStatus reports whether a stored token is valid and when it expires, without refreshing
+func (tm *TokenManager) GetTokenStatus(ctx context.Context, userID, provider, serverName string) (bool, int64, error) {
+	token, err := tm.storage.GetToken(ctx, userID, provider, serverName)
+	if err != nil {
+		return false, 0, err
+	}
+
+	var expiresAt int64
+	if !token.Expiry.IsZero() {
+		expiresAt = token.Expiry.Unix()
+	}
+
+	return !tm.isTokenExpired(token), expiresAt, nil
+}
+
 // ForceRefreshToken forces a token refresh regardless of expiry status
 func (tm *TokenManager) ForceRefreshToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error) {
 	// Get current token from storage
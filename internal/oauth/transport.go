@@ -0,0 +1,93 @@
+package oauth
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"social/pkg/breaker"
+	"social/pkg/quota"
+)
+
+// hostOverrideTransport rewrites requests for a provider's default API host
+// to a configured alternate host, leaving all other requests untouched
+type hostOverrideTransport struct {
+	base        http.RoundTripper
+	defaultHost string
+	host        string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *hostOverrideTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if req.URL.Host != t.defaultHost {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the original request
+	clone := req.Clone(req.Context())
+	clone.URL.Host = t.host
+	clone.Host = t.host
+
+	return base.RoundTrip(clone)
+}
+
+// Unwrap returns the wrapped transport, so platforms can reach the oauth2 transport underneath
+func (t *hostOverrideTransport) Unwrap() http.RoundTripper {
+	return t.base
+}
+
+// WithBase returns a copy of the override wrapping base, so platforms can swap the oauth2 transport
+// underneath for one with another access token and keep the override
+func (t *hostOverrideTransport) WithBase(base http.RoundTripper) http.RoundTripper {
+	clone := *t
+	clone.base = base
+	return &clone
+}
+
+// userAgentTransport sets the User-Agent header on every request, which providers such as
+// Reddit require to identify the application
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// RoundTrippers must not modify the original request
+	clone := req.Clone(req.Context())
+	clone.Header.Set("User-Agent", t.userAgent)
+
+	return base.RoundTrip(clone)
+}
+
+// withCircuitBreaker routes a client's API calls through the provider's circuit breaker. The
+// breaker sits beneath the oauth2 transport, so clients platforms derive from it with another
+// access token (such as Facebook Page tokens) keep going through the breaker.
+func withCircuitBreaker(client *http.Client, provider string) {
+	if t, ok := client.Transport.(*oauth2.Transport); ok {
+		t.Base = breaker.Transport(provider, t.Base)
+		return
+	}
+	client.Transport = breaker.Transport(provider, client.Transport)
+}
+
+// withRateLimits tracks the rate limit windows of a client's API calls, owner tells apart the
+// tokens of a provider. It sits above the circuit breaker, so calls rejected until a window
+// resets don't count as provider failures, and beneath the oauth2 transport like the breaker.
+func withRateLimits(client *http.Client, provider, owner string) {
+	if t, ok := client.Transport.(*oauth2.Transport); ok {
+		t.Base = quota.Transport(provider, owner, t.Base)
+		return
+	}
+	client.Transport = quota.Transport(provider, owner, client.Transport)
+}
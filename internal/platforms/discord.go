@@ -0,0 +1,395 @@
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"social/internal/types"
+	"social/pkg/httpx"
+)
+
+// discordAPIBaseURL is the versioned Discord REST API
+const discordAPIBaseURL = "https://discord.com/api/v10"
+
+// Discord message limits
+const (
+	discordMaxContentLength    = 2000
+	discordMaxEmbedTitleLength = 256
+)
+
+// discordWebhookHosts are the hosts Discord issues webhook URLs on
+var discordWebhookHosts = map[string]bool{
+	"discord.com":        true,
+	"discordapp.com":     true,
+	"ptb.discord.com":    true,
+	"canary.discord.com": true,
+}
+
+// DiscordPlatform implements the Discord platform. Messages are posted to a channel by the
+// server's bot, whose token the authenticated client sends, or to the webhook URL of a request.
+type DiscordPlatform struct{}
+
+// NewDiscordPlatform creates a new Discord platform instance
+func NewDiscordPlatform() *DiscordPlatform {
+	return &DiscordPlatform{}
+}
+
+// GetName returns the platform name
+func (d *DiscordPlatform) GetName() string {
+	return "discord"
+}
+
+// Capabilities reports what Discord supports, messages have no engagement stats and a channel's
+// history isn't a user's posts
+func (d *DiscordPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:           true,
+		CanDelete:          true,
+		SupportsScheduling: true,
+	}
+}
+
+// discordMessage is a message as returned by the Discord API
+type discordMessage struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channel_id"`
+	Content     string `json:"content"`
+	Timestamp   string `json:"timestamp"`
+	Attachments []struct {
+		URL         string `json:"url"`
+		ContentType string `json:"content_type"`
+	} `json:"attachments"`
+	Embeds []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Image       *struct {
+			URL string `json:"url"`
+		} `json:"image"`
+	} `json:"embeds"`
+}
+
+// apiError builds an error from a non-2xx Discord response
+func (d *DiscordPlatform) apiError(operation string, statusCode int, body []byte) error {
+	var errorResponse struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Message != "" {
+		return platformError(statusCode, body, fmt.Errorf("discord %sapi error (%d): %s (code %d)", operation, statusCode, errorResponse.Message, errorResponse.Code))
+	}
+	return platformError(statusCode, body, fmt.Errorf("discord %sapi error: status=%d body=%s", operation, statusCode, string(body)))
+}
+
+// SharesWithoutAuth reports whether req is posted to a webhook, whose URL carries its own token
+func (d *DiscordPlatform) SharesWithoutAuth(req *types.ShareRequest) bool {
+	return req.WebhookURL != ""
+}
+
+// Validate checks that a share request can be posted to Discord
+func (d *DiscordPlatform) Validate(req *types.ShareRequest) error {
+	if req.WebhookURL != "" {
+		if err := validateDiscordWebhookURL(req.WebhookURL); err != nil {
+			return err
+		}
+	} else if req.ChannelID == "" {
+		return types.NewValidationError("channel_id or webhook_url is required for discord messages")
+	} else if !isNumeric(req.ChannelID) {
+		return types.NewValidationError("discord channel_id must be a numeric channel ID")
+	}
+
+	if req.MediaURL == "" && strings.TrimSpace(req.Content) == "" {
+		return types.NewValidationError("content or media_url required for discord messages")
+	}
+	if utf8.RuneCountInString(req.Content) > discordMaxContentLength {
+		return types.NewValidationError("discord message exceeds %d characters", discordMaxContentLength)
+	}
+	if utf8.RuneCountInString(req.Title) > discordMaxEmbedTitleLength {
+		return types.NewValidationError("discord embed title exceeds %d characters", discordMaxEmbedTitleLength)
+	}
+	return nil
+}
+
+// validateDiscordWebhookURL checks that a webhook URL points at Discord's webhook endpoint,
+// so requests can't make the service post to arbitrary hosts
+func validateDiscordWebhookURL(webhookURL string) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Scheme != "https" || !discordWebhookHosts[parsed.Host] {
+		return types.NewValidationError("webhook_url must be a https discord webhook url")
+	}
+	if !strings.HasPrefix(parsed.Path, "/api/webhooks/") && !strings.HasPrefix(parsed.Path, "/api/v10/webhooks/") {
+		return types.NewValidationError("webhook_url must be a https discord webhook url")
+	}
+	return nil
+}
+
+// Share posts a message, with the media as an embedded image, to the request's webhook or to the
+// channel as the server's bot. The media ID is {channel_id}_{message_id}.
+func (d *DiscordPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := d.Validate(req); err != nil {
+		return "", err
+	}
+
+	messageData := map[string]any{
+		"content": req.Content,
+		// Mentions in shared content must not ping the whole server
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	}
+	if req.MediaURL != "" {
+		embed := map[string]any{
+			"image": map[string]string{"url": req.MediaURL},
+		}
+		if req.Title != "" {
+			embed["title"] = req.Title
+		}
+		if req.Desc != "" {
+			embed["description"] = req.Desc
+		}
+		messageData["embeds"] = []any{embed}
+	}
+
+	jsonData, err := json.Marshal(messageData)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal discord message request: %w", err)
+	}
+
+	messageURL := fmt.Sprintf("%s/channels/%s/messages", discordAPIBaseURL, req.ChannelID)
+	if req.WebhookURL != "" {
+		webhookURL, _ := url.Parse(req.WebhookURL)
+		query := webhookURL.Query()
+		// wait=true makes Discord return the created message instead of 204
+		query.Set("wait", "true")
+		webhookURL.RawQuery = query.Encode()
+		messageURL = webhookURL.String()
+		// The webhook URL carries its own token, the bot's must not be sent along to it. Handlers
+		// already pass a plain client for webhook requests, see SharesWithoutAuth.
+		client = &http.Client{Timeout: client.Timeout}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", messageURL, bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create discord message request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send discord message request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read discord message response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", d.apiError("", resp.StatusCode, body)
+	}
+
+	var message discordMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return "", fmt.Errorf("failed to parse discord message response: %w", err)
+	}
+
+	return message.ChannelID + "_" + message.ID, nil
+}
+
+// splitDiscordMediaID splits a media ID into its channel and message IDs
+func splitDiscordMediaID(mediaID string) (string, string, error) {
+	channelID, messageID, ok := strings.Cut(mediaID, "_")
+	if !ok || channelID == "" || messageID == "" {
+		return "", "", fmt.Errorf("invalid discord media_id %q, expected {channel_id}_{message_id}", mediaID)
+	}
+	return channelID, messageID, nil
+}
+
+// GetStats is not supported, Discord messages have no engagement metrics
+func (d *DiscordPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	return types.StatsData{}, fmt.Errorf("discord messages have no stats: %w", types.ErrOperationNotSupported)
+}
+
+// GetPost retrieves a message posted by Share, the bot must be able to read the channel
+func (d *DiscordPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	if mediaID == "" {
+		return types.Post{}, fmt.Errorf("media_id required")
+	}
+	channelID, messageID, err := splitDiscordMediaID(mediaID)
+	if err != nil {
+		return types.Post{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/channels/%s/messages/%s", discordAPIBaseURL, url.PathEscape(channelID), url.PathEscape(messageID)), nil)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to create discord post request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to get discord post: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to read discord post response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.Post{}, d.apiError("post ", resp.StatusCode, body)
+	}
+
+	var message discordMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return types.Post{}, fmt.Errorf("failed to parse discord post response: %w", err)
+	}
+
+	return discordPost(mediaID, message), nil
+}
+
+// discordPost converts a message to a post
+func discordPost(mediaID string, message discordMessage) types.Post {
+	createdTime, err := time.Parse(time.RFC3339, message.Timestamp)
+	if err != nil {
+		createdTime = time.Now()
+	}
+
+	post := types.Post{
+		ID:        mediaID,
+		Content:   message.Content,
+		CreatedAt: createdTime.Unix(),
+		Tags:      []string{},
+	}
+
+	if len(message.Embeds) > 0 {
+		embed := message.Embeds[0]
+		post.Title = embed.Title
+		post.Description = embed.Description
+		if embed.Image != nil {
+			post.MediaURL = embed.Image.URL
+			post.MediaType = "image"
+		}
+	}
+	if post.MediaURL == "" && len(message.Attachments) > 0 {
+		attachment := message.Attachments[0]
+		post.MediaURL = attachment.URL
+		post.MediaType = "image"
+		if strings.HasPrefix(attachment.ContentType, "video/") {
+			post.MediaType = "video"
+		}
+	}
+
+	return post
+}
+
+// DeletePost deletes a message, the bot must have posted it or be allowed to manage messages
+func (d *DiscordPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+	channelID, messageID, err := splitDiscordMediaID(mediaID)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/channels/%s/messages/%s", discordAPIBaseURL, url.PathEscape(channelID), url.PathEscape(messageID)), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create discord delete request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send discord delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read discord delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return d.apiError("delete ", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetUserInfo retrieves the account the client authenticates as, the bot when a bot token is configured
+func (d *DiscordPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types.UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", discordAPIBaseURL+"/users/@me", nil)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to read user info response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.UserInfo{}, d.apiError("user info ", resp.StatusCode, body)
+	}
+
+	var user struct {
+		ID         string `json:"id"`
+		Username   string `json:"username"`
+		GlobalName string `json:"global_name"`
+		Avatar     string `json:"avatar"`
+		Verified   bool   `json:"verified"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to parse user info response: %w", err)
+	}
+
+	displayName := user.GlobalName
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	var avatarURL string
+	if user.Avatar != "" {
+		avatarURL = fmt.Sprintf("https://cdn.discordapp.com/avatars/%s/%s.png", user.ID, user.Avatar)
+	}
+
+	return types.UserInfo{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: displayName,
+		AvatarURL:   avatarURL,
+		Verified:    user.Verified, // email verification, Discord has no account verification badge
+	}, nil
+}
+
+// GetRecentPosts is not supported, Discord has no per-user message history
+func (d *DiscordPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	return nil, fmt.Errorf("discord has no listing of a user's messages: %w", types.ErrOperationNotSupported)
+}
+
+// HandleOAuthCallback handles OAuth callback for Discord platform
+func (d *DiscordPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
+	// Discord平台特定的OAuth回调处理逻辑
+	return nil
+}
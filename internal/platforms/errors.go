@@ -0,0 +1,96 @@
+package platforms
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+
+	"social/internal/types"
+)
+
+// ErrorCategory classifies a platform API failure so handlers can map it to an API error
+type ErrorCategory string
+
+// Platform error categories
+const (
+	CategoryRateLimited    ErrorCategory = "rate_limited"    // too many requests, retry later
+	CategoryAuthFailed     ErrorCategory = "auth_failed"     // the platform rejected the token
+	CategorySuspended      ErrorCategory = "suspended"       // the account is suspended or locked
+	CategoryNotFound       ErrorCategory = "not_found"       // the post or account doesn't exist
+	CategoryInvalidContent ErrorCategory = "invalid_content" // the platform refused the content itself
+	CategoryAccessLevel    ErrorCategory = "access_level"    // the app's API tier doesn't include the operation
+	CategoryUpstream       ErrorCategory = "upstream"        // any other platform failure
+)
+
+// PlatformError is an error response from a platform API, carrying its category and the original message
+type PlatformError struct {
+	Category   ErrorCategory
+	StatusCode int
+	Err        error
+
+	missingScope bool
+}
+
+// Error implements the error interface
+func (e *PlatformError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes the original error, and ErrInsufficientScope for scope rejections
+func (e *PlatformError) Unwrap() []error {
+	if e.missingScope {
+		return []error{e.Err, types.ErrInsufficientScope}
+	}
+	return []error{e.Err}
+}
+
+// AsPlatformError returns the PlatformError in err's chain, if any
+func AsPlatformError(err error) (*PlatformError, bool) {
+	var platformErr *PlatformError
+	ok := stderrors.As(err, &platformErr)
+	return platformErr, ok
+}
+
+// platformError wraps err, built from a non-2xx response, in a PlatformError categorized by the
+// response's status code and body
+func platformError(statusCode int, body []byte, err error) error {
+	return &PlatformError{
+		Category:     errorCategory(statusCode, body),
+		StatusCode:   statusCode,
+		Err:          err,
+		missingScope: isScopeRejection(statusCode, body),
+	}
+}
+
+// googleError wraps a Google API client error in a PlatformError, other errors are returned unchanged
+func googleError(err error) error {
+	var apiErr *googleapi.Error
+	if !stderrors.As(err, &apiErr) {
+		return err
+	}
+	return platformError(apiErr.Code, []byte(apiErr.Body+" "+apiErr.Message), err)
+}
+
+// errorCategory categorizes an error response. Some providers report rate limits, suspensions
+// and invalid tokens with a generic status, so the body is checked first.
+func errorCategory(statusCode int, body []byte) ErrorCategory {
+	text := strings.ToLower(string(body))
+
+	switch {
+	case strings.Contains(text, "suspended"):
+		return CategorySuspended
+	case statusCode == http.StatusTooManyRequests || strings.Contains(text, "rate limit"):
+		return CategoryRateLimited
+	case statusCode == http.StatusUnauthorized || strings.Contains(text, "invalid_token") || strings.Contains(text, `"code":190`):
+		return CategoryAuthFailed
+	case statusCode == http.StatusNotFound:
+		return CategoryNotFound
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusRequestEntityTooLarge ||
+		statusCode == http.StatusUnprocessableEntity || strings.Contains(text, "duplicate"):
+		return CategoryInvalidContent
+	default:
+		return CategoryUpstream
+	}
+}
@@ -6,12 +6,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"social/internal/types"
+	"social/pkg/httpx"
 )
 
+// Facebook accepts scheduled_publish_time between 10 minutes and 6 months ahead
+const (
+	facebookMinScheduleLead       = 10 * time.Minute
+	facebookMaxScheduleLeadMonths = 6
+)
+
+// facebookMaxMessageLength is the longest post message the Graph API accepts
+const facebookMaxMessageLength = 63206
+
 // FacebookPlatform implements the Facebook platform
 type FacebookPlatform struct{}
 
@@ -25,14 +37,64 @@ func (f *FacebookPlatform) GetName() string {
 	return "facebook"
 }
 
-// Share shares content to Facebook
-func (f *FacebookPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
-	// Facebook Graph API requires page access token and page ID
-	// For now, we'll implement basic user feed posting
-	// In production, you should use page access tokens for business accounts
+// Capabilities reports what Facebook supports, posts can be text only
+func (f *FacebookPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+	}
+}
+
+// SchedulesNatively reports that Facebook publishes scheduled posts itself through scheduled_publish_time
+func (f *FacebookPlatform) SchedulesNatively() bool {
+	return true
+}
 
+// Validate checks that a share request can be posted to Facebook
+func (f *FacebookPlatform) Validate(req *types.ShareRequest) error {
 	if strings.TrimSpace(req.Content) == "" {
-		return "", fmt.Errorf("content required for facebook post")
+		return types.NewValidationError("content required for facebook post")
+	}
+	if utf8.RuneCountInString(req.Content) > facebookMaxMessageLength {
+		return types.NewValidationError("facebook post text exceeds %d characters", facebookMaxMessageLength)
+	}
+
+	now := time.Now()
+	if req.ScheduledAt > now.Unix() {
+		// Only Page posts can be scheduled, the Graph API rejects scheduled posts to a user's feed
+		if req.PageID == "" {
+			return types.NewValidationError("page_id is required to schedule facebook posts")
+		}
+		scheduledAt := time.Unix(req.ScheduledAt, 0)
+		if scheduledAt.Before(now.Add(facebookMinScheduleLead)) {
+			return types.NewValidationError("facebook posts must be scheduled at least %s ahead", facebookMinScheduleLead)
+		}
+		if scheduledAt.After(now.AddDate(0, facebookMaxScheduleLeadMonths, 0)) {
+			return types.NewValidationError("facebook posts can be scheduled at most %d months ahead", facebookMaxScheduleLeadMonths)
+		}
+	}
+	return nil
+}
+
+// Share shares content to Facebook. With a page_id the post is made to that Page with its page
+// access token, otherwise to the user's own feed. Posts with a future scheduled_at are created
+// unpublished and published by Facebook at that time, the returned ID is the scheduled post's.
+func (f *FacebookPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := f.Validate(req); err != nil {
+		return "", err
+	}
+
+	feedURL := "https://graph.facebook.com/me/feed"
+	if req.PageID != "" {
+		pageToken, err := f.pageAccessToken(ctx, client, req.PageID)
+		if err != nil {
+			return "", err
+		}
+		client = clientWithAccessToken(client, pageToken)
+		feedURL = fmt.Sprintf("https://graph.facebook.com/%s/feed", url.PathEscape(req.PageID))
 	}
 
 	// Prepare post data
@@ -47,13 +109,18 @@ func (f *FacebookPlatform) Share(ctx context.Context, client *http.Client, req *
 		postData["link"] = req.MediaURL
 	}
 
+	if req.ScheduledAt > time.Now().Unix() {
+		postData["published"] = false
+		postData["scheduled_publish_time"] = req.ScheduledAt
+	}
+
 	jsonData, err := json.Marshal(postData)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal facebook post request: %w", err)
 	}
 
-	// Post to user's feed
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://graph.facebook.com/me/feed", strings.NewReader(string(jsonData)))
+	// Post to the page's or the user's feed
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", feedURL, strings.NewReader(string(jsonData)))
 	if err != nil {
 		return "", fmt.Errorf("failed to create facebook post request: %w", err)
 	}
@@ -99,10 +166,61 @@ func (f *FacebookPlatform) Share(ctx context.Context, client *http.Client, req *
 	}
 
 	if err := json.Unmarshal(body, &errorResponse); err == nil {
-		return "", fmt.Errorf("facebook api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+		return "", platformError(resp.StatusCode, body, fmt.Errorf("facebook api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
+	}
+
+	return "", platformError(resp.StatusCode, body, fmt.Errorf("facebook api error: status=%d body=%s", resp.StatusCode, string(body)))
+}
+
+// pageAccessToken exchanges the user's token for the access token of a Page they manage
+func (f *FacebookPlatform) pageAccessToken(ctx context.Context, client *http.Client, pageID string) (string, error) {
+	endpoint := fmt.Sprintf("https://graph.facebook.com/%s?fields=access_token", url.PathEscape(pageID))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create facebook page token request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get facebook page token: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read facebook page token response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errorResponse struct {
+			Error struct {
+				Message string `json:"message"`
+				Code    int    `json:"code"`
+			} `json:"error"`
+		}
+
+		if err := json.Unmarshal(body, &errorResponse); err == nil {
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("facebook page token api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
+		}
+
+		return "", platformError(resp.StatusCode, body, fmt.Errorf("facebook page token api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var pageResponse struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &pageResponse); err != nil {
+		return "", fmt.Errorf("failed to parse facebook page token response: %w", err)
 	}
 
-	return "", fmt.Errorf("facebook api error: status=%d body=%s", resp.StatusCode, string(body))
+	// The field is left out when the user doesn't manage the page
+	if pageResponse.AccessToken == "" {
+		return "", types.NewValidationError("no access token for facebook page %s, the user must manage the page and grant pages_manage_posts", pageID)
+	}
+
+	return pageResponse.AccessToken, nil
 }
 
 // GetStats retrieves statistics from Facebook
@@ -119,7 +237,7 @@ func (f *FacebookPlatform) GetStats(ctx context.Context, client *http.Client, me
 		return types.StatsData{}, fmt.Errorf("failed to create facebook stats request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.StatsData{}, fmt.Errorf("failed to get facebook stats: %w", err)
 	}
@@ -145,39 +263,108 @@ func (f *FacebookPlatform) GetStats(ctx context.Context, client *http.Client, me
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.StatsData{}, fmt.Errorf("facebook stats api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return types.StatsData{}, platformError(resp.StatusCode, body, fmt.Errorf("facebook stats api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return types.StatsData{}, fmt.Errorf("facebook stats api error: status=%d body=%s", resp.StatusCode, string(body))
+		return types.StatsData{}, platformError(resp.StatusCode, body, fmt.Errorf("facebook stats api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
 	var statsResponse struct {
-		Likes struct {
+		Likes *struct {
 			Summary struct {
 				TotalCount int `json:"total_count"`
 			} `json:"summary"`
 		} `json:"likes"`
-		Comments struct {
+		Comments *struct {
 			Summary struct {
 				TotalCount int `json:"total_count"`
 			} `json:"summary"`
 		} `json:"comments"`
 		Shares struct {
 			Count int `json:"count"`
-		} `json:"shares"`
+		} `json:"shares"` // omitted when the post has no shares
 	}
 
 	if err := json.Unmarshal(body, &statsResponse); err != nil {
 		return types.StatsData{}, fmt.Errorf("failed to parse facebook stats response: %w", err)
 	}
 
-	return types.StatsData{
-		Likes:    statsResponse.Likes.Summary.TotalCount,
-		Replies:  statsResponse.Comments.Summary.TotalCount,
+	// Likes and comments are left out when the token can't read them
+	stats := types.StatsData{
 		Shares:   statsResponse.Shares.Count,
 		Retweets: 0, // Facebook doesn't have retweets
-	}, nil
+	}
+	var missing []string
+	if statsResponse.Likes != nil {
+		stats.Likes = statsResponse.Likes.Summary.TotalCount
+	} else {
+		missing = append(missing, metricLikes)
+	}
+	if statsResponse.Comments != nil {
+		stats.Replies = statsResponse.Comments.Summary.TotalCount
+	} else {
+		missing = append(missing, metricReplies)
+	}
+
+	return fetchedStats(stats, missing...), nil
+}
+
+// DeletePost deletes a Facebook post
+func (f *FacebookPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("https://graph.facebook.com/%s", mediaID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create facebook delete request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send facebook delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read facebook delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Parse error response
+		var errorResponse struct {
+			Error struct {
+				Message   string `json:"message"`
+				Type      string `json:"type"`
+				Code      int    `json:"code"`
+				SubCode   int    `json:"error_subcode,omitempty"`
+				FBTraceID string `json:"fbtrace_id"`
+			} `json:"error"`
+		}
+
+		if err := json.Unmarshal(body, &errorResponse); err == nil {
+			return platformError(resp.StatusCode, body, fmt.Errorf("facebook delete api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
+		}
+
+		return platformError(resp.StatusCode, body, fmt.Errorf("facebook delete api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var deleteResponse struct {
+		Success bool `json:"success"`
+	}
+	if err := json.Unmarshal(body, &deleteResponse); err != nil {
+		return fmt.Errorf("failed to parse facebook delete response: %w", err)
+	}
+
+	if !deleteResponse.Success {
+		return fmt.Errorf("facebook api did not delete post %s", mediaID)
+	}
+
+	return nil
 }
 
 // GetUserInfo retrieves user information from Facebook platform
@@ -189,7 +376,7 @@ func (f *FacebookPlatform) GetUserInfo(ctx context.Context, client *http.Client)
 		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
 	}
@@ -215,10 +402,10 @@ func (f *FacebookPlatform) GetUserInfo(ctx context.Context, client *http.Client)
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.UserInfo{}, fmt.Errorf("facebook user info api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("facebook user info api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return types.UserInfo{}, fmt.Errorf("facebook user info api error: status=%d body=%s", resp.StatusCode, string(body))
+		return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("facebook user info api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
@@ -265,7 +452,7 @@ func (f *FacebookPlatform) GetRecentPosts(ctx context.Context, client *http.Clie
 	}
 
 	// Build query parameters
-	params := fmt.Sprintf("limit=%d&fields=id,message,created_time,updated_time,likes.summary(true),comments.summary(true),shares", limit)
+	params := fmt.Sprintf("limit=%d&fields=%s", limit, facebookPostFields)
 
 	// Add time range filters if provided
 	if startTime > 0 {
@@ -283,7 +470,7 @@ func (f *FacebookPlatform) GetRecentPosts(ctx context.Context, client *http.Clie
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -297,30 +484,12 @@ func (f *FacebookPlatform) GetRecentPosts(ctx context.Context, client *http.Clie
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("facebook api error: status=%d body=%s", resp.StatusCode, string(body))
+		return nil, platformError(resp.StatusCode, body, fmt.Errorf("facebook api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
 	var postsResponse struct {
-		Data []struct {
-			ID          string `json:"id"`
-			Message     string `json:"message"`
-			CreatedTime string `json:"created_time"`
-			UpdatedTime string `json:"updated_time,omitempty"`
-			Likes       struct {
-				Summary struct {
-					TotalCount int `json:"total_count"`
-				} `json:"summary"`
-			} `json:"likes"`
-			Comments struct {
-				Summary struct {
-					TotalCount int `json:"total_count"`
-				} `json:"summary"`
-			} `json:"comments"`
-			Shares struct {
-				Count int `json:"count"`
-			} `json:"shares"`
-		} `json:"data"`
+		Data []facebookFeedPost `json:"data"`
 	}
 
 	if err := json.Unmarshal(body, &postsResponse); err != nil {
@@ -330,42 +499,103 @@ func (f *FacebookPlatform) GetRecentPosts(ctx context.Context, client *http.Clie
 	// Convert to Post structs
 	var posts []types.Post
 	for _, post := range postsResponse.Data {
-		// Parse created time
-		createdTime, err := time.Parse(time.RFC3339, post.CreatedTime)
-		if err != nil {
-			createdTime = time.Now()
-		}
+		posts = append(posts, facebookPost(post))
+	}
 
-		// Parse updated time if available
-		var updatedTime int64
-		if post.UpdatedTime != "" {
-			if parsed, err := time.Parse(time.RFC3339, post.UpdatedTime); err == nil {
-				updatedTime = parsed.Unix()
-			}
-		}
+	return posts, nil
+}
 
-		// Build post URL
-		postURL := fmt.Sprintf("https://www.facebook.com/%s", post.ID)
-
-		postData := types.Post{
-			ID:        post.ID,
-			Content:   post.Message,
-			CreatedAt: createdTime.Unix(),
-			UpdatedAt: updatedTime,
-			Stats: types.StatsData{
-				Likes:    post.Likes.Summary.TotalCount,
-				Replies:  post.Comments.Summary.TotalCount,
-				Shares:   post.Shares.Count,
-				Retweets: 0, // Facebook doesn't have retweets
-			},
-			URL:       postURL,
-			MediaType: "text", // Default to text, could be enhanced to detect media
-		}
+// GetPost retrieves a single Facebook post by ID
+func (f *FacebookPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	if mediaID == "" {
+		return types.Post{}, fmt.Errorf("media_id required")
+	}
+
+	url := fmt.Sprintf("https://graph.facebook.com/%s?fields=%s", mediaID, facebookPostFields)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to create facebook post request: %w", err)
+	}
 
-		posts = append(posts, postData)
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to get facebook post: %w", err)
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
-	return posts, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to read facebook post response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.Post{}, platformError(resp.StatusCode, body, fmt.Errorf("facebook api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var post facebookFeedPost
+	if err := json.Unmarshal(body, &post); err != nil {
+		return types.Post{}, fmt.Errorf("failed to parse facebook post response: %w", err)
+	}
+
+	return facebookPost(post), nil
+}
+
+// facebookPostFields are the Graph API fields requested for posts
+const facebookPostFields = "id,message,created_time,updated_time,likes.summary(true),comments.summary(true),shares"
+
+// facebookFeedPost is a post as returned by the Graph API
+type facebookFeedPost struct {
+	ID          string `json:"id"`
+	Message     string `json:"message"`
+	CreatedTime string `json:"created_time"`
+	UpdatedTime string `json:"updated_time,omitempty"`
+	Likes       struct {
+		Summary struct {
+			TotalCount int `json:"total_count"`
+		} `json:"summary"`
+	} `json:"likes"`
+	Comments struct {
+		Summary struct {
+			TotalCount int `json:"total_count"`
+		} `json:"summary"`
+	} `json:"comments"`
+	Shares struct {
+		Count int `json:"count"`
+	} `json:"shares"`
+}
+
+// facebookPost converts a Graph API post to a post
+func facebookPost(post facebookFeedPost) types.Post {
+	// Parse created time
+	createdTime, err := time.Parse(time.RFC3339, post.CreatedTime)
+	if err != nil {
+		createdTime = time.Now()
+	}
+
+	// Parse updated time if available
+	var updatedTime int64
+	if post.UpdatedTime != "" {
+		if parsed, err := time.Parse(time.RFC3339, post.UpdatedTime); err == nil {
+			updatedTime = parsed.Unix()
+		}
+	}
+
+	return types.Post{
+		ID:        post.ID,
+		Content:   post.Message,
+		CreatedAt: createdTime.Unix(),
+		UpdatedAt: updatedTime,
+		Stats: fetchedStats(types.StatsData{
+			Likes:    post.Likes.Summary.TotalCount,
+			Replies:  post.Comments.Summary.TotalCount,
+			Shares:   post.Shares.Count,
+			Retweets: 0, // Facebook doesn't have retweets
+		}),
+		URL:       fmt.Sprintf("https://www.facebook.com/%s", post.ID),
+		MediaType: "text", // Default to text, could be enhanced to detect media
+	}
 }
 
 // HandleOAuthCallback handles OAuth callback for Facebook platform
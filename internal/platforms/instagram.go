@@ -2,22 +2,57 @@ package platforms
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"social/internal/types"
+	"social/pkg/httpx"
+	"social/pkg/media"
 )
 
+// Instagram carousel size limits
+const (
+	instagramMinCarouselItems = 2
+	instagramMaxCarouselItems = 10
+)
+
+// instagramMaxCaptionLength is the longest caption Instagram accepts on a post
+const instagramMaxCaptionLength = 2200
+
+// Instagram Reels container processing poll settings
+const (
+	instagramContainerPollInterval = 5 * time.Second
+	instagramContainerMaxPolls     = 60
+)
+
+// instagramUserIDTTL is how long the Instagram user ID resolved for a token is reused
+const instagramUserIDTTL = 24 * time.Hour
+
 // InstagramPlatform implements the Instagram platform
-type InstagramPlatform struct{}
+type InstagramPlatform struct {
+	mu      sync.Mutex
+	userIDs map[string]instagramUserID // keyed by a hash of the access token
+}
+
+// instagramUserID is a resolved Instagram Business Account ID and when to resolve it again
+type instagramUserID struct {
+	id        string
+	expiresAt time.Time
+}
 
 // NewInstagramPlatform creates a new Instagram platform instance
 func NewInstagramPlatform() *InstagramPlatform {
-	return &InstagramPlatform{}
+	return &InstagramPlatform{
+		userIDs: make(map[string]instagramUserID),
+	}
 }
 
 // GetName returns the platform name
@@ -25,29 +60,191 @@ func (i *InstagramPlatform) GetName() string {
 	return "instagram"
 }
 
+// Capabilities reports what Instagram supports, posts need media and the API can't delete them
+func (i *InstagramPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		RequiresMedia:       true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+		SupportsVideo:       true,
+	}
+}
+
+// Validate checks that a share request can be posted to Instagram
+func (i *InstagramPlatform) Validate(req *types.ShareRequest) error {
+	if req.MediaURL == "" && len(req.MediaURLs) == 0 {
+		return types.NewValidationError("media_url or media_urls is required for Instagram posts")
+	}
+	if len(req.MediaURLs) > 0 && (len(req.MediaURLs) < instagramMinCarouselItems || len(req.MediaURLs) > instagramMaxCarouselItems) {
+		return types.NewValidationError("instagram carousel requires %d to %d media_urls, got %d", instagramMinCarouselItems, instagramMaxCarouselItems, len(req.MediaURLs))
+	}
+	if utf8.RuneCountInString(req.Content) > instagramMaxCaptionLength {
+		return types.NewValidationError("instagram caption exceeds %d characters", instagramMaxCaptionLength)
+	}
+	return nil
+}
+
 // Share shares content to Instagram
 func (i *InstagramPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
 	// Instagram Graph API requires Instagram Business Account connected to Facebook Page
-	// This is a simplified implementation for photo posts
-	// For production, you need proper media upload handling
+	// Images and carousels are published directly, videos are published as Reels
+
+	if err := i.Validate(req); err != nil {
+		return "", err
+	}
 
-	if req.MediaURL == "" {
-		return "", fmt.Errorf("media_url is required for Instagram posts")
+	// Content is published on the Instagram Business Account, not on "me"
+	igUserID, err := i.resolveUserID(ctx, client)
+	if err != nil {
+		return "", err
 	}
 
 	// Step 1: Create media container
-	mediaData := map[string]any{
-		"image_url": req.MediaURL,
-		"caption":   req.Content,
+	var mediaData map[string]any
+	isReel := false
+	if len(req.MediaURLs) > 0 {
+		carouselData, err := i.createCarouselData(ctx, client, igUserID, req)
+		if err != nil {
+			return "", err
+		}
+		mediaData = carouselData
+	} else if media.IsVideo(media.TypeByURL(req.MediaURL)) {
+		// Instagram fetches the media itself, so videos are recognized by the URL extension
+		isReel = true
+		mediaData = map[string]any{
+			"media_type": "REELS",
+			"video_url":  req.MediaURL,
+			"caption":    req.Content,
+		}
+	} else {
+		mediaData = map[string]any{
+			"image_url": req.MediaURL,
+			"caption":   req.Content,
+		}
+		// Alt text is only accepted on single image posts
+		if req.MediaAltText != "" {
+			mediaData["alt_text"] = req.MediaAltText
+		}
+	}
+
+	// Engagement settings are only sent when explicitly requested
+	if req.DisableComments {
+		mediaData["comment_enabled"] = false
+	}
+	if req.HideLikeCounts {
+		mediaData["like_and_view_counts_disabled"] = true
+	}
+
+	containerID, err := i.createMediaContainer(ctx, client, igUserID, mediaData)
+	if err != nil {
+		return "", err
+	}
+
+	// A Reels container can only be published once Instagram has processed the video
+	if isReel {
+		if err := i.waitForContainer(ctx, client, containerID); err != nil {
+			return "", err
+		}
+	}
+
+	// Step 2: Publish the media container
+	publishData := map[string]any{
+		"creation_id": containerID,
+	}
+
+	publishJSON, err := json.Marshal(publishData)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal instagram publish request: %w", err)
+	}
+
+	// Publish media
+	publishReq, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("https://graph.facebook.com/%s/media_publish", igUserID), strings.NewReader(string(publishJSON)))
+	if err != nil {
+		return "", fmt.Errorf("failed to create instagram publish request: %w", err)
+	}
+
+	publishReq.Header.Set("Content-Type", "application/json")
+
+	publishResp, err := client.Do(publishReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send instagram publish request: %w", err)
+	}
+	defer func() {
+		_ = publishResp.Body.Close()
+	}()
+
+	publishBody, err := io.ReadAll(publishResp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read instagram publish response: %w", err)
+	}
+
+	if publishResp.StatusCode < 200 || publishResp.StatusCode >= 300 {
+		// Parse error response
+		var errorResponse struct {
+			Error struct {
+				Message   string `json:"message"`
+				Type      string `json:"type"`
+				Code      int    `json:"code"`
+				SubCode   int    `json:"error_subcode,omitempty"`
+				FBTraceID string `json:"fbtrace_id"`
+			} `json:"error"`
+		}
+
+		if err := json.Unmarshal(publishBody, &errorResponse); err == nil {
+			return "", platformError(publishResp.StatusCode, publishBody, fmt.Errorf("instagram publish api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
+		}
+
+		return "", platformError(publishResp.StatusCode, publishBody, fmt.Errorf("instagram publish api error: status=%d body=%s", publishResp.StatusCode, string(publishBody)))
+	}
+
+	// Parse publish response
+	var publishResponse struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(publishBody, &publishResponse); err != nil {
+		return "", fmt.Errorf("failed to parse instagram publish response: %w", err)
+	}
+
+	return publishResponse.ID, nil
+}
+
+// createCarouselData creates a child container for each carousel image and
+// returns the parent CAROUSEL container data referencing them
+func (i *InstagramPlatform) createCarouselData(ctx context.Context, client *http.Client, igUserID string, req *types.ShareRequest) (map[string]any, error) {
+	if len(req.MediaURLs) < instagramMinCarouselItems || len(req.MediaURLs) > instagramMaxCarouselItems {
+		return nil, types.NewValidationError("instagram carousel requires %d to %d media_urls, got %d", instagramMinCarouselItems, instagramMaxCarouselItems, len(req.MediaURLs))
 	}
 
+	childIDs := make([]string, 0, len(req.MediaURLs))
+	for idx, mediaURL := range req.MediaURLs {
+		childID, err := i.createMediaContainer(ctx, client, igUserID, map[string]any{
+			"image_url":        mediaURL,
+			"is_carousel_item": true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create carousel item %d: %w", idx+1, err)
+		}
+		childIDs = append(childIDs, childID)
+	}
+
+	return map[string]any{
+		"media_type": "CAROUSEL",
+		"children":   strings.Join(childIDs, ","),
+		"caption":    req.Content,
+	}, nil
+}
+
+// createMediaContainer creates a media container on the Instagram user and returns its ID
+func (i *InstagramPlatform) createMediaContainer(ctx context.Context, client *http.Client, igUserID string, mediaData map[string]any) (string, error) {
 	jsonData, err := json.Marshal(mediaData)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal instagram media request: %w", err)
 	}
 
-	// Create media container
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://graph.facebook.com/me/media", strings.NewReader(string(jsonData)))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("https://graph.facebook.com/%s/media", igUserID), strings.NewReader(string(jsonData)))
 	if err != nil {
 		return "", fmt.Errorf("failed to create instagram media request: %w", err)
 	}
@@ -80,10 +277,10 @@ func (i *InstagramPlatform) Share(ctx context.Context, client *http.Client, req
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return "", fmt.Errorf("instagram media api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("instagram media api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return "", fmt.Errorf("instagram media api error: status=%d body=%s", resp.StatusCode, string(body))
+		return "", platformError(resp.StatusCode, body, fmt.Errorf("instagram media api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse media container response
@@ -99,66 +296,163 @@ func (i *InstagramPlatform) Share(ctx context.Context, client *http.Client, req
 		return "", fmt.Errorf("no media container ID in response")
 	}
 
-	// Step 2: Publish the media container
-	publishData := map[string]any{
-		"creation_id": mediaResponse.ID,
-	}
+	return mediaResponse.ID, nil
+}
 
-	publishJSON, err := json.Marshal(publishData)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal instagram publish request: %w", err)
+// waitForContainer polls a media container's status_code until Instagram has finished processing
+// it, a container that failed processing is reported as a *types.ProcessingError
+func (i *InstagramPlatform) waitForContainer(ctx context.Context, client *http.Client, containerID string) error {
+	for attempt := 0; attempt < instagramContainerMaxPolls; attempt++ {
+		status, err := i.containerStatus(ctx, client, containerID)
+		if err != nil {
+			return err
+		}
+
+		switch status.StatusCode {
+		case "FINISHED", "PUBLISHED":
+			return nil
+		case "ERROR", "EXPIRED":
+			message := "instagram couldn't process the video"
+			if status.Status != "" {
+				message += ": " + status.Status
+			}
+			return &types.ProcessingError{Reason: strings.ToLower(status.StatusCode), Message: message}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(instagramContainerPollInterval):
+		}
 	}
 
-	// Publish media
-	publishReq, err := http.NewRequestWithContext(ctx, "POST", "https://graph.facebook.com/me/media_publish", strings.NewReader(string(publishJSON)))
+	return fmt.Errorf("instagram media container %s was not processed in time", containerID)
+}
+
+// instagramContainerStatus is the processing state of a media container, Status carries
+// the reason when StatusCode is ERROR
+type instagramContainerStatus struct {
+	StatusCode string `json:"status_code"`
+	Status     string `json:"status"`
+}
+
+// containerStatus fetches the processing state of a media container
+func (i *InstagramPlatform) containerStatus(ctx context.Context, client *http.Client, containerID string) (instagramContainerStatus, error) {
+	var status instagramContainerStatus
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://graph.facebook.com/%s?fields=status_code,status", containerID), nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create instagram publish request: %w", err)
+		return status, fmt.Errorf("failed to create instagram container status request: %w", err)
 	}
 
-	publishReq.Header.Set("Content-Type", "application/json")
-
-	publishResp, err := client.Do(publishReq)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send instagram publish request: %w", err)
+		return status, fmt.Errorf("failed to get instagram container status: %w", err)
 	}
 	defer func() {
-		_ = publishResp.Body.Close()
+		_ = resp.Body.Close()
 	}()
 
-	publishBody, err := io.ReadAll(publishResp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read instagram publish response: %w", err)
+		return status, fmt.Errorf("failed to read instagram container status response: %w", err)
 	}
 
-	if publishResp.StatusCode < 200 || publishResp.StatusCode >= 300 {
-		// Parse error response
-		var errorResponse struct {
-			Error struct {
-				Message   string `json:"message"`
-				Type      string `json:"type"`
-				Code      int    `json:"code"`
-				SubCode   int    `json:"error_subcode,omitempty"`
-				FBTraceID string `json:"fbtrace_id"`
-			} `json:"error"`
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return status, platformError(resp.StatusCode, body, fmt.Errorf("instagram container status api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	if err := json.Unmarshal(body, &status); err != nil {
+		return status, fmt.Errorf("failed to parse instagram container status response: %w", err)
+	}
+
+	return status, nil
+}
+
+// resolveUserID returns the ID of the Instagram Business Account connected to the user's Facebook
+// Pages, which content is published on. It is cached per access token.
+func (i *InstagramPlatform) resolveUserID(ctx context.Context, client *http.Client) (string, error) {
+	var key string
+	if accessToken, ok := clientAccessToken(client); ok {
+		sum := sha256.Sum256([]byte(accessToken))
+		key = hex.EncodeToString(sum[:])
+
+		i.mu.Lock()
+		cached, found := i.userIDs[key]
+		i.mu.Unlock()
+		if found && time.Now().Before(cached.expiresAt) {
+			return cached.id, nil
 		}
+	}
 
-		if err := json.Unmarshal(publishBody, &errorResponse); err == nil {
-			return "", fmt.Errorf("instagram publish api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+	igUserID, err := i.fetchUserID(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	if key != "" {
+		now := time.Now()
+		i.mu.Lock()
+		for cachedKey, cached := range i.userIDs {
+			if now.After(cached.expiresAt) {
+				delete(i.userIDs, cachedKey)
+			}
 		}
+		i.userIDs[key] = instagramUserID{id: igUserID, expiresAt: now.Add(instagramUserIDTTL)}
+		i.mu.Unlock()
+	}
+
+	return igUserID, nil
+}
 
-		return "", fmt.Errorf("instagram publish api error: status=%d body=%s", publishResp.StatusCode, string(publishBody))
+// fetchUserID looks up the Instagram Business Account of the first of the user's Facebook Pages that has one
+func (i *InstagramPlatform) fetchUserID(ctx context.Context, client *http.Client) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://graph.facebook.com/me/accounts?fields=id,name,instagram_business_account", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create instagram account request: %w", err)
 	}
 
-	// Parse publish response
-	var publishResponse struct {
-		ID string `json:"id"`
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get instagram account: %w", err)
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
-	if err := json.Unmarshal(publishBody, &publishResponse); err != nil {
-		return "", fmt.Errorf("failed to parse instagram publish response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read instagram account response: %w", err)
 	}
 
-	return publishResponse.ID, nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", platformError(resp.StatusCode, body, fmt.Errorf("instagram account api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var accounts struct {
+		Data []struct {
+			ID                       string `json:"id"`
+			InstagramBusinessAccount *struct {
+				ID string `json:"id"`
+			} `json:"instagram_business_account"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &accounts); err != nil {
+		return "", fmt.Errorf("failed to parse instagram account response: %w", err)
+	}
+
+	for _, page := range accounts.Data {
+		if page.InstagramBusinessAccount != nil && page.InstagramBusinessAccount.ID != "" {
+			return page.InstagramBusinessAccount.ID, nil
+		}
+	}
+
+	return "", types.NewValidationError("no instagram business account is connected to the user's facebook pages")
+}
+
+// DeletePost is not supported, the Instagram Graph API can't delete published media
+func (i *InstagramPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	return fmt.Errorf("instagram api does not support deleting posts, delete it in the Instagram app: %w", types.ErrOperationNotSupported)
 }
 
 // GetStats retrieves statistics from Instagram
@@ -175,7 +469,7 @@ func (i *InstagramPlatform) GetStats(ctx context.Context, client *http.Client, m
 		return types.StatsData{}, fmt.Errorf("failed to create instagram stats request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.StatsData{}, fmt.Errorf("failed to get instagram stats: %w", err)
 	}
@@ -201,16 +495,16 @@ func (i *InstagramPlatform) GetStats(ctx context.Context, client *http.Client, m
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.StatsData{}, fmt.Errorf("instagram stats api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return types.StatsData{}, platformError(resp.StatusCode, body, fmt.Errorf("instagram stats api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return types.StatsData{}, fmt.Errorf("instagram stats api error: status=%d body=%s", resp.StatusCode, string(body))
+		return types.StatsData{}, platformError(resp.StatusCode, body, fmt.Errorf("instagram stats api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
 	var statsResponse struct {
-		LikeCount     int    `json:"like_count"`
-		CommentsCount int    `json:"comments_count"`
+		LikeCount     *int   `json:"like_count"` // omitted when the owner hides like counts
+		CommentsCount *int   `json:"comments_count"`
 		MediaType     string `json:"media_type"`
 	}
 
@@ -218,13 +512,75 @@ func (i *InstagramPlatform) GetStats(ctx context.Context, client *http.Client, m
 		return types.StatsData{}, fmt.Errorf("failed to parse instagram stats response: %w", err)
 	}
 
-	return types.StatsData{
-		Likes:    statsResponse.LikeCount,
-		Replies:  statsResponse.CommentsCount,
+	stats := types.StatsData{
 		Shares:   0, // Instagram doesn't provide share count in basic stats
 		Retweets: 0, // Instagram doesn't have retweets
 		Views:    0, // Instagram doesn't provide view count in basic stats
-	}, nil
+	}
+	var missing []string
+	if statsResponse.LikeCount != nil {
+		stats.Likes = *statsResponse.LikeCount
+	} else {
+		missing = append(missing, metricLikes)
+	}
+	if statsResponse.CommentsCount != nil {
+		stats.Replies = *statsResponse.CommentsCount
+	} else {
+		missing = append(missing, metricReplies)
+	}
+
+	// Insights are extra metrics, the core stats are still returned without them
+	if extra, err := i.mediaInsights(ctx, client, mediaID); err == nil && len(extra) > 0 {
+		stats.Extra = extra
+	}
+
+	return fetchedStats(stats, missing...), nil
+}
+
+// mediaInsights returns the reach and saves of a media from its lifetime insights
+func (i *InstagramPlatform) mediaInsights(ctx context.Context, client *http.Client, mediaID string) (map[string]int, error) {
+	insightsURL := fmt.Sprintf("https://graph.facebook.com/%s/insights?metric=%s,%s", mediaID, extraReach, extraSaved)
+	req, err := http.NewRequestWithContext(ctx, "GET", insightsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instagram insights request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instagram insights: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instagram insights response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, platformError(resp.StatusCode, body, fmt.Errorf("instagram insights api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var insights struct {
+		Data []struct {
+			Name   string `json:"name"`
+			Values []struct {
+				Value int `json:"value"`
+			} `json:"values"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &insights); err != nil {
+		return nil, fmt.Errorf("failed to parse instagram insights response: %w", err)
+	}
+
+	extra := make(map[string]int)
+	for _, metric := range insights.Data {
+		if len(metric.Values) > 0 {
+			extra[metric.Name] = metric.Values[0].Value
+		}
+	}
+	return extra, nil
 }
 
 // GetUserInfo retrieves user information from Instagram platform
@@ -236,7 +592,7 @@ func (i *InstagramPlatform) GetUserInfo(ctx context.Context, client *http.Client
 		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
 	}
@@ -262,10 +618,10 @@ func (i *InstagramPlatform) GetUserInfo(ctx context.Context, client *http.Client
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.UserInfo{}, fmt.Errorf("instagram user info api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("instagram user info api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return types.UserInfo{}, fmt.Errorf("instagram user info api error: status=%d body=%s", resp.StatusCode, string(body))
+		return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("instagram user info api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
@@ -310,7 +666,7 @@ func (i *InstagramPlatform) GetRecentPosts(ctx context.Context, client *http.Cli
 	}
 
 	// Build query parameters
-	params := fmt.Sprintf("limit=%d&fields=id,caption,media_type,media_url,permalink,thumbnail_url,timestamp,like_count,comments_count", limit)
+	params := fmt.Sprintf("limit=%d&fields=%s", limit, instagramMediaFields)
 
 	// Add time range filters if provided
 	if startTime > 0 {
@@ -328,7 +684,7 @@ func (i *InstagramPlatform) GetRecentPosts(ctx context.Context, client *http.Cli
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -342,22 +698,12 @@ func (i *InstagramPlatform) GetRecentPosts(ctx context.Context, client *http.Cli
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("instagram api error: status=%d body=%s", resp.StatusCode, string(body))
+		return nil, platformError(resp.StatusCode, body, fmt.Errorf("instagram api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
 	var mediaResponse struct {
-		Data []struct {
-			ID            string `json:"id"`
-			Caption       string `json:"caption"`
-			MediaType     string `json:"media_type"`
-			MediaURL      string `json:"media_url"`
-			Permalink     string `json:"permalink"`
-			ThumbnailURL  string `json:"thumbnail_url"`
-			Timestamp     string `json:"timestamp"`
-			LikeCount     int    `json:"like_count"`
-			CommentsCount int    `json:"comments_count"`
-		} `json:"data"`
+		Data []instagramMedia `json:"data"`
 	}
 
 	if err := json.Unmarshal(body, &mediaResponse); err != nil {
@@ -367,43 +713,99 @@ func (i *InstagramPlatform) GetRecentPosts(ctx context.Context, client *http.Cli
 	// Convert to Post structs
 	var posts []types.Post
 	for _, media := range mediaResponse.Data {
-		// Parse timestamp
-		timestamp, err := time.Parse(time.RFC3339, media.Timestamp)
-		if err != nil {
-			timestamp = time.Now()
-		}
+		posts = append(posts, instagramPost(media))
+	}
 
-		// Determine media type
-		mediaType := media.MediaType
-		if mediaType == "" {
-			mediaType = "image" // Default to image
-		}
+	return posts, nil
+}
 
-		// Use thumbnail URL if available, otherwise use media URL
-		mediaURL := media.MediaURL
-		if media.ThumbnailURL != "" {
-			mediaURL = media.ThumbnailURL
-		}
+// GetPost retrieves a single Instagram media object by ID
+func (i *InstagramPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	if mediaID == "" {
+		return types.Post{}, fmt.Errorf("media_id required")
+	}
 
-		post := types.Post{
-			ID:        media.ID,
-			Content:   media.Caption,
-			CreatedAt: timestamp.Unix(),
-			Stats: types.StatsData{
-				Likes:    media.LikeCount,
-				Replies:  media.CommentsCount,
-				Shares:   0, // Instagram doesn't provide share count in basic API
-				Retweets: 0, // Instagram doesn't have retweets
-			},
-			URL:       media.Permalink,
-			MediaType: mediaType,
-			MediaURL:  mediaURL,
-		}
+	url := fmt.Sprintf("https://graph.facebook.com/%s?fields=%s", mediaID, instagramMediaFields)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to create instagram post request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to get instagram post: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
-		posts = append(posts, post)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to read instagram post response: %w", err)
 	}
 
-	return posts, nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.Post{}, platformError(resp.StatusCode, body, fmt.Errorf("instagram api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var media instagramMedia
+	if err := json.Unmarshal(body, &media); err != nil {
+		return types.Post{}, fmt.Errorf("failed to parse instagram post response: %w", err)
+	}
+
+	return instagramPost(media), nil
+}
+
+// instagramMediaFields are the Graph API fields requested for media objects
+const instagramMediaFields = "id,caption,media_type,media_url,permalink,thumbnail_url,timestamp,like_count,comments_count"
+
+// instagramMedia is a media object as returned by the Graph API
+type instagramMedia struct {
+	ID            string `json:"id"`
+	Caption       string `json:"caption"`
+	MediaType     string `json:"media_type"`
+	MediaURL      string `json:"media_url"`
+	Permalink     string `json:"permalink"`
+	ThumbnailURL  string `json:"thumbnail_url"`
+	Timestamp     string `json:"timestamp"`
+	LikeCount     int    `json:"like_count"`
+	CommentsCount int    `json:"comments_count"`
+}
+
+// instagramPost converts a media object to a post
+func instagramPost(media instagramMedia) types.Post {
+	// Parse timestamp
+	timestamp, err := time.Parse(time.RFC3339, media.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	// Determine media type
+	mediaType := media.MediaType
+	if mediaType == "" {
+		mediaType = "image" // Default to image
+	}
+
+	// Use thumbnail URL if available, otherwise use media URL
+	mediaURL := media.MediaURL
+	if media.ThumbnailURL != "" {
+		mediaURL = media.ThumbnailURL
+	}
+
+	return types.Post{
+		ID:        media.ID,
+		Content:   media.Caption,
+		CreatedAt: timestamp.Unix(),
+		Stats: fetchedStats(types.StatsData{
+			Likes:    media.LikeCount,
+			Replies:  media.CommentsCount,
+			Shares:   0, // Instagram doesn't provide share count in basic API
+			Retweets: 0, // Instagram doesn't have retweets
+		}),
+		URL:       media.Permalink,
+		MediaType: mediaType,
+		MediaURL:  mediaURL,
+	}
 }
 
 // HandleOAuthCallback handles OAuth callback for Instagram platform
@@ -0,0 +1,130 @@
+package platforms
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"social/pkg/httpx"
+)
+
+const (
+	linkCardTimeout      = 5 * time.Second
+	linkCardMaxBodyBytes = 512 * 1024
+	linkCardMaxRedirects = 3
+)
+
+// ErrAddressNotPublic is returned when an outbound URL resolves to an address that isn't public
+var ErrAddressNotPublic = errors.New("address is not public")
+
+// linkCardClient fetches links from the shared content. The links come from the caller, so the
+// client only connects to public addresses, checked on the resolved IP of every connection
+// including redirects, and doesn't go through a proxy that would resolve the host itself.
+// It is built on first use so it picks up the configured minimum TLS version.
+var linkCardClient = sync.OnceValue(func() *http.Client {
+	return &http.Client{
+		Transport: publicTransport(linkCardTimeout),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= linkCardMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", linkCardMaxRedirects)
+			}
+			return nil
+		},
+	}
+})
+
+// publicTransport creates a transport for fetching caller-supplied URLs: it only connects to public
+// addresses, checked with PublicDialControl, skips proxies, which would resolve the host themselves,
+// and uses the configured minimum TLS version
+func publicTransport(connectTimeout time.Duration) *http.Transport {
+	dialer := &net.Dialer{Timeout: connectTimeout, Control: PublicDialControl}
+	return &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSClientConfig:     &tls.Config{MinVersion: httpx.MinTLSVersion()},
+		TLSHandshakeTimeout: connectTimeout,
+		ForceAttemptHTTP2:   true,
+	}
+}
+
+// PublicDialControl is a net.Dialer Control that rejects connections to loopback, private, link-local
+// and other non-public addresses, for clients that fetch caller-supplied URLs
+func PublicDialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return ErrAddressNotPublic
+	}
+	return nil
+}
+
+// isPublicAddr reports whether addr is a globally routable unicast address
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
+
+var (
+	linkPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+	// cardTagPattern matches the meta tags X uses to build a link preview
+	cardTagPattern = regexp.MustCompile(`(?i)<meta[^>]+(?:property|name)\s*=\s*["'](?:og:title|og:image|twitter:card)["']`)
+)
+
+// PrimaryLink returns the first http(s) URL in content, or an empty string if there is none
+func PrimaryLink(content string) string {
+	link := linkPattern.FindString(content)
+	// Trailing punctuation usually belongs to the sentence rather than the URL
+	return strings.TrimRight(link, ".,;:!?)]}'")
+}
+
+// CheckLinkCard fetches a link and checks that it is reachable and has Open Graph or
+// Twitter card tags, returning warnings for anything that would degrade the preview
+func CheckLinkCard(ctx context.Context, link string) []string {
+	ctx, cancel := context.WithTimeout(ctx, linkCardTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return []string{fmt.Sprintf("link %s is malformed: %v", link, err)}
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := linkCardClient().Do(req)
+	if err != nil {
+		return []string{fmt.Sprintf("link %s is unreachable: %v", link, err)}
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return []string{fmt.Sprintf("link %s returned status %d, the tweet will have no link preview", link, resp.StatusCode)}
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, linkCardMaxBodyBytes))
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read link %s: %v", link, err)}
+	}
+
+	if !cardTagPattern.Match(body) {
+		return []string{fmt.Sprintf("link %s has no Open Graph or Twitter card tags, the tweet will have no link preview", link)}
+	}
+
+	return nil
+}
@@ -0,0 +1,448 @@
+package platforms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"social/internal/types"
+)
+
+// mastodonDefaultInstance is the instance API calls are addressed to. Requests are routed to the
+// server's configured instance by the authenticated client, so it must match config.DefaultMastodonInstance.
+const mastodonDefaultInstance = "https://mastodon.social"
+
+// mastodonMaxPageSize is the largest limit the account statuses endpoint accepts
+const mastodonMaxPageSize = 40
+
+// mastodonMaxStatusLength is the default status limit of Mastodon instances, some allow more
+const mastodonMaxStatusLength = 500
+
+var (
+	// mastodonLineBreakPattern matches the tags Mastodon uses for line and paragraph breaks
+	mastodonLineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>\s*<p>`)
+	mastodonTagPattern       = regexp.MustCompile(`<[^>]+>`)
+)
+
+// MastodonPlatform implements the Mastodon platform
+type MastodonPlatform struct{}
+
+// NewMastodonPlatform creates a new Mastodon platform instance
+func NewMastodonPlatform() *MastodonPlatform {
+	return &MastodonPlatform{}
+}
+
+// GetName returns the platform name
+func (m *MastodonPlatform) GetName() string {
+	return "mastodon"
+}
+
+// Capabilities reports what Mastodon supports, statuses are text only
+func (m *MastodonPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+	}
+}
+
+// apiError builds an error from a non-2xx Mastodon response
+func (m *MastodonPlatform) apiError(operation string, statusCode int, body []byte) error {
+	var errorResponse struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error != "" {
+		return platformError(statusCode, body, fmt.Errorf("mastodon %sapi error (%d): %s", operation, statusCode, errorResponse.Error))
+	}
+	return platformError(statusCode, body, fmt.Errorf("mastodon %sapi error: status=%d body=%s", operation, statusCode, string(body)))
+}
+
+// instanceURL returns the base URL API calls of a share request are sent to
+func (m *MastodonPlatform) instanceURL(req *types.ShareRequest) string {
+	if req.InstanceURL != "" {
+		return strings.TrimSuffix(req.InstanceURL, "/")
+	}
+	return mastodonDefaultInstance
+}
+
+// Validate checks that a share request can be posted to Mastodon
+func (m *MastodonPlatform) Validate(req *types.ShareRequest) error {
+	if strings.TrimSpace(req.Content) == "" {
+		return types.NewValidationError("content required for mastodon status")
+	}
+	if req.MediaURL != "" {
+		return types.NewValidationError("media_url is not supported for mastodon")
+	}
+	if utf8.RuneCountInString(req.Content) > mastodonMaxStatusLength {
+		return types.NewValidationError("mastodon status exceeds %d characters", mastodonMaxStatusLength)
+	}
+	return nil
+}
+
+// Share posts a status to the user's Mastodon instance
+func (m *MastodonPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := m.Validate(req); err != nil {
+		return "", err
+	}
+
+	statusData := map[string]any{
+		"status":     req.Content,
+		"visibility": m.getVisibility(req),
+	}
+
+	jsonData, err := json.Marshal(statusData)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mastodon status request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", m.instanceURL(req)+"/api/v1/statuses", strings.NewReader(string(jsonData)))
+	if err != nil {
+		return "", fmt.Errorf("failed to create mastodon status request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send mastodon status request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read mastodon status response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", m.apiError("", resp.StatusCode, body)
+	}
+
+	var statusResponse struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &statusResponse); err != nil {
+		return "", fmt.Errorf("failed to parse mastodon status response: %w", err)
+	}
+
+	return statusResponse.ID, nil
+}
+
+// getVisibility maps the request privacy to a Mastodon status visibility
+func (m *MastodonPlatform) getVisibility(req *types.ShareRequest) string {
+	switch req.Privacy {
+	case "unlisted":
+		return "unlisted"
+	case "private", "friends", "followers":
+		return "private" // followers only
+	default:
+		return "public"
+	}
+}
+
+// mastodonStatus is a status as returned by the Mastodon API
+type mastodonStatus struct {
+	ID               string `json:"id"`
+	CreatedAt        string `json:"created_at"`
+	Content          string `json:"content"`
+	URL              string `json:"url"`
+	RepliesCount     int    `json:"replies_count"`
+	ReblogsCount     int    `json:"reblogs_count"`
+	FavouritesCount  int    `json:"favourites_count"`
+	MediaAttachments []struct {
+		Type       string `json:"type"`
+		URL        string `json:"url"`
+		PreviewURL string `json:"preview_url"`
+	} `json:"media_attachments"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// GetStats retrieves favourites, boosts and replies of a status
+func (m *MastodonPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	status, err := m.getStatus(ctx, client, mediaID, "stats")
+	if err != nil {
+		return types.StatsData{}, err
+	}
+
+	return mastodonStats(status), nil
+}
+
+// GetPost retrieves a single status by ID
+func (m *MastodonPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	status, err := m.getStatus(ctx, client, mediaID, "post")
+	if err != nil {
+		return types.Post{}, err
+	}
+
+	return mastodonPost(status), nil
+}
+
+// getStatus fetches a status, operation names the request in errors
+func (m *MastodonPlatform) getStatus(ctx context.Context, client *http.Client, mediaID, operation string) (mastodonStatus, error) {
+	if mediaID == "" {
+		return mastodonStatus{}, fmt.Errorf("media_id required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v1/statuses/%s", mastodonDefaultInstance, url.PathEscape(mediaID)), nil)
+	if err != nil {
+		return mastodonStatus{}, fmt.Errorf("failed to create mastodon %s request: %w", operation, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return mastodonStatus{}, fmt.Errorf("failed to get mastodon %s: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return mastodonStatus{}, fmt.Errorf("failed to read mastodon %s response: %w", operation, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return mastodonStatus{}, m.apiError(operation+" ", resp.StatusCode, body)
+	}
+
+	var status mastodonStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return mastodonStatus{}, fmt.Errorf("failed to parse mastodon %s response: %w", operation, err)
+	}
+
+	return status, nil
+}
+
+// DeletePost deletes a status
+func (m *MastodonPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/v1/statuses/%s", mastodonDefaultInstance, url.PathEscape(mediaID)), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create mastodon delete request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send mastodon delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read mastodon delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return m.apiError("delete ", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetUserInfo retrieves the authenticated account from Mastodon
+func (m *MastodonPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types.UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", mastodonDefaultInstance+"/api/v1/accounts/verify_credentials", nil)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to read user info response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.UserInfo{}, m.apiError("user info ", resp.StatusCode, body)
+	}
+
+	var account struct {
+		ID             string `json:"id"`
+		Username       string `json:"username"`
+		DisplayName    string `json:"display_name"`
+		Avatar         string `json:"avatar"`
+		URL            string `json:"url"`
+		FollowersCount int    `json:"followers_count"`
+		FollowingCount int    `json:"following_count"`
+	}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to parse user info response: %w", err)
+	}
+
+	displayName := account.DisplayName
+	if displayName == "" {
+		displayName = account.Username
+	}
+
+	return types.UserInfo{
+		ID:          account.ID,
+		Username:    account.Username,
+		DisplayName: displayName,
+		AvatarURL:   account.Avatar,
+		ProfileURL:  account.URL,
+		Verified:    false, // Mastodon has no platform verification, only self-verified profile links
+		Followers:   account.FollowersCount,
+		Following:   account.FollowingCount,
+	}, nil
+}
+
+// GetRecentPosts retrieves the account's recent statuses
+func (m *MastodonPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > mastodonMaxPageSize {
+		limit = mastodonMaxPageSize
+	}
+
+	// Statuses are listed per account, so look up the account ID first
+	userInfo, err := m.GetUserInfo(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	// Boosts aren't the user's own posts
+	statusesURL := fmt.Sprintf("%s/api/v1/accounts/%s/statuses?limit=%d&exclude_reblogs=true", mastodonDefaultInstance, url.PathEscape(userInfo.ID), limit)
+	req, err := http.NewRequestWithContext(ctx, "GET", statusesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, m.apiError("", resp.StatusCode, body)
+	}
+
+	var statuses []mastodonStatus
+	if err := json.Unmarshal(body, &statuses); err != nil {
+		return nil, fmt.Errorf("failed to parse mastodon statuses response: %w", err)
+	}
+
+	var posts []types.Post
+	for _, status := range statuses {
+		createdTime, err := time.Parse(time.RFC3339, status.CreatedAt)
+		if err != nil {
+			createdTime = time.Now()
+		}
+
+		// The statuses endpoint pages by ID rather than time, so apply the range here
+		if startTime > 0 && createdTime.Unix() < startTime {
+			continue
+		}
+		if endTime > 0 && createdTime.Unix() > endTime {
+			continue
+		}
+
+		posts = append(posts, mastodonPost(status))
+	}
+
+	return posts, nil
+}
+
+// mastodonStats converts a status's counters to stats
+func mastodonStats(status mastodonStatus) types.StatsData {
+	return fetchedStats(types.StatsData{
+		Likes:    status.FavouritesCount,
+		Retweets: status.ReblogsCount, // Boosts are Mastodon's equivalent of retweets
+		Replies:  status.RepliesCount,
+		Views:    0, // Mastodon doesn't expose view counts
+	})
+}
+
+// mastodonPost converts a status to a post
+func mastodonPost(status mastodonStatus) types.Post {
+	createdTime, err := time.Parse(time.RFC3339, status.CreatedAt)
+	if err != nil {
+		createdTime = time.Now()
+	}
+
+	var mediaType, mediaURL string
+	if len(status.MediaAttachments) > 0 {
+		media := status.MediaAttachments[0]
+		mediaType = mastodonMediaType(media.Type)
+		mediaURL = media.URL
+		if mediaType == "video" || mediaType == "gif" {
+			mediaURL = media.PreviewURL
+		}
+	}
+
+	tags := make([]string, 0, len(status.Tags))
+	for _, tag := range status.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return types.Post{
+		ID:        status.ID,
+		Content:   mastodonPlainText(status.Content),
+		CreatedAt: createdTime.Unix(),
+		Stats:     mastodonStats(status),
+		URL:       status.URL,
+		MediaURL:  mediaURL,
+		MediaType: mediaType,
+		Tags:      tags,
+	}
+}
+
+// mastodonMediaType maps a Mastodon attachment type to a Post media type
+func mastodonMediaType(mediaType string) string {
+	switch mediaType {
+	case "video":
+		return "video"
+	case "gifv":
+		return "gif"
+	case "audio":
+		return "audio"
+	default:
+		return "image"
+	}
+}
+
+// mastodonPlainText converts the HTML content of a status to plain text
+func mastodonPlainText(content string) string {
+	text := mastodonLineBreakPattern.ReplaceAllString(content, "\n")
+	text = mastodonTagPattern.ReplaceAllString(text, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// HandleOAuthCallback handles OAuth callback for Mastodon platform
+func (m *MastodonPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
+	// Mastodon平台特定的OAuth回调处理逻辑
+	return nil
+}
@@ -0,0 +1,57 @@
+package platforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"social/internal/types"
+	"social/pkg/media"
+	"social/pkg/tracing"
+)
+
+// Upload size limits of platforms that download the media and upload it themselves. Media is
+// buffered in memory, so these stay below what YouTube and TikTok accept.
+const (
+	youtubeMaxMediaSize = 1024 * 1024 * 1024
+	tiktokMaxVideoSize  = 1024 * 1024 * 1024
+)
+
+// mediaDialTimeout bounds connecting to the media host, the download itself is bound by the share's context
+const mediaDialTimeout = 10 * time.Second
+
+// mediaClient downloads the media to share. The media URL can point to any host, so it is fetched
+// without the platform's OAuth client: that would send the user's token to the media host and count
+// the download against the platform's circuit breaker and quota. It only connects to public
+// addresses, so a media URL can't reach internal services or the cloud metadata endpoint.
+// It is built on first use so it picks up the configured minimum TLS version and tracing.
+var mediaClient = sync.OnceValue(func() *http.Client {
+	return &http.Client{Transport: tracing.Transport(publicTransport(mediaDialTimeout))}
+})
+
+// downloadMedia downloads the media at mediaURL and returns it with its MIME type, detected from
+// the content rather than trusted from the URL or the server. Media larger than maxSize, or the
+// limit configured on the context, is rejected with ErrMediaTooLarge.
+func downloadMedia(ctx context.Context, mediaURL string, maxSize int64) ([]byte, string, error) {
+	if limit, ok := types.MediaSizeLimit(ctx); ok {
+		maxSize = limit
+	}
+
+	mediaData, mediaType, err := media.Download(ctx, mediaClient(), mediaURL, maxSize)
+	var tooLarge *media.TooLargeError
+	if errors.As(err, &tooLarge) {
+		return nil, "", mediaTooLargeError(tooLarge.Size, tooLarge.Limit)
+	}
+	return mediaData, mediaType, err
+}
+
+// mediaTooLargeError reports media over a size limit, size is -1 when the full size isn't known
+func mediaTooLargeError(size, maxSize int64) error {
+	if size < 0 {
+		return fmt.Errorf("media is larger than the %dMB limit: %w", maxSize/(1024*1024), types.ErrMediaTooLarge)
+	}
+	return fmt.Errorf("media is %dMB, larger than the %dMB limit: %w", size/(1024*1024), maxSize/(1024*1024), types.ErrMediaTooLarge)
+}
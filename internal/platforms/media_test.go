@@ -0,0 +1,48 @@
+package platforms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestDownloadMediaRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("media was downloaded from a loopback address")
+		_, _ = w.Write([]byte("GIF89a"))
+	}))
+	defer server.Close()
+
+	_, _, err := downloadMedia(context.Background(), server.URL+"/image.gif", 1024)
+	if !errors.Is(err, ErrAddressNotPublic) {
+		t.Fatalf("downloadMedia(%s) error = %v, want ErrAddressNotPublic", server.URL, err)
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "127.0.0.1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "192.168.0.10", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "::1", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:4700::1111", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
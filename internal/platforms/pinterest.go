@@ -0,0 +1,415 @@
+package platforms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"social/internal/types"
+)
+
+// Pinterest pin limits
+const (
+	pinterestMaxTitleLength       = 100
+	pinterestMaxDescriptionLength = 800
+	pinterestMaxPageSize          = 100
+)
+
+// pinterestTimeLayout is the layout of pin timestamps, which are UTC without a zone suffix
+const pinterestTimeLayout = "2006-01-02T15:04:05"
+
+// PinterestPlatform implements the Pinterest platform
+type PinterestPlatform struct{}
+
+// NewPinterestPlatform creates a new Pinterest platform instance
+func NewPinterestPlatform() *PinterestPlatform {
+	return &PinterestPlatform{}
+}
+
+// GetName returns the platform name
+func (p *PinterestPlatform) GetName() string {
+	return "pinterest"
+}
+
+// Capabilities reports what Pinterest supports, every pin needs an image
+func (p *PinterestPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		RequiresMedia:       true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+	}
+}
+
+// pinterestError is the error body returned by the Pinterest API v5
+type pinterestError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiError builds an error from a non-2xx Pinterest response
+func (p *PinterestPlatform) apiError(operation string, statusCode int, body []byte) error {
+	var errorResponse pinterestError
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Message != "" {
+		return platformError(statusCode, body, fmt.Errorf("pinterest %sapi error (%d): %s", operation, errorResponse.Code, errorResponse.Message))
+	}
+	return platformError(statusCode, body, fmt.Errorf("pinterest %sapi error: status=%d body=%s", operation, statusCode, string(body)))
+}
+
+// Validate checks that a share request can be posted to Pinterest
+func (p *PinterestPlatform) Validate(req *types.ShareRequest) error {
+	if req.BoardID == "" {
+		return types.NewValidationError("board_id is required for pinterest pins")
+	}
+	if req.MediaURL == "" {
+		return types.NewValidationError("media_url is required for pinterest pins")
+	}
+	if utf8.RuneCountInString(req.Title) > pinterestMaxTitleLength {
+		return types.NewValidationError("pinterest pin title exceeds %d characters", pinterestMaxTitleLength)
+	}
+	if utf8.RuneCountInString(req.Content) > pinterestMaxDescriptionLength {
+		return types.NewValidationError("pinterest pin description exceeds %d characters", pinterestMaxDescriptionLength)
+	}
+	return nil
+}
+
+// Share creates a pin on the given board with the media URL as its image
+func (p *PinterestPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := p.Validate(req); err != nil {
+		return "", err
+	}
+
+	pinData := map[string]any{
+		"board_id": req.BoardID,
+		"media_source": map[string]any{
+			"source_type": "image_url",
+			"url":         req.MediaURL,
+		},
+	}
+	if req.Title != "" {
+		pinData["title"] = req.Title
+	}
+	if req.Content != "" {
+		pinData["description"] = req.Content
+	}
+
+	jsonData, err := json.Marshal(pinData)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal pinterest pin request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.pinterest.com/v5/pins", strings.NewReader(string(jsonData)))
+	if err != nil {
+		return "", fmt.Errorf("failed to create pinterest pin request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send pinterest pin request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read pinterest pin response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", p.apiError("", resp.StatusCode, body)
+	}
+
+	var pinResponse struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &pinResponse); err != nil {
+		return "", fmt.Errorf("failed to parse pinterest pin response: %w", err)
+	}
+
+	return pinResponse.ID, nil
+}
+
+// GetStats retrieves lifetime metrics of a pin
+func (p *PinterestPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	pin, err := p.getPin(ctx, client, mediaID, "stats")
+	if err != nil {
+		return types.StatsData{}, err
+	}
+
+	// Pins without metrics (not a business account, or too old) would otherwise read as all zeros
+	if pin.PinMetrics == nil {
+		return types.StatsData{}, fmt.Errorf("pinterest returned no metrics for pin %s", mediaID)
+	}
+
+	return pinterestStats(pin), nil
+}
+
+// GetPost retrieves a single pin by ID, with stats when Pinterest reports metrics for it
+func (p *PinterestPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	pin, err := p.getPin(ctx, client, mediaID, "post")
+	if err != nil {
+		return types.Post{}, err
+	}
+
+	return pinterestPost(pin), nil
+}
+
+// getPin fetches a pin with its metrics, operation names the request in errors
+func (p *PinterestPlatform) getPin(ctx context.Context, client *http.Client, mediaID, operation string) (pinterestPin, error) {
+	if mediaID == "" {
+		return pinterestPin{}, fmt.Errorf("media_id required")
+	}
+
+	url := fmt.Sprintf("https://api.pinterest.com/v5/pins/%s?pin_metrics=true", mediaID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return pinterestPin{}, fmt.Errorf("failed to create pinterest %s request: %w", operation, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return pinterestPin{}, fmt.Errorf("failed to get pinterest %s: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return pinterestPin{}, fmt.Errorf("failed to read pinterest %s response: %w", operation, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return pinterestPin{}, p.apiError(operation+" ", resp.StatusCode, body)
+	}
+
+	var pin pinterestPin
+	if err := json.Unmarshal(body, &pin); err != nil {
+		return pinterestPin{}, fmt.Errorf("failed to parse pinterest %s response: %w", operation, err)
+	}
+
+	return pin, nil
+}
+
+// DeletePost deletes a pin
+func (p *PinterestPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("https://api.pinterest.com/v5/pins/%s", mediaID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create pinterest delete request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send pinterest delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read pinterest delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return p.apiError("delete ", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetUserInfo retrieves user information from Pinterest platform
+func (p *PinterestPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types.UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.pinterest.com/v5/user_account", nil)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to read user info response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.UserInfo{}, p.apiError("user info ", resp.StatusCode, body)
+	}
+
+	var userResponse struct {
+		ID             string `json:"id"`
+		Username       string `json:"username"`
+		BusinessName   string `json:"business_name,omitempty"`
+		ProfileImage   string `json:"profile_image"`
+		FollowerCount  int    `json:"follower_count"`
+		FollowingCount int    `json:"following_count"`
+	}
+	if err := json.Unmarshal(body, &userResponse); err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to parse user info response: %w", err)
+	}
+
+	// Business accounts have a display name, personal accounts only a username
+	displayName := userResponse.BusinessName
+	if displayName == "" {
+		displayName = userResponse.Username
+	}
+
+	return types.UserInfo{
+		ID:          userResponse.ID,
+		Username:    userResponse.Username,
+		DisplayName: displayName,
+		AvatarURL:   userResponse.ProfileImage,
+		ProfileURL:  fmt.Sprintf("https://www.pinterest.com/%s/", userResponse.Username),
+		Verified:    false, // Pinterest doesn't expose verification status
+		Followers:   userResponse.FollowerCount,
+		Following:   userResponse.FollowingCount,
+	}, nil
+}
+
+// GetRecentPosts retrieves the user's recent pins
+func (p *PinterestPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > pinterestMaxPageSize {
+		limit = pinterestMaxPageSize
+	}
+
+	url := fmt.Sprintf("https://api.pinterest.com/v5/pins?page_size=%d", limit)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, p.apiError("", resp.StatusCode, body)
+	}
+
+	var pinsResponse struct {
+		Items []pinterestPin `json:"items"`
+	}
+	if err := json.Unmarshal(body, &pinsResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse pinterest pins response: %w", err)
+	}
+
+	var posts []types.Post
+	for _, pin := range pinsResponse.Items {
+		createdTime, err := time.ParseInLocation(pinterestTimeLayout, pin.CreatedAt, time.UTC)
+		if err != nil {
+			createdTime = time.Now()
+		}
+
+		// The pins endpoint has no time filters, so apply the range here
+		if startTime > 0 && createdTime.Unix() < startTime {
+			continue
+		}
+		if endTime > 0 && createdTime.Unix() > endTime {
+			continue
+		}
+
+		posts = append(posts, pinterestPost(pin))
+	}
+
+	return posts, nil
+}
+
+// pinterestPin is a pin as returned by the Pinterest API
+type pinterestPin struct {
+	ID          string `json:"id"`
+	CreatedAt   string `json:"created_at"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Media       struct {
+		MediaType string `json:"media_type"`
+		Images    map[string]struct {
+			URL string `json:"url"`
+		} `json:"images"`
+	} `json:"media"`
+	PinMetrics *struct {
+		LifetimeMetrics struct {
+			Impression int `json:"impression"`
+			Save       int `json:"save"`
+			Reaction   int `json:"reaction"`
+			Comment    int `json:"comment"`
+		} `json:"lifetime_metrics"`
+	} `json:"pin_metrics"` // only present when requested with pin_metrics=true
+}
+
+// pinterestStats converts a pin's lifetime metrics to stats, the pin must have metrics
+func pinterestStats(pin pinterestPin) types.StatsData {
+	metrics := pin.PinMetrics.LifetimeMetrics
+	return fetchedStats(types.StatsData{
+		Likes:    metrics.Reaction,
+		Replies:  metrics.Comment,
+		Views:    metrics.Impression,
+		Shares:   metrics.Save, // Saves are Pinterest's equivalent of shares
+		Retweets: 0,            // Pinterest doesn't have retweets
+	})
+}
+
+// pinterestPost converts a pin to a post, stats are left unset for pins without metrics
+func pinterestPost(pin pinterestPin) types.Post {
+	createdTime, err := time.ParseInLocation(pinterestTimeLayout, pin.CreatedAt, time.UTC)
+	if err != nil {
+		createdTime = time.Now()
+	}
+
+	mediaType := "image"
+	if strings.HasPrefix(pin.Media.MediaType, "video") {
+		mediaType = "video"
+	}
+
+	post := types.Post{
+		ID:          pin.ID,
+		Content:     pin.Description,
+		MediaURL:    pin.Media.Images["originals"].URL,
+		CreatedAt:   createdTime.Unix(),
+		URL:         fmt.Sprintf("https://www.pinterest.com/pin/%s/", pin.ID),
+		MediaType:   mediaType,
+		Title:       pin.Title,
+		Description: pin.Description,
+	}
+	if pin.PinMetrics != nil {
+		post.Stats = pinterestStats(pin)
+	}
+	return post
+}
+
+// HandleOAuthCallback handles OAuth callback for Pinterest platform
+func (p *PinterestPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
+	// Pinterest平台特定的OAuth回调处理逻辑
+	return nil
+}
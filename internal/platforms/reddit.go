@@ -0,0 +1,449 @@
+package platforms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"social/internal/types"
+	"social/pkg/httpx"
+)
+
+// redditAPIBaseURL is the host OAuth-authenticated Reddit API calls are sent to
+const redditAPIBaseURL = "https://oauth.reddit.com"
+
+// Reddit submission limits
+const (
+	redditMaxTitleLength    = 300
+	redditMaxSelfTextLength = 40000
+	redditMaxPageSize       = 100
+)
+
+// redditLinkPrefix is the type prefix of a submission's fullname
+const redditLinkPrefix = "t3_"
+
+// RedditPlatform implements the Reddit platform. Reddit rejects requests without a descriptive
+// User-Agent, which the authenticated client sets on every request.
+type RedditPlatform struct{}
+
+// NewRedditPlatform creates a new Reddit platform instance
+func NewRedditPlatform() *RedditPlatform {
+	return &RedditPlatform{}
+}
+
+// GetName returns the platform name
+func (r *RedditPlatform) GetName() string {
+	return "reddit"
+}
+
+// Capabilities reports what Reddit supports, media URLs are posted as links
+func (r *RedditPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+	}
+}
+
+// redditSubmission is the submission data returned in Reddit listings
+type redditSubmission struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	SelfText    string  `json:"selftext"`
+	URL         string  `json:"url"`
+	Permalink   string  `json:"permalink"`
+	CreatedUTC  float64 `json:"created_utc"`
+	Score       int     `json:"score"`
+	Ups         int     `json:"ups"`
+	UpvoteRatio float64 `json:"upvote_ratio"`
+	NumComments int     `json:"num_comments"`
+	IsSelf      bool    `json:"is_self"`
+	IsVideo     bool    `json:"is_video"`
+	PostHint    string  `json:"post_hint"`
+}
+
+// redditListing is a page of submissions
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data redditSubmission `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// apiError builds an error from a non-2xx Reddit response
+func (r *RedditPlatform) apiError(operation string, statusCode int, body []byte) error {
+	var errorResponse struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Message != "" {
+		return platformError(statusCode, body, fmt.Errorf("reddit %sapi error (%d): %s", operation, statusCode, errorResponse.Message))
+	}
+	return platformError(statusCode, body, fmt.Errorf("reddit %sapi error: status=%d body=%s", operation, statusCode, string(body)))
+}
+
+// Validate checks that a share request can be submitted to Reddit
+func (r *RedditPlatform) Validate(req *types.ShareRequest) error {
+	if req.Subreddit == "" {
+		return types.NewValidationError("subreddit is required for reddit posts")
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		return types.NewValidationError("title is required for reddit posts")
+	}
+	if utf8.RuneCountInString(req.Title) > redditMaxTitleLength {
+		return types.NewValidationError("reddit title exceeds %d characters", redditMaxTitleLength)
+	}
+	if req.MediaURL == "" && strings.TrimSpace(req.Content) == "" {
+		return types.NewValidationError("content or media_url required for reddit posts")
+	}
+	if utf8.RuneCountInString(req.Content) > redditMaxSelfTextLength {
+		return types.NewValidationError("reddit post text exceeds %d characters", redditMaxSelfTextLength)
+	}
+	return nil
+}
+
+// Share submits a link post for the media URL, or a text post for the content, to the subreddit
+func (r *RedditPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := r.Validate(req); err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"api_type":    {"json"},
+		"sr":          {strings.TrimPrefix(req.Subreddit, "r/")},
+		"title":       {req.Title},
+		"resubmit":    {"true"},
+		"sendreplies": {"true"},
+	}
+	if req.MediaURL != "" {
+		form.Set("kind", "link")
+		form.Set("url", req.MediaURL)
+		if req.Content != "" {
+			types.AddShareWarning(ctx, "reddit link posts have no body, content was left out")
+		}
+	} else {
+		form.Set("kind", "self")
+		form.Set("text", req.Content)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", redditAPIBaseURL+"/api/submit", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create reddit submit request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send reddit submit request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read reddit submit response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", r.apiError("submit ", resp.StatusCode, body)
+	}
+
+	var submitResponse struct {
+		JSON struct {
+			Errors [][]any `json:"errors"`
+			Data   struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := json.Unmarshal(body, &submitResponse); err != nil {
+		return "", fmt.Errorf("failed to parse reddit submit response: %w", err)
+	}
+
+	// Reddit reports rejected submissions as [code, message, field] entries in a 200 response
+	if errs := submitResponse.JSON.Errors; len(errs) > 0 {
+		code := fmt.Sprint(errs[0][0])
+		message := code
+		if len(errs[0]) > 1 {
+			message = fmt.Sprintf("%s: %v", code, errs[0][1])
+		}
+		statusCode := http.StatusUnprocessableEntity
+		if code == "RATELIMIT" {
+			statusCode = http.StatusTooManyRequests
+		}
+		return "", platformError(statusCode, body, fmt.Errorf("reddit submit rejected: %s", message))
+	}
+
+	if submitResponse.JSON.Data.ID == "" {
+		return "", fmt.Errorf("reddit submit response has no post id")
+	}
+
+	return submitResponse.JSON.Data.ID, nil
+}
+
+// GetStats retrieves the score, upvote ratio and comment count of a submission
+func (r *RedditPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	submission, err := r.getSubmission(ctx, client, mediaID, "stats")
+	if err != nil {
+		return types.StatsData{}, err
+	}
+
+	return redditStats(submission), nil
+}
+
+// GetPost retrieves a single submission by ID
+func (r *RedditPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	submission, err := r.getSubmission(ctx, client, mediaID, "post")
+	if err != nil {
+		return types.Post{}, err
+	}
+
+	return redditPost(submission), nil
+}
+
+// getSubmission looks up a submission through /api/info, operation names the request in errors
+func (r *RedditPlatform) getSubmission(ctx context.Context, client *http.Client, mediaID, operation string) (redditSubmission, error) {
+	if mediaID == "" {
+		return redditSubmission{}, fmt.Errorf("media_id required")
+	}
+
+	infoURL := fmt.Sprintf("%s/api/info?id=%s&raw_json=1", redditAPIBaseURL, url.QueryEscape(redditLinkPrefix+strings.TrimPrefix(mediaID, redditLinkPrefix)))
+	req, err := http.NewRequestWithContext(ctx, "GET", infoURL, nil)
+	if err != nil {
+		return redditSubmission{}, fmt.Errorf("failed to create reddit %s request: %w", operation, err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return redditSubmission{}, fmt.Errorf("failed to get reddit %s: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return redditSubmission{}, fmt.Errorf("failed to read reddit %s response: %w", operation, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return redditSubmission{}, r.apiError(operation+" ", resp.StatusCode, body)
+	}
+
+	var listing redditListing
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return redditSubmission{}, fmt.Errorf("failed to parse reddit %s response: %w", operation, err)
+	}
+	if len(listing.Data.Children) == 0 {
+		return redditSubmission{}, platformError(http.StatusNotFound, body, fmt.Errorf("reddit post %s not found", mediaID))
+	}
+
+	return listing.Data.Children[0].Data, nil
+}
+
+// DeletePost deletes a submission
+func (r *RedditPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	form := url.Values{"id": {redditLinkPrefix + strings.TrimPrefix(mediaID, redditLinkPrefix)}}
+	req, err := http.NewRequestWithContext(ctx, "POST", redditAPIBaseURL+"/api/del", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create reddit delete request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send reddit delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read reddit delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return r.apiError("delete ", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetUserInfo retrieves the authenticated user from /api/v1/me
+func (r *RedditPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types.UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", redditAPIBaseURL+"/api/v1/me?raw_json=1", nil)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to read user info response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return types.UserInfo{}, r.apiError("user info ", resp.StatusCode, body)
+	}
+
+	var userResponse struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		IconImg   string `json:"icon_img"`
+		Verified  bool   `json:"verified"`
+		Subreddit struct {
+			Title       string `json:"title"`
+			Subscribers int    `json:"subscribers"`
+		} `json:"subreddit"`
+	}
+	if err := json.Unmarshal(body, &userResponse); err != nil {
+		return types.UserInfo{}, fmt.Errorf("failed to parse user info response: %w", err)
+	}
+
+	// The profile title is the display name, users without one only have a username
+	displayName := userResponse.Subreddit.Title
+	if displayName == "" {
+		displayName = userResponse.Name
+	}
+
+	return types.UserInfo{
+		ID:          userResponse.ID,
+		Username:    userResponse.Name,
+		DisplayName: displayName,
+		AvatarURL:   userResponse.IconImg,
+		ProfileURL:  fmt.Sprintf("https://www.reddit.com/user/%s/", userResponse.Name),
+		Verified:    userResponse.Verified,
+		Followers:   userResponse.Subreddit.Subscribers, // profile subscribers are Reddit's followers
+	}, nil
+}
+
+// GetRecentPosts retrieves the user's recent submissions
+func (r *RedditPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > redditMaxPageSize {
+		limit = redditMaxPageSize
+	}
+
+	// Submissions are listed by username, which the token doesn't carry
+	userInfo, err := r.GetUserInfo(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reddit user: %w", err)
+	}
+
+	submittedURL := fmt.Sprintf("%s/user/%s/submitted?limit=%d&sort=new&raw_json=1", redditAPIBaseURL, url.PathEscape(userInfo.Username), limit)
+	req, err := http.NewRequestWithContext(ctx, "GET", submittedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, r.apiError("", resp.StatusCode, body)
+	}
+
+	var listing redditListing
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, fmt.Errorf("failed to parse reddit submissions response: %w", err)
+	}
+
+	var posts []types.Post
+	for _, child := range listing.Data.Children {
+		submission := child.Data
+		createdAt := int64(submission.CreatedUTC)
+
+		// The submitted listing has no time filters, so apply the range here
+		if startTime > 0 && createdAt < startTime {
+			continue
+		}
+		if endTime > 0 && createdAt > endTime {
+			continue
+		}
+
+		posts = append(posts, redditPost(submission))
+	}
+
+	return posts, nil
+}
+
+// HandleOAuthCallback handles OAuth callback for Reddit platform
+func (r *RedditPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
+	return nil
+}
+
+// redditStats converts a submission's counters to stats
+func redditStats(submission redditSubmission) types.StatsData {
+	return fetchedStats(types.StatsData{
+		Likes:       submission.Ups,
+		Replies:     submission.NumComments,
+		Score:       submission.Score,
+		UpvoteRatio: submission.UpvoteRatio,
+	})
+}
+
+// redditPost converts a submission to a post
+func redditPost(submission redditSubmission) types.Post {
+	post := types.Post{
+		ID:          submission.ID,
+		Content:     submission.SelfText,
+		CreatedAt:   int64(submission.CreatedUTC),
+		Stats:       redditStats(submission),
+		URL:         "https://www.reddit.com" + submission.Permalink,
+		MediaType:   redditMediaType(submission),
+		Title:       submission.Title,
+		Description: submission.SelfText,
+		Tags:        []string{},
+	}
+	if !submission.IsSelf {
+		post.MediaURL = submission.URL
+	}
+	return post
+}
+
+// redditMediaType maps a submission to a post media type
+func redditMediaType(submission redditSubmission) string {
+	switch {
+	case submission.IsSelf:
+		return "text"
+	case submission.IsVideo || strings.Contains(submission.PostHint, "video"):
+		return "video"
+	case submission.PostHint == "image":
+		return "image"
+	default:
+		return ""
+	}
+}
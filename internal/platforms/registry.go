@@ -1,12 +1,42 @@
 package platforms
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+
 	"social/internal/types"
 )
 
-// Registry manages platform implementations
+// ErrPlatformRegistered is returned when registering a platform under a name that is already taken
+var ErrPlatformRegistered = errors.New("platform already registered")
+
+// ErrPlatformNotRegistered is returned when replacing a platform that was never registered
+var ErrPlatformNotRegistered = errors.New("platform not registered")
+
+// providerAliases maps names users commonly type to the canonical platform names
+var providerAliases = map[string]string{
+	"twitter": "x",
+	"ig":      "instagram",
+	"yt":      "youtube",
+	"fb":      "facebook",
+}
+
+// CanonicalName returns the canonical platform name for name, resolving aliases such as twitter
+// for x and ignoring case. Names that aren't aliases are returned lowercased.
+func CanonicalName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := providerAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// Registry manages platform implementations. It is safe for concurrent use, so platforms can be
+// registered or replaced while requests are being served.
 type Registry struct {
+	mu        sync.RWMutex
 	platforms map[string]types.Platform
 }
 
@@ -17,34 +47,201 @@ func NewRegistry() *Registry {
 	}
 
 	// Register all platforms
-	registry.Register(NewXPlatform())
-	registry.Register(NewYouTubePlatform())
-	registry.Register(NewFacebookPlatform())
-	registry.Register(NewTikTokPlatform())
-	registry.Register(NewInstagramPlatform())
+	registry.mustRegister(NewXPlatform())
+	registry.mustRegister(NewYouTubePlatform())
+	registry.mustRegister(NewFacebookPlatform())
+	registry.mustRegister(NewTikTokPlatform())
+	registry.mustRegister(NewInstagramPlatform())
+	registry.mustRegister(NewPinterestPlatform())
+	registry.mustRegister(NewMastodonPlatform())
+	registry.mustRegister(NewRedditPlatform())
+	registry.mustRegister(NewDiscordPlatform())
+	registry.mustRegister(NewThreadsPlatform())
 
 	return registry
 }
 
-// Register registers a platform implementation
-func (r *Registry) Register(platform types.Platform) {
-	r.platforms[platform.GetName()] = platform
+// Register registers a platform implementation, failing with ErrPlatformRegistered if its name is
+// taken. Use Replace to swap out a registered platform.
+func (r *Registry) Register(platform types.Platform) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := platform.GetName()
+	if _, exists := r.platforms[name]; exists {
+		return fmt.Errorf("%w: %s", ErrPlatformRegistered, name)
+	}
+	r.platforms[name] = platform
+	return nil
+}
+
+// Replace swaps a registered platform for another implementation of the same name, failing with
+// ErrPlatformNotRegistered if there is nothing to replace
+func (r *Registry) Replace(platform types.Platform) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := platform.GetName()
+	if _, exists := r.platforms[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrPlatformNotRegistered, name)
+	}
+	r.platforms[name] = platform
+	return nil
+}
+
+// mustRegister registers a built-in platform, two of them sharing a name is a programming error
+func (r *Registry) mustRegister(platform types.Platform) {
+	if err := r.Register(platform); err != nil {
+		panic(err)
+	}
+}
+
+// UseSandbox replaces the named platforms with sandbox stand-ins that never call the provider,
+// or all registered platforms if no names are given
+func (r *Registry) UseSandbox(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(names) == 0 {
+		for name := range r.platforms {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		if platform, exists := r.platforms[name]; exists {
+			r.platforms[name] = NewSandboxPlatform(platform)
+		}
+	}
 }
 
-// GetPlatform returns a platform implementation by name
+// GetPlatform returns a platform implementation by name or alias
 func (r *Registry) GetPlatform(name string) (types.Platform, error) {
-	platform, exists := r.platforms[name]
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	platform, exists := r.platforms[CanonicalName(name)]
 	if !exists {
 		return nil, fmt.Errorf("platform %s not supported", name)
 	}
 	return platform, nil
 }
 
+// IsSupported reports whether a platform is registered under name or the name it is an alias of
+func (r *Registry) IsSupported(name string) bool {
+	_, ok := r.Resolve(name)
+	return ok
+}
+
+// Resolve returns the canonical name of the platform registered under name or the name it is an
+// alias of, and false if there is none
+func (r *Registry) Resolve(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	canonical := CanonicalName(name)
+	_, exists := r.platforms[canonical]
+	return canonical, exists
+}
+
 // GetSupportedPlatforms returns a list of supported platform names
 func (r *Registry) GetSupportedPlatforms() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var platforms []string
 	for name := range r.platforms {
 		platforms = append(platforms, name)
 	}
 	return platforms
 }
+
+// PostURL returns the public URL of a post, or an empty string if the
+// platform's post URL can't be derived from the media ID alone
+func PostURL(provider, mediaID string) string {
+	if mediaID == "" {
+		return ""
+	}
+
+	switch provider {
+	case "x":
+		return fmt.Sprintf("https://x.com/i/web/status/%s", mediaID)
+	case "youtube":
+		return fmt.Sprintf("https://www.youtube.com/watch?v=%s", mediaID)
+	case "facebook":
+		return fmt.Sprintf("https://www.facebook.com/%s", mediaID)
+	case "pinterest":
+		return fmt.Sprintf("https://www.pinterest.com/pin/%s/", mediaID)
+	case "reddit":
+		return fmt.Sprintf("https://www.reddit.com/comments/%s/", mediaID)
+	default:
+		// Instagram and Threads need the shortcode, TikTok and Mastodon the username, Discord the guild ID
+		return ""
+	}
+}
+
+// ParsePostRef builds a structured reference for a post, splitting composite
+// media IDs into their components. It returns nil if the media ID is empty.
+func ParsePostRef(provider, mediaID string) *types.PostRef {
+	if mediaID == "" {
+		return nil
+	}
+
+	components := make(map[string]string)
+	switch provider {
+	case "x":
+		components["tweet_id"] = mediaID
+	case "youtube":
+		components["video_id"] = mediaID
+	case "facebook":
+		// Feed posts are returned as {page_or_user_id}_{post_id}, photos as a bare object ID
+		if ownerID, postID, ok := strings.Cut(mediaID, "_"); ok && ownerID != "" && postID != "" {
+			components["page_id"] = ownerID
+			components["post_id"] = postID
+		} else {
+			components["object_id"] = mediaID
+		}
+	case "instagram":
+		components["media_id"] = mediaID
+	case "pinterest":
+		components["pin_id"] = mediaID
+	case "mastodon":
+		components["status_id"] = mediaID
+	case "reddit":
+		components["post_id"] = mediaID
+	case "threads":
+		components["media_id"] = mediaID
+	case "discord":
+		// Messages are addressed by channel, Share returns {channel_id}_{message_id}
+		if channelID, messageID, err := splitDiscordMediaID(mediaID); err == nil {
+			components["channel_id"] = channelID
+			components["message_id"] = messageID
+		}
+	case "tiktok":
+		// Share returns the public post ID once published, otherwise the publish ID
+		if isNumeric(mediaID) {
+			components["post_id"] = mediaID
+		} else {
+			components["publish_id"] = mediaID
+		}
+	}
+
+	return &types.PostRef{
+		Provider:   provider,
+		ID:         mediaID,
+		Components: components,
+	}
+}
+
+// isNumeric reports whether s consists only of ASCII digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package platforms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"social/internal/types"
+)
+
+// sandboxIDPrefix marks media IDs returned by sandbox platforms
+const sandboxIDPrefix = "sandbox_"
+
+// SandboxPlatform stands in for a provider in sandbox mode. It never calls the provider: shares
+// return deterministic fake media IDs derived from the request and stats are derived from the
+// media ID, so integration tests against this API get stable results. Requests are still checked
+// with the real platform's Validate so content the provider would reject fails the same way.
+type SandboxPlatform struct {
+	name     string
+	platform types.Platform // the real implementation, only used to validate requests
+
+	mu    sync.Mutex
+	posts []types.Post // posts shared since startup, newest first
+}
+
+// NewSandboxPlatform creates a sandbox stand-in for platform
+func NewSandboxPlatform(platform types.Platform) *SandboxPlatform {
+	return &SandboxPlatform{
+		name:     platform.GetName(),
+		platform: platform,
+	}
+}
+
+// GetName returns the name of the platform it stands in for
+func (s *SandboxPlatform) GetName() string {
+	return s.name
+}
+
+// Capabilities reports the capabilities of the real platform, which the sandbox mirrors
+func (s *SandboxPlatform) Capabilities() types.PlatformCapabilities {
+	return s.platform.Capabilities()
+}
+
+// Validate applies the real platform's request checks
+func (s *SandboxPlatform) Validate(req *types.ShareRequest) error {
+	if validator, ok := s.platform.(types.ShareValidator); ok {
+		return validator.Validate(req)
+	}
+	return nil
+}
+
+// Share records the post and returns a fake media ID, the same one for the same request
+func (s *SandboxPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := s.Validate(req); err != nil {
+		return "", err
+	}
+
+	mediaID := s.mediaID(req)
+	post := types.Post{
+		ID:          mediaID,
+		Content:     req.Content,
+		MediaURL:    req.MediaURL,
+		CreatedAt:   time.Now().Unix(),
+		Stats:       s.stats(mediaID),
+		Title:       req.Title,
+		Description: req.Desc,
+		Tags:        req.Tags,
+	}
+	if post.Tags == nil {
+		post.Tags = []string{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removePost(mediaID)
+	s.posts = append([]types.Post{post}, s.posts...)
+
+	return mediaID, nil
+}
+
+// GetStats returns fake stats derived from the media ID
+func (s *SandboxPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	return s.stats(mediaID), nil
+}
+
+// GetPost returns a post shared to the sandbox
+func (s *SandboxPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, post := range s.posts {
+		if post.ID == mediaID {
+			return post, nil
+		}
+	}
+	return types.Post{}, platformError(http.StatusNotFound, nil, fmt.Errorf("sandbox post %s not found", mediaID))
+}
+
+// GetUserInfo returns a fixed sandbox user
+func (s *SandboxPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types.UserInfo, error) {
+	return types.UserInfo{
+		ID:          sandboxIDPrefix + s.name + "_user",
+		Username:    "sandbox_user",
+		DisplayName: fmt.Sprintf("Sandbox %s User", s.name),
+	}, nil
+}
+
+// GetRecentPosts returns the posts shared to the sandbox within the time range, newest first
+func (s *SandboxPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	posts := make([]types.Post, 0, len(s.posts))
+	for _, post := range s.posts {
+		if (startTime > 0 && post.CreatedAt < startTime) || (endTime > 0 && post.CreatedAt > endTime) {
+			continue
+		}
+		posts = append(posts, post)
+		if limit > 0 && len(posts) >= limit {
+			break
+		}
+	}
+
+	return posts, nil
+}
+
+// DeletePost forgets a post shared to the sandbox
+func (s *SandboxPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removePost(mediaID)
+	return nil
+}
+
+// HandleOAuthCallback is a no-op, sandbox platforms don't need authorization
+func (s *SandboxPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
+	return nil
+}
+
+// mediaID derives the fake media ID of a share request
+func (s *SandboxPlatform) mediaID(req *types.ShareRequest) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%s\x00%s\x00%s", req.ServerName, req.UserID, req.Content, req.MediaURL, req.Title))
+	return sandboxIDPrefix + s.name + "_" + hex.EncodeToString(sum[:8])
+}
+
+// stats derives fake stats from a media ID
+func (s *SandboxPlatform) stats(mediaID string) types.StatsData {
+	sum := sha256.Sum256([]byte(mediaID))
+	seed := binary.BigEndian.Uint64(sum[:8])
+
+	return fetchedStats(types.StatsData{
+		Likes:    int(seed % 1000),
+		Retweets: int(seed / 1000 % 100),
+		Replies:  int(seed / 100000 % 100),
+		Views:    int(seed / 10000000 % 100000),
+		Shares:   int(seed / 1000000000000 % 100),
+	})
+}
+
+// removePost drops a post from the recorded ones, the caller must hold s.mu
+func (s *SandboxPlatform) removePost(mediaID string) {
+	for i, post := range s.posts {
+		if post.ID == mediaID {
+			s.posts = append(s.posts[:i], s.posts[i+1:]...)
+			return
+		}
+	}
+}
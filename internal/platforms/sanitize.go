@@ -0,0 +1,70 @@
+package platforms
+
+import (
+	"strings"
+	"unicode"
+
+	"social/internal/types"
+)
+
+// zeroWidthChars are invisible characters that carry no meaning in post text.
+// The zero-width joiner and non-joiner are kept since emoji sequences and several scripts depend on them.
+var zeroWidthChars = map[rune]bool{
+	'\u200B': true, // zero-width space
+	'\u2060': true, // word joiner
+	'\u180E': true, // mongolian vowel separator
+	'\uFEFF': true, // byte order mark / zero-width no-break space
+}
+
+// isBidiControl reports whether r is a bidirectional embedding, override or isolate character,
+// which can make text render in a different order than it is stored
+func isBidiControl(r rune) bool {
+	return (r >= '\u202A' && r <= '\u202E') || (r >= '\u2066' && r <= '\u2069')
+}
+
+// Sanitizer strips invisible characters from share content before it is posted,
+// since pasted text often carries characters that fail platform validation or render as boxes
+type Sanitizer struct {
+	zeroWidth bool
+	control   bool
+}
+
+// NewSanitizer creates a sanitizer stripping zero-width and/or control characters
+func NewSanitizer(zeroWidth, control bool) *Sanitizer {
+	return &Sanitizer{
+		zeroWidth: zeroWidth,
+		control:   control,
+	}
+}
+
+// Sanitize removes the configured characters from text. Newlines and tabs are always kept.
+func (s *Sanitizer) Sanitize(text string) string {
+	if s == nil || (!s.zeroWidth && !s.control) {
+		return text
+	}
+
+	return strings.Map(func(r rune) rune {
+		if s.zeroWidth && zeroWidthChars[r] {
+			return -1
+		}
+		if s.control && r != '\n' && r != '\t' && (unicode.IsControl(r) || isBidiControl(r)) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// ShareRequest sanitizes the text fields of a share request in place
+func (s *Sanitizer) ShareRequest(req *types.ShareRequest) {
+	req.Content = s.Sanitize(req.Content)
+	req.Title = s.Sanitize(req.Title)
+	req.Desc = s.Sanitize(req.Desc)
+	req.MediaAltText = s.Sanitize(req.MediaAltText)
+
+	for i := range req.Thread {
+		req.Thread[i] = s.Sanitize(req.Thread[i])
+	}
+	for i := range req.Tags {
+		req.Tags[i] = s.Sanitize(req.Tags[i])
+	}
+}
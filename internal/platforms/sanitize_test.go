@@ -0,0 +1,79 @@
+package platforms
+
+import (
+	"slices"
+	"testing"
+
+	"social/internal/types"
+)
+
+func TestSanitizerSanitize(t *testing.T) {
+	tests := []struct {
+		name      string
+		zeroWidth bool
+		control   bool
+		input     string
+		want      string
+	}{
+		{name: "zero-width space", zeroWidth: true, control: true, input: "hel\u200Blo", want: "hello"},
+		{name: "byte order mark", zeroWidth: true, control: true, input: "\uFEFFhello", want: "hello"},
+		{name: "word joiner", zeroWidth: true, control: true, input: "hello\u2060world", want: "helloworld"},
+		{name: "zwj emoji sequence kept", zeroWidth: true, control: true, input: "family 👨\u200D👩\u200D👧", want: "family 👨\u200D👩\u200D👧"},
+		{name: "zwnj kept", zeroWidth: true, control: true, input: "می\u200Cخواهم", want: "می\u200Cخواهم"},
+		{name: "bidi override", zeroWidth: true, control: true, input: "invoice\u202Efdp.exe", want: "invoicefdp.exe"},
+		{name: "bidi isolate", zeroWidth: true, control: true, input: "\u2066text\u2069", want: "text"},
+		{name: "control characters", zeroWidth: true, control: true, input: "a\x00b\x07c\rd", want: "abcd"},
+		{name: "newline and tab kept", zeroWidth: true, control: true, input: "line one\n\tline two", want: "line one\n\tline two"},
+		{name: "zero-width only", zeroWidth: true, control: false, input: "a\u200Bb\u202Ec", want: "ab\u202Ec"},
+		{name: "control only", zeroWidth: false, control: true, input: "a\u200Bb\u202Ec", want: "a\u200Bbc"},
+		{name: "both flags off", zeroWidth: false, control: false, input: "a\u200Bb\u202Ec\x00", want: "a\u200Bb\u202Ec\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSanitizer(tt.zeroWidth, tt.control).Sanitize(tt.input)
+			if got != tt.want {
+				t.Fatalf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizerNil(t *testing.T) {
+	var s *Sanitizer
+	if got := s.Sanitize("a\u200Bb"); got != "a\u200Bb" {
+		t.Fatalf("nil Sanitize = %q, want input unchanged", got)
+	}
+}
+
+func TestSanitizerShareRequest(t *testing.T) {
+	req := &types.ShareRequest{
+		Content:      "hello\u200B world",
+		Title:        "\uFEFFtitle",
+		Desc:         "desc\u202E",
+		MediaAltText: "a dog\u200B",
+		Thread:       []string{"second\u2060 tweet", "third tweet"},
+		Tags:         []string{"go\u200Blang", "news"},
+	}
+
+	NewSanitizer(true, true).ShareRequest(req)
+
+	if req.Content != "hello world" {
+		t.Errorf("Content = %q", req.Content)
+	}
+	if req.Title != "title" {
+		t.Errorf("Title = %q", req.Title)
+	}
+	if req.Desc != "desc" {
+		t.Errorf("Desc = %q", req.Desc)
+	}
+	if req.MediaAltText != "a dog" {
+		t.Errorf("MediaAltText = %q", req.MediaAltText)
+	}
+	if want := []string{"second tweet", "third tweet"}; !slices.Equal(req.Thread, want) {
+		t.Errorf("Thread = %q, want %q", req.Thread, want)
+	}
+	if want := []string{"golang", "news"}; !slices.Equal(req.Tags, want) {
+		t.Errorf("Tags = %q, want %q", req.Tags, want)
+	}
+}
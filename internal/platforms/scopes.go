@@ -0,0 +1,20 @@
+package platforms
+
+import (
+	"net/http"
+	"strings"
+)
+
+// isScopeRejection reports whether an error response says the token lacks a permission.
+// Providers answer with 403, or 400/401 for Facebook permission errors and TikTok's
+// scope_not_authorized, and mention the scope or permission in the body.
+func isScopeRejection(statusCode int, body []byte) bool {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+	default:
+		return false
+	}
+
+	text := strings.ToLower(string(body))
+	return strings.Contains(text, "scope") || strings.Contains(text, "permission")
+}
@@ -0,0 +1,34 @@
+package platforms
+
+import "social/internal/types"
+
+// Metric names reported in StatsData.MissingMetrics, matching the StatsData JSON fields
+const (
+	metricLikes    = "likes"
+	metricRetweets = "retweets"
+	metricReplies  = "replies"
+	metricViews    = "views"
+	metricShares   = "shares"
+)
+
+// Platform-specific metrics reported in StatsData.Extra, named as the platform names them
+const (
+	extraImpressionCount         = "impression_count"
+	extraQuoteCount              = "quote_count"
+	extraBookmarkCount           = "bookmark_count"
+	extraReach                   = "reach"
+	extraSaved                   = "saved"
+	extraEstimatedMinutesWatched = "estimatedMinutesWatched"
+)
+
+// fetchedStats marks stats as successfully fetched. missing lists the metrics the platform usually
+// reports but left out of this response, such as likes hidden by the owner, so consumers can tell
+// them apart from genuine zeros.
+func fetchedStats(stats types.StatsData, missing ...string) types.StatsData {
+	stats.HasStats = true
+	if len(missing) > 0 {
+		stats.Partial = true
+		stats.MissingMetrics = missing
+	}
+	return stats
+}
@@ -0,0 +1,462 @@
+package platforms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"social/internal/types"
+	"social/pkg/httpx"
+	"social/pkg/media"
+)
+
+// threadsAPIBaseURL is the Threads Graph API root
+const threadsAPIBaseURL = "https://graph.threads.net/v1.0"
+
+// threadsMaxTextLength is the longest text a thread accepts, in characters
+const threadsMaxTextLength = 500
+
+// threadsMaxPageSize is the largest limit the user threads endpoint accepts
+const threadsMaxPageSize = 100
+
+// Video containers are processed asynchronously and can only be published once finished
+const (
+	threadsContainerPollInterval = 3 * time.Second
+	threadsContainerMaxPolls     = 40
+)
+
+// threadsMediaFields are the Graph API fields requested for threads
+const threadsMediaFields = "id,media_type,media_url,permalink,text,timestamp,thumbnail_url"
+
+// threadsInsightMetrics are the insights requested for a thread, in the order of the StatsData fields
+const threadsInsightMetrics = "views,likes,replies,reposts,quotes"
+
+// ThreadsPlatform implements the Threads platform
+type ThreadsPlatform struct{}
+
+// NewThreadsPlatform creates a new Threads platform instance
+func NewThreadsPlatform() *ThreadsPlatform {
+	return &ThreadsPlatform{}
+}
+
+// GetName returns the platform name
+func (t *ThreadsPlatform) GetName() string {
+	return "threads"
+}
+
+// Capabilities reports what Threads supports, a thread is text with at most one image or video
+func (t *ThreadsPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+		SupportsVideo:       true,
+	}
+}
+
+// apiError builds an error from a non-2xx Threads Graph API response
+func (t *ThreadsPlatform) apiError(operation string, statusCode int, body []byte) error {
+	var errorResponse struct {
+		Error struct {
+			Message   string `json:"message"`
+			Type      string `json:"type"`
+			Code      int    `json:"code"`
+			SubCode   int    `json:"error_subcode,omitempty"`
+			FBTraceID string `json:"fbtrace_id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error.Message != "" {
+		return platformError(statusCode, body, fmt.Errorf("threads %sapi error (%d): %s", operation, errorResponse.Error.Code, errorResponse.Error.Message))
+	}
+	return platformError(statusCode, body, fmt.Errorf("threads %sapi error: status=%d body=%s", operation, statusCode, string(body)))
+}
+
+// Validate checks that a share request can be posted to Threads
+func (t *ThreadsPlatform) Validate(req *types.ShareRequest) error {
+	if strings.TrimSpace(req.Content) == "" && req.MediaURL == "" {
+		return types.NewValidationError("content or media_url is required for threads posts")
+	}
+	if utf8.RuneCountInString(req.Content) > threadsMaxTextLength {
+		return types.NewValidationError("threads text must be at most %d characters", threadsMaxTextLength)
+	}
+	return nil
+}
+
+// Share creates a media container on the user's account and publishes it
+func (t *ThreadsPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := t.Validate(req); err != nil {
+		return "", err
+	}
+
+	userInfo, err := t.GetUserInfo(ctx, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to get threads user: %w", err)
+	}
+
+	// Step 1: Create media container
+	form := url.Values{}
+	form.Set("text", req.Content)
+	mediaType := t.mediaType(req.MediaURL)
+	form.Set("media_type", mediaType)
+	switch mediaType {
+	case "IMAGE":
+		form.Set("image_url", req.MediaURL)
+	case "VIDEO":
+		form.Set("video_url", req.MediaURL)
+	}
+
+	var container struct {
+		ID string `json:"id"`
+	}
+	if err := t.post(ctx, client, fmt.Sprintf("%s/%s/threads", threadsAPIBaseURL, url.PathEscape(userInfo.ID)), form, "media ", &container); err != nil {
+		return "", err
+	}
+	if container.ID == "" {
+		return "", fmt.Errorf("no media container ID in response")
+	}
+
+	if mediaType == "VIDEO" {
+		if err := t.waitForContainer(ctx, client, container.ID); err != nil {
+			return "", err
+		}
+	}
+
+	// Step 2: Publish the media container
+	publishForm := url.Values{}
+	publishForm.Set("creation_id", container.ID)
+
+	var published struct {
+		ID string `json:"id"`
+	}
+	if err := t.post(ctx, client, fmt.Sprintf("%s/%s/threads_publish", threadsAPIBaseURL, url.PathEscape(userInfo.ID)), publishForm, "publish ", &published); err != nil {
+		return "", err
+	}
+
+	return published.ID, nil
+}
+
+// mediaType returns the container media type of a thread with the given media URL
+func (t *ThreadsPlatform) mediaType(mediaURL string) string {
+	if mediaURL == "" {
+		return "TEXT"
+	}
+
+	// Threads fetches the media itself, so only the URL is known here
+	if media.IsVideo(media.TypeByURL(mediaURL)) {
+		return "VIDEO"
+	}
+	return "IMAGE"
+}
+
+// post sends a form to the Threads API and decodes the response into result, operation names
+// the request in errors
+func (t *ThreadsPlatform) post(ctx context.Context, client *http.Client, endpoint string, form url.Values, operation string, result any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create threads %srequest: %w", operation, err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send threads %srequest: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read threads %sresponse: %w", operation, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return t.apiError(operation, resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse threads %sresponse: %w", operation, err)
+	}
+
+	return nil
+}
+
+// get fetches a Threads API resource and decodes the response into result, operation names the
+// request in errors
+func (t *ThreadsPlatform) get(ctx context.Context, client *http.Client, endpoint, operation string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create threads %srequest: %w", operation, err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return fmt.Errorf("failed to send threads %srequest: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read threads %sresponse: %w", operation, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return t.apiError(operation, resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse threads %sresponse: %w", operation, err)
+	}
+
+	return nil
+}
+
+// waitForContainer polls a media container until Threads has finished processing it
+func (t *ThreadsPlatform) waitForContainer(ctx context.Context, client *http.Client, containerID string) error {
+	for attempt := 0; attempt < threadsContainerMaxPolls; attempt++ {
+		var status struct {
+			Status       string `json:"status"`
+			ErrorMessage string `json:"error_message"`
+		}
+		if err := t.get(ctx, client, fmt.Sprintf("%s/%s?fields=status,error_message", threadsAPIBaseURL, url.PathEscape(containerID)), "container status ", &status); err != nil {
+			return err
+		}
+
+		switch status.Status {
+		case "FINISHED", "PUBLISHED":
+			return nil
+		case "ERROR", "EXPIRED":
+			return fmt.Errorf("threads media container %s: %s", strings.ToLower(status.Status), status.ErrorMessage)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(threadsContainerPollInterval):
+		}
+	}
+
+	return fmt.Errorf("threads media container %s was not processed in time", containerID)
+}
+
+// threadsMedia is a thread as returned by the Graph API
+type threadsMedia struct {
+	ID           string `json:"id"`
+	MediaType    string `json:"media_type"`
+	MediaURL     string `json:"media_url"`
+	Permalink    string `json:"permalink"`
+	Text         string `json:"text"`
+	Timestamp    string `json:"timestamp"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// GetStats retrieves views, likes, replies, reposts and quotes of a thread from its insights
+func (t *ThreadsPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	if mediaID == "" {
+		return types.StatsData{}, fmt.Errorf("media_id required")
+	}
+
+	var insights struct {
+		Data []struct {
+			Name   string `json:"name"`
+			Values []struct {
+				Value int `json:"value"`
+			} `json:"values"`
+		} `json:"data"`
+	}
+	if err := t.get(ctx, client, fmt.Sprintf("%s/%s/insights?metric=%s", threadsAPIBaseURL, url.PathEscape(mediaID), threadsInsightMetrics), "stats ", &insights); err != nil {
+		return types.StatsData{}, err
+	}
+
+	values := make(map[string]int, len(insights.Data))
+	for _, metric := range insights.Data {
+		if len(metric.Values) > 0 {
+			values[metric.Name] = metric.Values[0].Value
+		}
+	}
+
+	var stats types.StatsData
+	var missing []string
+	for _, metric := range []struct {
+		name   string
+		target *int
+		report string
+	}{
+		{"views", &stats.Views, metricViews},
+		{"likes", &stats.Likes, metricLikes},
+		{"replies", &stats.Replies, metricReplies},
+		{"reposts", &stats.Retweets, metricRetweets}, // Reposts are Threads' equivalent of retweets
+		{"quotes", &stats.Shares, metricShares},
+	} {
+		value, ok := values[metric.name]
+		if !ok {
+			missing = append(missing, metric.report)
+			continue
+		}
+		*metric.target = value
+	}
+
+	return fetchedStats(stats, missing...), nil
+}
+
+// GetPost retrieves a single thread by ID
+func (t *ThreadsPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	if mediaID == "" {
+		return types.Post{}, fmt.Errorf("media_id required")
+	}
+
+	var media threadsMedia
+	if err := t.get(ctx, client, fmt.Sprintf("%s/%s?fields=%s", threadsAPIBaseURL, url.PathEscape(mediaID), threadsMediaFields), "post ", &media); err != nil {
+		return types.Post{}, err
+	}
+
+	return threadsPost(media), nil
+}
+
+// DeletePost deletes a thread
+func (t *ThreadsPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/%s", threadsAPIBaseURL, url.PathEscape(mediaID)), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create threads delete request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send threads delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read threads delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return t.apiError("delete ", resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// GetUserInfo retrieves the authenticated Threads profile
+func (t *ThreadsPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types.UserInfo, error) {
+	var profile struct {
+		ID                string `json:"id"`
+		Username          string `json:"username"`
+		Name              string `json:"name"`
+		ProfilePictureURL string `json:"threads_profile_picture_url"`
+		IsVerified        bool   `json:"is_verified"`
+	}
+	if err := t.get(ctx, client, threadsAPIBaseURL+"/me?fields=id,username,name,threads_profile_picture_url,is_verified", "user info ", &profile); err != nil {
+		return types.UserInfo{}, err
+	}
+
+	displayName := profile.Name
+	if displayName == "" {
+		displayName = profile.Username
+	}
+
+	return types.UserInfo{
+		ID:          profile.ID,
+		Username:    profile.Username,
+		DisplayName: displayName,
+		AvatarURL:   profile.ProfilePictureURL,
+		ProfileURL:  fmt.Sprintf("https://www.threads.net/@%s", profile.Username),
+		Verified:    profile.IsVerified,
+		// Follower counts are only available through the user insights endpoint
+	}, nil
+}
+
+// GetRecentPosts retrieves the user's recent threads
+func (t *ThreadsPlatform) GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]types.Post, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > threadsMaxPageSize {
+		limit = threadsMaxPageSize
+	}
+
+	params := url.Values{}
+	params.Set("fields", threadsMediaFields)
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	if startTime > 0 {
+		params.Set("since", fmt.Sprintf("%d", startTime))
+	}
+	if endTime > 0 {
+		params.Set("until", fmt.Sprintf("%d", endTime))
+	}
+
+	var mediaResponse struct {
+		Data []threadsMedia `json:"data"`
+	}
+	if err := t.get(ctx, client, threadsAPIBaseURL+"/me/threads?"+params.Encode(), "", &mediaResponse); err != nil {
+		return nil, err
+	}
+
+	var posts []types.Post
+	for _, media := range mediaResponse.Data {
+		posts = append(posts, threadsPost(media))
+	}
+
+	return posts, nil
+}
+
+// threadsPost converts a thread to a post, stats need a separate insights request
+func threadsPost(media threadsMedia) types.Post {
+	createdTime, err := time.Parse("2006-01-02T15:04:05-0700", media.Timestamp)
+	if err != nil {
+		createdTime = time.Now()
+	}
+
+	// Use thumbnail URL if available, otherwise use media URL
+	mediaURL := media.MediaURL
+	if media.ThumbnailURL != "" {
+		mediaURL = media.ThumbnailURL
+	}
+
+	return types.Post{
+		ID:        media.ID,
+		Content:   media.Text,
+		CreatedAt: createdTime.Unix(),
+		URL:       media.Permalink,
+		MediaType: threadsMediaType(media.MediaType),
+		MediaURL:  mediaURL,
+	}
+}
+
+// threadsMediaType maps a Threads media type to a Post media type
+func threadsMediaType(mediaType string) string {
+	switch mediaType {
+	case "VIDEO":
+		return "video"
+	case "IMAGE", "CAROUSEL_ALBUM":
+		return "image"
+	case "TEXT_POST", "REPOST_FACADE":
+		return "text"
+	default:
+		return strings.ToLower(mediaType)
+	}
+}
+
+// HandleOAuthCallback handles OAuth callback for Threads platform
+func (t *ThreadsPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
+	// Threads平台特定的OAuth回调处理逻辑
+	return nil
+}
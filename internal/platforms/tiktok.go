@@ -1,14 +1,18 @@
 package platforms
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"social/internal/types"
+	"social/pkg/httpx"
 )
 
 // TikTokPlatform implements the TikTok platform
@@ -24,32 +28,74 @@ func (t *TikTokPlatform) GetName() string {
 	return "tiktok"
 }
 
-// Share shares content to TikTok
-func (t *TikTokPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
-	// TikTok for Developers API requires video upload
-	// This is a simplified implementation - in production you need proper video handling
+// Capabilities reports what TikTok supports, only videos can be posted and the API can't delete them
+func (t *TikTokPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		RequiresMedia:       true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+		SupportsVideo:       true,
+	}
+}
 
+// TikTok chunk size limits for FILE_UPLOAD, see Content Posting API docs
+const (
+	tiktokMinChunkSize     = 5 * 1024 * 1024
+	tiktokDefaultChunkSize = 10 * 1024 * 1024
+	tiktokStatusPollDelay  = 3 * time.Second
+)
+
+// tiktokMaxCaptionLength is the longest video caption TikTok accepts
+const tiktokMaxCaptionLength = 2200
+
+// Validate checks that a share request can be posted to TikTok
+func (t *TikTokPlatform) Validate(req *types.ShareRequest) error {
 	if req.MediaURL == "" {
-		return "", fmt.Errorf("media_url is required for TikTok video posts")
+		return types.NewValidationError("media_url is required for TikTok video posts")
+	}
+	if utf8.RuneCountInString(req.Title) > tiktokMaxCaptionLength {
+		return types.NewValidationError("tiktok title exceeds %d characters", tiktokMaxCaptionLength)
+	}
+	if utf8.RuneCountInString(req.Content) > tiktokMaxCaptionLength {
+		return types.NewValidationError("tiktok caption exceeds %d characters", tiktokMaxCaptionLength)
+	}
+	return nil
+}
+
+// Share shares content to TikTok
+func (t *TikTokPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
+	if err := t.Validate(req); err != nil {
+		return "", err
 	}
 
 	// TikTok API requires a multi-step process:
 	// 1. Initialize video upload
-	// 2. Upload video data
-	// 3. Publish video
+	// 2. Upload video data in chunks
+	// 3. Poll publish status until the post is published
+
+	// Download the video so we know its real size
+	videoData, _, err := downloadMedia(ctx, req.MediaURL, tiktokMaxVideoSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to download media: %w", err)
+	}
+
+	videoSize := int64(len(videoData))
+	chunkSize, totalChunkCount := t.calculateChunks(videoSize)
 
 	// Step 1: Initialize video upload
 	initData := map[string]any{
 		"source_info": map[string]any{
 			"source":            "FILE_UPLOAD",
-			"video_size":        0, // This would be the actual file size
-			"chunk_size":        0, // This would be the chunk size for upload
-			"total_chunk_count": 0, // This would be calculated based on file size
+			"video_size":        videoSize,
+			"chunk_size":        chunkSize,
+			"total_chunk_count": totalChunkCount,
 		},
 		"post_info": map[string]any{
 			"title":                    req.Title,
 			"description":              req.Content,
-			"privacy_level":            "MUTUAL_FOLLOW_FRIEND", // Default privacy level
+			"privacy_level":            t.getPrivacyLevel(req),
 			"disable_duet":             false,
 			"disable_comment":          false,
 			"disable_stitch":           false,
@@ -63,12 +109,12 @@ func (t *TikTokPlatform) Share(ctx context.Context, client *http.Client, req *ty
 	}
 
 	// Initialize upload
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://open-api.tiktok.com/share/video/upload/", strings.NewReader(string(jsonData)))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://open.tiktokapis.com/v2/post/publish/video/init/", strings.NewReader(string(jsonData)))
 	if err != nil {
 		return "", fmt.Errorf("failed to create tiktok init request: %w", err)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Content-Type", "application/json; charset=UTF-8")
 
 	resp, err := client.Do(httpReq)
 	if err != nil {
@@ -87,17 +133,17 @@ func (t *TikTokPlatform) Share(ctx context.Context, client *http.Client, req *ty
 		// Parse error response
 		var errorResponse struct {
 			Error struct {
-				Code    int    `json:"code"`
+				Code    string `json:"code"`
 				Message string `json:"message"`
 				LogID   string `json:"log_id"`
 			} `json:"error"`
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return "", fmt.Errorf("tiktok init api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("tiktok init api error (%s): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return "", fmt.Errorf("tiktok init api error: status=%d body=%s", resp.StatusCode, string(body))
+		return "", platformError(resp.StatusCode, body, fmt.Errorf("tiktok init api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse init response
@@ -112,33 +158,288 @@ func (t *TikTokPlatform) Share(ctx context.Context, client *http.Client, req *ty
 		return "", fmt.Errorf("failed to parse tiktok init response: %w", err)
 	}
 
-	// Note: In a real implementation, you would:
-	// 1. Download the video from req.MediaURL
-	// 2. Upload it to initResponse.Data.UploadURL
-	// 3. Call the publish endpoint with initResponse.Data.PublishID
+	if initResponse.Data.UploadURL == "" || initResponse.Data.PublishID == "" {
+		return "", fmt.Errorf("tiktok init response missing upload_url or publish_id")
+	}
+
+	// Step 2: Upload video data in chunks
+	if err := t.uploadChunks(ctx, client, initResponse.Data.UploadURL, videoData, chunkSize, totalChunkCount); err != nil {
+		return "", fmt.Errorf("failed to upload video: %w", err)
+	}
+	types.AddUploadedBytes(ctx, videoSize)
+
+	// Step 3: Poll publish status until published or failed
+	postID, err := t.waitForPublish(ctx, client, initResponse.Data.PublishID)
+	if err != nil {
+		return "", err
+	}
+
+	return postID, nil
+}
+
+// calculateChunks computes chunk size and chunk count for a FILE_UPLOAD.
+// Videos smaller than the minimum chunk size are uploaded as a single chunk;
+// otherwise any remainder is merged into the final chunk as TikTok requires.
+func (t *TikTokPlatform) calculateChunks(videoSize int64) (int64, int64) {
+	if videoSize < tiktokMinChunkSize {
+		return videoSize, 1
+	}
+
+	chunkSize := int64(tiktokDefaultChunkSize)
+	if videoSize < chunkSize {
+		chunkSize = videoSize
+	}
+
+	return chunkSize, videoSize / chunkSize
+}
+
+// uploadChunks PUTs the video bytes to the upload URL returned by the init call
+func (t *TikTokPlatform) uploadChunks(ctx context.Context, client *http.Client, uploadURL string, videoData []byte, chunkSize, totalChunkCount int64) error {
+	videoSize := int64(len(videoData))
+
+	for i := int64(0); i < totalChunkCount; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		// The last chunk carries any remaining bytes
+		if i == totalChunkCount-1 {
+			end = videoSize
+		}
+
+		chunkReq, err := http.NewRequestWithContext(ctx, "PUT", uploadURL, bytes.NewReader(videoData[start:end]))
+		if err != nil {
+			return fmt.Errorf("failed to create tiktok upload request: %w", err)
+		}
+
+		chunkReq.ContentLength = end - start
+		chunkReq.Header.Set("Content-Type", "video/mp4")
+		chunkReq.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, videoSize))
+
+		chunkResp, err := client.Do(chunkReq)
+		if err != nil {
+			return fmt.Errorf("failed to upload chunk %d/%d: %w", i+1, totalChunkCount, err)
+		}
+
+		chunkBody, _ := io.ReadAll(chunkResp.Body)
+		_ = chunkResp.Body.Close()
+
+		if chunkResp.StatusCode < 200 || chunkResp.StatusCode >= 300 {
+			return platformError(chunkResp.StatusCode, chunkBody, fmt.Errorf("tiktok upload chunk %d/%d failed: status=%d body=%s", i+1, totalChunkCount, chunkResp.StatusCode, string(chunkBody)))
+		}
+		types.ReportUploadProgress(ctx, end, videoSize)
+	}
+
+	return nil
+}
+
+// waitForPublish polls the publish status endpoint until the post is published or fails.
+// The upload has been accepted at this point, so running out of time isn't a failure: the publish ID
+// is returned while there is still time left to report it as processing, and GetPostStatus tracks it
+// from there. Failing instead would make callers retry and publish the video twice.
+func (t *TikTokPlatform) waitForPublish(ctx context.Context, client *http.Client, publishID string) (string, error) {
+	for {
+		status, err := t.fetchPublishStatus(ctx, client, publishID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return t.stillProcessing(ctx, publishID, "PROCESSING"), nil
+			}
+			return "", err
+		}
+
+		switch status.Status {
+		case "PUBLISH_COMPLETE":
+			if len(status.PublicPostIDs) > 0 {
+				return fmt.Sprintf("%d", status.PublicPostIDs[0]), nil
+			}
+			// Non-public posts don't expose a post ID, fall back to the publish ID
+			return publishID, nil
+		case "FAILED":
+			return "", fmt.Errorf("tiktok publish failed: %w", tiktokProcessingError(status.FailReason))
+		}
+
+		// Leave time to report the publish ID rather than polling up to the deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < 2*tiktokStatusPollDelay {
+			return t.stillProcessing(ctx, publishID, status.Status), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return t.stillProcessing(ctx, publishID, status.Status), nil
+		case <-time.After(tiktokStatusPollDelay):
+		}
+	}
+}
+
+// stillProcessing records that waiting for a publish stopped early and returns its publish ID
+func (t *TikTokPlatform) stillProcessing(ctx context.Context, publishID, status string) string {
+	types.AddShareWarning(ctx, "tiktok publish still %s when waiting stopped, check its status with media_id %s", status, publishID)
+	return publishID
+}
+
+// GetPostStatus maps the publish status of an upload to a post status, explaining failures with
+// TikTok's fail reason. TikTok only reports status by publish ID. Share returns the numeric public
+// post ID instead once it has seen the publish complete, so such an ID is published already.
+func (t *TikTokPlatform) GetPostStatus(ctx context.Context, client *http.Client, mediaID string) (types.PostStatusDetail, error) {
+	if mediaID == "" {
+		return types.PostStatusDetail{}, fmt.Errorf("media_id required")
+	}
+	if isNumeric(mediaID) {
+		return types.PostStatusDetail{Status: types.PostStatusPublished}, nil
+	}
+
+	status, err := t.fetchPublishStatus(ctx, client, mediaID)
+	if err != nil {
+		return types.PostStatusDetail{}, err
+	}
+
+	switch status.Status {
+	case "PUBLISH_COMPLETE":
+		return types.PostStatusDetail{Status: types.PostStatusPublished}, nil
+	case "FAILED":
+		return types.PostStatusDetail{
+			Status:          types.PostStatusFailed,
+			ProcessingError: tiktokProcessingError(status.FailReason),
+		}, nil
+	default:
+		return types.PostStatusDetail{Status: types.PostStatusProcessing}, nil
+	}
+}
+
+// tiktokFailReasons explains the fail reasons TikTok reports for a publish that didn't go live
+var tiktokFailReasons = map[string]string{
+	"file_format_check_failed":           "tiktok doesn't support the video's format",
+	"duration_check_failed":              "the video is too long or too short for tiktok",
+	"frame_rate_check_failed":            "tiktok doesn't support the video's frame rate",
+	"picture_size_check_failed":          "tiktok doesn't support the video's resolution",
+	"video_pull_failed":                  "tiktok couldn't download the video",
+	"publish_cancelled":                  "the publish was cancelled",
+	"auth_removed":                       "the user revoked the app's access during the publish",
+	"spam_risk_too_many_posts":           "the account has posted too many times today",
+	"spam_risk_user_banned_from_posting": "the account is banned from posting",
+	"spam_risk_text":                     "tiktok flagged the caption as spam",
+	"spam_risk":                          "tiktok flagged the post as spam",
+	"internal":                           "tiktok failed to process the video",
+}
+
+// tiktokProcessingError explains a TikTok fail reason
+func tiktokProcessingError(reason string) *types.ProcessingError {
+	if reason == "" {
+		reason = "internal"
+	}
+
+	message, ok := tiktokFailReasons[reason]
+	if !ok {
+		message = "tiktok didn't publish the video: " + reason
+	}
+	return &types.ProcessingError{Reason: reason, Message: message}
+}
+
+// tiktokPublishStatus represents the publish status returned by TikTok
+type tiktokPublishStatus struct {
+	Status        string  `json:"status"`
+	FailReason    string  `json:"fail_reason"`
+	PublicPostIDs []int64 `json:"publicaly_available_post_id"`
+}
+
+// fetchPublishStatus queries the publish status of an upload
+func (t *TikTokPlatform) fetchPublishStatus(ctx context.Context, client *http.Client, publishID string) (*tiktokPublishStatus, error) {
+	jsonData, err := json.Marshal(map[string]any{"publish_id": publishID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tiktok status request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://open.tiktokapis.com/v2/post/publish/status/fetch/", strings.NewReader(string(jsonData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tiktok status request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send tiktok status request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tiktok status response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, platformError(resp.StatusCode, body, fmt.Errorf("tiktok status api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var statusResponse struct {
+		Data tiktokPublishStatus `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &statusResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse tiktok status response: %w", err)
+	}
+
+	return &statusResponse.Data, nil
+}
+
+// getPrivacyLevel maps the request privacy to a TikTok privacy level
+func (t *TikTokPlatform) getPrivacyLevel(req *types.ShareRequest) string {
+	switch req.Privacy {
+	case "public":
+		return "PUBLIC_TO_EVERYONE"
+	case "private":
+		return "SELF_ONLY"
+	case "friends":
+		return "MUTUAL_FOLLOW_FRIEND"
+	case "followers":
+		return "FOLLOWER_OF_CREATOR"
+	default:
+		return "MUTUAL_FOLLOW_FRIEND" // Default privacy level
+	}
+}
 
-	// For now, we'll return the publish_id as a placeholder
-	// In production, you need to complete the upload and publish process
-	return initResponse.Data.PublishID, nil
+// DeletePost is not supported, the TikTok Content Posting API can't delete published videos
+func (t *TikTokPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	return fmt.Errorf("tiktok api does not support deleting posts, delete it in the TikTok app: %w", types.ErrOperationNotSupported)
 }
 
 // GetStats retrieves statistics from TikTok
 func (t *TikTokPlatform) GetStats(ctx context.Context, client *http.Client, mediaID string) (types.StatsData, error) {
+	video, err := t.queryVideo(ctx, client, mediaID, "stats")
+	if err != nil {
+		return types.StatsData{}, err
+	}
+
+	return tiktokStats(video), nil
+}
+
+// GetPost retrieves a single TikTok video by ID
+func (t *TikTokPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	video, err := t.queryVideo(ctx, client, mediaID, "post")
+	if err != nil {
+		return types.Post{}, err
+	}
+
+	return tiktokPost(video), nil
+}
+
+// queryVideo looks up a video, operation names the request in errors
+func (t *TikTokPlatform) queryVideo(ctx context.Context, client *http.Client, mediaID, operation string) (tiktokVideo, error) {
 	if mediaID == "" {
-		return types.StatsData{}, fmt.Errorf("media_id required")
+		return tiktokVideo{}, fmt.Errorf("media_id required")
 	}
 
-	// Get TikTok video statistics from TikTok for Developers API
+	// Get TikTok video from TikTok for Developers API
 	// Note: This requires proper authentication and may have limited data availability
-	url := fmt.Sprintf("https://open-api.tiktok.com/video/query/?video_id=%s&fields=id,title,cover_image_url,embed_url,like_count,comment_count,share_count,view_count", mediaID)
+	url := fmt.Sprintf("https://open-api.tiktok.com/video/query/?video_id=%s&fields=%s", mediaID, tiktokVideoFields)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return types.StatsData{}, fmt.Errorf("failed to create tiktok stats request: %w", err)
+		return tiktokVideo{}, fmt.Errorf("failed to create tiktok %s request: %w", operation, err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
-		return types.StatsData{}, fmt.Errorf("failed to get tiktok stats: %w", err)
+		return tiktokVideo{}, fmt.Errorf("failed to get tiktok %s: %w", operation, err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -146,7 +447,7 @@ func (t *TikTokPlatform) GetStats(ctx context.Context, client *http.Client, medi
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return types.StatsData{}, fmt.Errorf("failed to read tiktok stats response: %w", err)
+		return tiktokVideo{}, fmt.Errorf("failed to read tiktok %s response: %w", operation, err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -160,43 +461,28 @@ func (t *TikTokPlatform) GetStats(ctx context.Context, client *http.Client, medi
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.StatsData{}, fmt.Errorf("tiktok stats api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return tiktokVideo{}, platformError(resp.StatusCode, body, fmt.Errorf("tiktok %s api error (%d): %s", operation, errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return types.StatsData{}, fmt.Errorf("tiktok stats api error: status=%d body=%s", resp.StatusCode, string(body))
+		return tiktokVideo{}, platformError(resp.StatusCode, body, fmt.Errorf("tiktok %s api error: status=%d body=%s", operation, resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
-	var statsResponse struct {
+	var videoResponse struct {
 		Data struct {
-			Videos []struct {
-				ID           string `json:"id"`
-				Title        string `json:"title"`
-				LikeCount    int    `json:"like_count"`
-				CommentCount int    `json:"comment_count"`
-				ShareCount   int    `json:"share_count"`
-				ViewCount    int    `json:"view_count"`
-			} `json:"videos"`
+			Videos []tiktokVideo `json:"videos"`
 		} `json:"data"`
 	}
 
-	if err := json.Unmarshal(body, &statsResponse); err != nil {
-		return types.StatsData{}, fmt.Errorf("failed to parse tiktok stats response: %w", err)
+	if err := json.Unmarshal(body, &videoResponse); err != nil {
+		return tiktokVideo{}, fmt.Errorf("failed to parse tiktok %s response: %w", operation, err)
 	}
 
-	if len(statsResponse.Data.Videos) == 0 {
-		return types.StatsData{}, fmt.Errorf("video not found")
+	if len(videoResponse.Data.Videos) == 0 {
+		return tiktokVideo{}, fmt.Errorf("video not found")
 	}
 
-	video := statsResponse.Data.Videos[0]
-
-	return types.StatsData{
-		Views:    video.ViewCount,
-		Likes:    video.LikeCount,
-		Replies:  video.CommentCount,
-		Shares:   video.ShareCount,
-		Retweets: 0, // TikTok doesn't have retweets
-	}, nil
+	return videoResponse.Data.Videos[0], nil
 }
 
 // GetUserInfo retrieves user information from TikTok platform
@@ -208,7 +494,7 @@ func (t *TikTokPlatform) GetUserInfo(ctx context.Context, client *http.Client) (
 		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
 	}
@@ -232,10 +518,10 @@ func (t *TikTokPlatform) GetUserInfo(ctx context.Context, client *http.Client) (
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.UserInfo{}, fmt.Errorf("tiktok user info api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message)
+			return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("tiktok user info api error (%d): %s", errorResponse.Error.Code, errorResponse.Error.Message))
 		}
 
-		return types.UserInfo{}, fmt.Errorf("tiktok user info api error: status=%d body=%s", resp.StatusCode, string(body))
+		return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("tiktok user info api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
@@ -286,7 +572,7 @@ func (t *TikTokPlatform) GetRecentPosts(ctx context.Context, client *http.Client
 	}
 
 	// Build query parameters
-	params := fmt.Sprintf("max_count=%d&fields=id,create_time,share_url,title,cover_image_url,embed_url,like_count,comment_count,share_count", limit)
+	params := fmt.Sprintf("max_count=%d&fields=%s", limit, tiktokVideoFields)
 
 	// Add time range filters if provided
 	if startTime > 0 {
@@ -302,7 +588,7 @@ func (t *TikTokPlatform) GetRecentPosts(ctx context.Context, client *http.Client
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -316,23 +602,13 @@ func (t *TikTokPlatform) GetRecentPosts(ctx context.Context, client *http.Client
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("tiktok api error: status=%d body=%s", resp.StatusCode, string(body))
+		return nil, platformError(resp.StatusCode, body, fmt.Errorf("tiktok api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
 	var videosResponse struct {
 		Data struct {
-			Videos []struct {
-				ID            string `json:"id"`
-				CreateTime    int64  `json:"create_time"`
-				ShareURL      string `json:"share_url"`
-				Title         string `json:"title"`
-				CoverImageURL string `json:"cover_image_url"`
-				EmbedURL      string `json:"embed_url"`
-				LikeCount     int    `json:"like_count"`
-				CommentCount  int    `json:"comment_count"`
-				ShareCount    int    `json:"share_count"`
-			} `json:"videos"`
+			Videos []tiktokVideo `json:"videos"`
 		} `json:"data"`
 	}
 
@@ -343,28 +619,54 @@ func (t *TikTokPlatform) GetRecentPosts(ctx context.Context, client *http.Client
 	// Convert to Post structs
 	var posts []types.Post
 	for _, video := range videosResponse.Data.Videos {
-		post := types.Post{
-			ID:        video.ID,
-			Content:   video.Title,
-			Title:     video.Title,
-			CreatedAt: video.CreateTime,
-			Stats: types.StatsData{
-				Likes:    video.LikeCount,
-				Replies:  video.CommentCount,
-				Shares:   video.ShareCount,
-				Retweets: 0, // TikTok doesn't have retweets
-			},
-			URL:       video.ShareURL,
-			MediaType: "video",
-			MediaURL:  video.CoverImageURL,
-		}
-
-		posts = append(posts, post)
+		posts = append(posts, tiktokPost(video))
 	}
 
 	return posts, nil
 }
 
+// tiktokVideoFields are the fields requested for videos
+const tiktokVideoFields = "id,create_time,share_url,title,cover_image_url,embed_url,like_count,comment_count,share_count,view_count"
+
+// tiktokVideo is a video as returned by the TikTok API
+type tiktokVideo struct {
+	ID            string `json:"id"`
+	CreateTime    int64  `json:"create_time"`
+	ShareURL      string `json:"share_url"`
+	Title         string `json:"title"`
+	CoverImageURL string `json:"cover_image_url"`
+	EmbedURL      string `json:"embed_url"`
+	LikeCount     int    `json:"like_count"`
+	CommentCount  int    `json:"comment_count"`
+	ShareCount    int    `json:"share_count"`
+	ViewCount     int    `json:"view_count"`
+}
+
+// tiktokStats converts a video's counters to stats
+func tiktokStats(video tiktokVideo) types.StatsData {
+	return fetchedStats(types.StatsData{
+		Views:    video.ViewCount,
+		Likes:    video.LikeCount,
+		Replies:  video.CommentCount,
+		Shares:   video.ShareCount,
+		Retweets: 0, // TikTok doesn't have retweets
+	})
+}
+
+// tiktokPost converts a video to a post
+func tiktokPost(video tiktokVideo) types.Post {
+	return types.Post{
+		ID:        video.ID,
+		Content:   video.Title,
+		Title:     video.Title,
+		CreatedAt: video.CreateTime,
+		Stats:     tiktokStats(video),
+		URL:       video.ShareURL,
+		MediaType: "video",
+		MediaURL:  video.CoverImageURL,
+	}
+}
+
 // HandleOAuthCallback handles OAuth callback for TikTok platform
 func (t *TikTokPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
 	// TikTok平台特定的OAuth回调处理逻辑
@@ -0,0 +1,86 @@
+package platforms
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// clientAccessToken returns the access token an authenticated client sends, looking through
+// transports that wrap the oauth2 one. It returns false for clients without a token, such as sandbox ones.
+func clientAccessToken(client *http.Client) (string, bool) {
+	transport := client.Transport
+	for transport != nil {
+		switch t := transport.(type) {
+		case *oauth2.Transport:
+			token, err := t.Source.Token()
+			if err != nil || token.AccessToken == "" {
+				return "", false
+			}
+			return token.AccessToken, true
+		case interface{ Unwrap() http.RoundTripper }:
+			transport = t.Unwrap()
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// rebasableTransport is a transport wrapping another one that can be rebuilt around a different one
+type rebasableTransport interface {
+	Unwrap() http.RoundTripper
+	WithBase(base http.RoundTripper) http.RoundTripper
+}
+
+// clientWithAccessToken returns a client sending accessToken in place of the client's own token.
+// Only the oauth2 transport is swapped: the circuit breaker and rate limit tracking beneath it and
+// host overrides above it are kept, so calls with the new token are accounted like the client's own.
+// Clients without a token, such as sandbox ones, get the token added on top of their transport.
+func clientWithAccessToken(client *http.Client, accessToken string) *http.Client {
+	source := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+
+	transport, ok := withTokenSource(client.Transport, source)
+	if !ok {
+		// The client's own token must not be sent, so start beneath its oauth2 transport if it has one
+		transport = &oauth2.Transport{Source: source, Base: beneathToken(client.Transport)}
+	}
+
+	return &http.Client{
+		Timeout:   client.Timeout,
+		Transport: transport,
+	}
+}
+
+// withTokenSource rebuilds a transport chain with the oauth2 transport in it using source instead,
+// and returns false if there is no oauth2 transport or a wrapper above it can't be rebuilt
+func withTokenSource(transport http.RoundTripper, source oauth2.TokenSource) (http.RoundTripper, bool) {
+	switch t := transport.(type) {
+	case *oauth2.Transport:
+		return &oauth2.Transport{Source: source, Base: t.Base}, true
+	case rebasableTransport:
+		base, ok := withTokenSource(t.Unwrap(), source)
+		if !ok {
+			return nil, false
+		}
+		return t.WithBase(base), true
+	default:
+		return nil, false
+	}
+}
+
+// beneathToken returns the transport beneath the oauth2 one in a chain, or the chain itself if it
+// has no oauth2 transport
+func beneathToken(transport http.RoundTripper) http.RoundTripper {
+	for current := transport; current != nil; {
+		switch t := current.(type) {
+		case *oauth2.Transport:
+			return t.Base
+		case interface{ Unwrap() http.RoundTripper }:
+			current = t.Unwrap()
+		default:
+			return transport
+		}
+	}
+	return transport
+}
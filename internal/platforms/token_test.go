@@ -0,0 +1,99 @@
+package platforms
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// recordingTransport stands in for the circuit breaker and rate limit transports beneath the oauth2
+// one, recording the Authorization header of the requests reaching it
+type recordingTransport struct {
+	authorization []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.authorization = append(t.authorization, req.Header.Get("Authorization"))
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
+
+// wrappingTransport stands in for a host override above the oauth2 transport, counting the requests
+// passing through it
+type wrappingTransport struct {
+	base  http.RoundTripper
+	calls *int
+}
+
+func (t *wrappingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.calls++
+	return t.base.RoundTrip(req)
+}
+
+func (t *wrappingTransport) Unwrap() http.RoundTripper {
+	return t.base
+}
+
+// rebasableWrappingTransport is a wrappingTransport that can be rebuilt around another transport
+type rebasableWrappingTransport struct {
+	wrappingTransport
+}
+
+func (t *rebasableWrappingTransport) WithBase(base http.RoundTripper) http.RoundTripper {
+	return &rebasableWrappingTransport{wrappingTransport{base: base, calls: t.calls}}
+}
+
+func userClient(base http.RoundTripper) *oauth2.Transport {
+	return &oauth2.Transport{
+		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "user-token"}),
+		Base:   base,
+	}
+}
+
+func doGet(t *testing.T, client *http.Client) {
+	t.Helper()
+	resp, err := client.Get("https://graph.facebook.com/me")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestClientWithAccessTokenKeepsWrappers(t *testing.T) {
+	base := &recordingTransport{}
+	calls := 0
+	client := &http.Client{Transport: &rebasableWrappingTransport{wrappingTransport{base: userClient(base), calls: &calls}}}
+
+	doGet(t, clientWithAccessToken(client, "page-token"))
+
+	if calls != 1 {
+		t.Errorf("wrapper above the oauth2 transport saw %d requests, want 1", calls)
+	}
+	if len(base.authorization) != 1 || base.authorization[0] != "Bearer page-token" {
+		t.Errorf("transport beneath the oauth2 one saw Authorization %q, want the page token", base.authorization)
+	}
+}
+
+func TestClientWithAccessTokenDropsUserToken(t *testing.T) {
+	base := &recordingTransport{}
+	calls := 0
+	client := &http.Client{Transport: &wrappingTransport{base: userClient(base), calls: &calls}}
+
+	doGet(t, clientWithAccessToken(client, "page-token"))
+
+	if len(base.authorization) != 1 || base.authorization[0] != "Bearer page-token" {
+		t.Errorf("transport beneath the oauth2 one saw Authorization %q, want the page token", base.authorization)
+	}
+}
+
+func TestClientWithAccessTokenWithoutToken(t *testing.T) {
+	base := &recordingTransport{}
+
+	doGet(t, clientWithAccessToken(&http.Client{Transport: base}, "page-token"))
+
+	if len(base.authorization) != 1 || base.authorization[0] != "Bearer page-token" {
+		t.Errorf("client transport saw Authorization %q, want the page token", base.authorization)
+	}
+}
@@ -0,0 +1,86 @@
+package platforms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// providerHosts lists the API and token hosts each provider talks to
+var providerHosts = map[string][]string{
+	"youtube":   {"https://www.googleapis.com", "https://oauth2.googleapis.com"},
+	"x":         {"https://api.x.com"},
+	"facebook":  {"https://graph.facebook.com"},
+	"tiktok":    {"https://open.tiktokapis.com"},
+	"instagram": {"https://graph.facebook.com", "https://graph.instagram.com", "https://api.instagram.com"},
+	"pinterest": {"https://api.pinterest.com"},
+	"reddit":    {"https://oauth.reddit.com"},
+	"discord":   {"https://discord.com"},
+	"threads":   {"https://graph.threads.net"},
+}
+
+// Warmup opens connections to the API hosts of the given providers so the first real
+// request doesn't pay for DNS lookup and TLS handshake. The connections are kept in the
+// idle pool of http.DefaultTransport, which the OAuth clients use as their base transport.
+// It returns the warmup error of each host that could not be reached.
+func Warmup(ctx context.Context, providers []string) map[string]error {
+	hosts := make(map[string]bool)
+	for _, provider := range providers {
+		for _, host := range providerHosts[provider] {
+			hosts[host] = true
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failures := make(map[string]error)
+
+	for host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			if err := warmupHost(ctx, host); err != nil {
+				mu.Lock()
+				failures[host] = err
+				mu.Unlock()
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	return failures
+}
+
+// CheckReachable connects to each API and token host of a provider, returning the failure of each
+// host that could not be reached. Providers without fixed hosts, such as Mastodon, have nothing to check.
+func CheckReachable(ctx context.Context, provider string) map[string]error {
+	failures := make(map[string]error)
+	for _, host := range providerHosts[provider] {
+		if err := warmupHost(ctx, host); err != nil {
+			failures[host] = err
+		}
+	}
+	return failures
+}
+
+// HasKnownHosts reports whether CheckReachable has hosts to check for a provider
+func HasKnownHosts(provider string) bool {
+	return len(providerHosts[provider]) > 0
+}
+
+// warmupHost sends a HEAD request to the host, any HTTP status means the connection is established
+func warmupHost(ctx context.Context, host string) error {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", host, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create warmup request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	return nil
+}
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"social/internal/types"
+	"social/pkg/httpx"
 )
 
 // XPlatform implements the X (Twitter) platform
@@ -25,17 +28,224 @@ func (x *XPlatform) GetName() string {
 	return "x"
 }
 
+// Capabilities reports what X supports, tweets can be text only
+func (x *XPlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+		SupportsVideo:       true,
+	}
+}
+
+// xMaxTweetLength is the maximum weighted length of a single tweet, see xWeightedLength
+const xMaxTweetLength = 280
+
+// xURLLength is the weight X gives every URL, whatever its length, after wrapping it in t.co
+const xURLLength = 23
+
+// xLightRanges are the code points X counts as 1, everything else (CJK, Hangul, emoji...) counts as 2
+var xLightRanges = []struct{ lo, hi rune }{
+	{0x0000, 0x10FF},
+	{0x2000, 0x200D},
+	{0x2010, 0x201F},
+	{0x2032, 0x2037},
+}
+
+// Thread numbering placeholders and the default suffix appended to each tweet
+const (
+	xThreadNumberPlaceholder = "{n}"
+	xThreadTotalPlaceholder  = "{total}"
+	xDefaultThreadNumber     = " ({n}/{total})"
+)
+
+// Reply settings of a tweet, everyone is X's default and isn't sent
+const (
+	xReplySettingsEveryone       = "everyone"
+	xReplySettingsMentionedUsers = "mentionedUsers"
+	xReplySettingsFollowing      = "following"
+)
+
 // Share shares content to X (Twitter)
+// Content longer than a single tweet, or a request with Thread entries, is posted as a thread
+// and the first tweet's ID is returned. Media from MediaURL is attached to the first tweet with
+// MediaAltText as its alt text; if its upload fails the text is still posted and the failure is
+// reported as a share warning.
 func (x *XPlatform) Share(ctx context.Context, client *http.Client, req *types.ShareRequest) (string, error) {
-	if strings.TrimSpace(req.Content) == "" {
-		return "", fmt.Errorf("content required for x/tweet")
+	if err := x.Validate(req); err != nil {
+		return "", err
+	}
+
+	tweets := x.buildThread(req)
+
+	var mediaIDs []string
+	if req.MediaURL != "" {
+		mediaID, err := x.uploadMedia(ctx, client, req.MediaURL)
+		switch {
+		case err == nil:
+			mediaIDs = []string{mediaID}
+			if req.MediaAltText != "" {
+				if err := x.setMediaAltText(ctx, client, mediaID, req.MediaAltText); err != nil {
+					types.AddShareWarning(ctx, "failed to set media alt text, posted media without it: %v", err)
+				}
+			}
+		case ctx.Err() != nil || len(tweets) == 0:
+			// Nothing to fall back to
+			return "", fmt.Errorf("media upload failed: %w", err)
+		default:
+			types.AddShareWarning(ctx, "media upload failed, posted text only: %v", err)
+		}
+	}
+
+	// A media-only tweet has no text
+	if len(tweets) == 0 {
+		tweets = []string{""}
+	}
+
+	var firstID, previousID string
+	for i, text := range tweets {
+		var attached []string
+		if i == 0 {
+			attached = mediaIDs
+		}
+
+		tweetID, err := x.postTweet(ctx, client, text, previousID, attached, req.ReplySettings)
+		if err != nil {
+			if i == 0 {
+				return "", err
+			}
+			return "", fmt.Errorf("thread failed after %d of %d tweets posted (first tweet id %s): %w", i, len(tweets), firstID, err)
+		}
+
+		if i == 0 {
+			firstID = tweetID
+		}
+		if tweetID == "" && i < len(tweets)-1 {
+			return "", fmt.Errorf("thread failed after %d of %d tweets posted (first tweet id %s): no tweet id returned to reply to", i+1, len(tweets), firstID)
+		}
+		previousID = tweetID
+	}
+
+	return firstID, nil
+}
+
+// Validate checks that a share request can be posted to X
+func (x *XPlatform) Validate(req *types.ShareRequest) error {
+	if req.ThreadNumberFormat != "" && (!strings.Contains(req.ThreadNumberFormat, xThreadNumberPlaceholder) ||
+		!strings.Contains(req.ThreadNumberFormat, xThreadTotalPlaceholder)) {
+		return types.NewValidationError("thread_number_format must contain %s and %s", xThreadNumberPlaceholder, xThreadTotalPlaceholder)
+	}
+
+	switch req.ReplySettings {
+	case "", xReplySettingsEveryone, xReplySettingsMentionedUsers, xReplySettingsFollowing:
+	default:
+		return types.NewValidationError("reply_settings must be one of %s, %s or %s", xReplySettingsEveryone, xReplySettingsMentionedUsers, xReplySettingsFollowing)
+	}
+
+	if len(x.splitTweets(req, xMaxTweetLength)) == 0 && req.MediaURL == "" {
+		return types.NewValidationError("content required for x/tweet")
+	}
+
+	for i, entry := range req.Thread {
+		if length := xWeightedLength(strings.TrimSpace(entry)); length > xMaxTweetLength {
+			return types.NewValidationError("thread[%d] is %d characters as counted by x, exceeding %d", i, length, xMaxTweetLength)
+		}
+	}
+
+	return nil
+}
+
+// buildThread returns the list of tweets to post for a share request.
+// Content is split on sentence boundaries when it exceeds a single tweet and Thread entries follow it.
+// With NumberThread set, each tweet of a multi-tweet thread gets a numbering suffix that counts
+// towards its length.
+func (x *XPlatform) buildThread(req *types.ShareRequest) []string {
+	tweets := x.splitTweets(req, xMaxTweetLength)
+	if !req.NumberThread || len(tweets) < 2 {
+		return tweets
+	}
+
+	format := req.ThreadNumberFormat
+	if format == "" {
+		format = xDefaultThreadNumber
+	}
+
+	// The suffix length depends on the number of digits in the total, and reserving room for it
+	// can push the total up, so re-split until the digit count is stable
+	digits := len(strconv.Itoa(len(tweets)))
+	for {
+		widest := strings.Repeat("9", digits)
+		suffixLength := xWeightedLength(formatThreadNumber(format, widest, widest))
+		tweets = x.splitTweets(req, xMaxTweetLength-suffixLength)
+
+		if totalDigits := len(strconv.Itoa(len(tweets))); totalDigits > digits {
+			digits = totalDigits
+			continue
+		}
+		break
+	}
+
+	total := strconv.Itoa(len(tweets))
+	for i := range tweets {
+		tweets[i] += formatThreadNumber(format, strconv.Itoa(i+1), total)
+	}
+
+	return tweets
+}
+
+// splitTweets splits the request's content and thread entries into tweets of at most limit weighted characters
+func (x *XPlatform) splitTweets(req *types.ShareRequest, limit int) []string {
+	var tweets []string
+
+	if content := strings.TrimSpace(req.Content); content != "" {
+		tweets = append(tweets, splitThread(content, limit)...)
+	}
+
+	for _, entry := range req.Thread {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			tweets = append(tweets, splitThread(entry, limit)...)
+		}
+	}
+
+	return tweets
+}
+
+// formatThreadNumber fills the numbering placeholders of a thread number format
+func formatThreadNumber(format, n, total string) string {
+	return strings.NewReplacer(xThreadNumberPlaceholder, n, xThreadTotalPlaceholder, total).Replace(format)
+}
+
+// postTweet posts a single tweet, optionally as a reply to another tweet and with uploaded media
+// attached, and returns its ID. replySettings limits who can reply, empty or everyone allows anyone
+func (x *XPlatform) postTweet(ctx context.Context, client *http.Client, text, inReplyToID string, mediaIDs []string, replySettings string) (string, error) {
+	type tweetReply struct {
+		InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+	}
+
+	type tweetMedia struct {
+		MediaIDs []string `json:"media_ids"`
 	}
 
 	type tweetReq struct {
-		Text string `json:"text"`
+		Text          string      `json:"text,omitempty"`
+		Reply         *tweetReply `json:"reply,omitempty"`
+		Media         *tweetMedia `json:"media,omitempty"`
+		ReplySettings string      `json:"reply_settings,omitempty"`
+	}
+
+	payload := tweetReq{Text: text}
+	if replySettings != xReplySettingsEveryone {
+		payload.ReplySettings = replySettings
+	}
+	if inReplyToID != "" {
+		payload.Reply = &tweetReply{InReplyToTweetID: inReplyToID}
+	}
+	if len(mediaIDs) > 0 {
+		payload.Media = &tweetMedia{MediaIDs: mediaIDs}
 	}
 
-	payload := tweetReq{Text: req.Content}
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal tweet request: %w", err)
@@ -76,6 +286,10 @@ func (x *XPlatform) Share(ctx context.Context, client *http.Client, req *types.S
 		return "", nil
 	}
 
+	if err := xAccessLevelError(resp.StatusCode, body, xOperationPost); err != nil {
+		return "", err
+	}
+
 	// Parse error response for better error handling
 	var errorResponse struct {
 		Detail string `json:"detail"`
@@ -89,19 +303,202 @@ func (x *XPlatform) Share(ctx context.Context, client *http.Client, req *types.S
 		switch errorResponse.Status {
 		case 403:
 			if strings.Contains(errorResponse.Detail, "suspended") {
-				return "", fmt.Errorf("account suspended: %s", errorResponse.Detail)
+				return "", platformError(resp.StatusCode, body, fmt.Errorf("account suspended: %s", errorResponse.Detail))
 			}
-			return "", fmt.Errorf("access forbidden: %s", errorResponse.Detail)
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("access forbidden: %s", errorResponse.Detail))
 		case 401:
-			return "", fmt.Errorf("authentication failed: %s", errorResponse.Detail)
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("authentication failed: %s", errorResponse.Detail))
 		case 429:
-			return "", fmt.Errorf("rate limit exceeded: %s", errorResponse.Detail)
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("rate limit exceeded: %s", errorResponse.Detail))
 		default:
-			return "", fmt.Errorf("x api error (%d): %s", errorResponse.Status, errorResponse.Detail)
+			return "", platformError(resp.StatusCode, body, fmt.Errorf("x api error (%d): %s", errorResponse.Status, errorResponse.Detail))
 		}
 	}
 
-	return "", fmt.Errorf("x api error: status=%d body=%s", resp.StatusCode, string(body))
+	return "", platformError(resp.StatusCode, body, fmt.Errorf("x api error: status=%d body=%s", resp.StatusCode, string(body)))
+}
+
+// splitThread splits text into chunks of at most limit weighted characters.
+// It prefers sentence boundaries, then word boundaries, and only cuts inside a word as a last resort.
+func splitThread(text string, limit int) []string {
+	if xWeightedLength(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	current := ""
+
+	flush := func() {
+		if trimmed := strings.TrimSpace(current); trimmed != "" {
+			chunks = append(chunks, trimmed)
+		}
+		current = ""
+	}
+
+	// appendPiece adds a piece to the current chunk, starting a new chunk when it would overflow
+	appendPiece := func(piece string) {
+		if current != "" && xWeightedLength(current+piece) > limit {
+			flush()
+			piece = strings.TrimLeft(piece, " ")
+		}
+		current += piece
+	}
+
+	for _, sentence := range splitSentences(text) {
+		if xWeightedLength(strings.TrimSpace(sentence)) <= limit {
+			appendPiece(sentence)
+			continue
+		}
+
+		// Sentence is too long on its own, fall back to word boundaries
+		for _, word := range strings.SplitAfter(sentence, " ") {
+			for xWeightedLength(word) > limit {
+				// Single word longer than a tweet, hard cut it
+				head, tail := cutWeighted(word, limit)
+				flush()
+				chunks = append(chunks, head)
+				word = tail
+			}
+			appendPiece(word)
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// xWeightedLength returns the length of text as X counts it: every URL counts as xURLLength,
+// code points in xLightRanges count as 1 and all others as 2.
+// Emoji ZWJ sequences are counted per code point, which overestimates them and only splits earlier.
+func xWeightedLength(text string) int {
+	length := 0
+	last := 0
+	for _, loc := range linkPattern.FindAllStringIndex(text, -1) {
+		// Trailing punctuation isn't part of the URL X wraps, as in PrimaryLink
+		end := loc[0] + len(strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?)]}'"))
+		length += xRunesWeight(text[last:loc[0]]) + xURLLength
+		last = end
+	}
+
+	return length + xRunesWeight(text[last:])
+}
+
+// xRunesWeight returns the weighted length of text without treating URLs specially
+func xRunesWeight(text string) int {
+	length := 0
+	for _, r := range text {
+		length += xRuneWeight(r)
+	}
+	return length
+}
+
+// xRuneWeight returns how much a single code point counts towards the tweet length
+func xRuneWeight(r rune) int {
+	for _, lightRange := range xLightRanges {
+		if r >= lightRange.lo && r <= lightRange.hi {
+			return 1
+		}
+	}
+	return 2
+}
+
+// cutWeighted splits word after the longest prefix whose weight fits in limit, keeping at least one
+// code point in the prefix so the caller always makes progress
+func cutWeighted(word string, limit int) (string, string) {
+	weight := 0
+	for i, r := range word {
+		weight += xRuneWeight(r)
+		if weight > limit && i > 0 {
+			return word[:i], word[i:]
+		}
+	}
+	return word, ""
+}
+
+// splitSentences splits text after sentence-ending punctuation, keeping the trailing whitespace
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(text)
+
+	for i, r := range runes {
+		if !strings.ContainsRune(".!?。！？\n", r) {
+			continue
+		}
+		// Keep consecutive terminators (e.g. "?!" or "...") together
+		if i+1 < len(runes) && strings.ContainsRune(".!?。！？", runes[i+1]) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && unicode.IsSpace(runes[end]) {
+			end++
+		}
+		if end > start {
+			sentences = append(sentences, string(runes[start:end]))
+			start = end
+		}
+	}
+
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+
+	return sentences
+}
+
+// DeletePost deletes a tweet
+func (x *XPlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("https://api.x.com/2/tweets/%s", mediaID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send delete request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read delete response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if err := xAccessLevelError(resp.StatusCode, body, xOperationDelete); err != nil {
+			return err
+		}
+
+		var errorResponse struct {
+			Detail string `json:"detail"`
+			Title  string `json:"title"`
+		}
+		if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Detail != "" {
+			return platformError(resp.StatusCode, body, fmt.Errorf("x api error (%d): %s", resp.StatusCode, errorResponse.Detail))
+		}
+		return platformError(resp.StatusCode, body, fmt.Errorf("x api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var deleteResponse struct {
+		Data struct {
+			Deleted bool `json:"deleted"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &deleteResponse); err != nil {
+		return fmt.Errorf("failed to parse delete response: %w", err)
+	}
+
+	if !deleteResponse.Data.Deleted {
+		return fmt.Errorf("x api did not delete tweet %s", mediaID)
+	}
+
+	return nil
 }
 
 // GetStats retrieves statistics from X (Twitter)
@@ -116,7 +513,7 @@ func (x *XPlatform) GetStats(ctx context.Context, client *http.Client, mediaID s
 		return types.StatsData{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.StatsData{}, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -126,30 +523,65 @@ func (x *XPlatform) GetStats(ctx context.Context, client *http.Client, mediaID s
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return types.StatsData{}, fmt.Errorf("x stats api error: status=%d body=%s", resp.StatusCode, string(body))
+		if err := xAccessLevelError(resp.StatusCode, body, xOperationStats); err != nil {
+			return types.StatsData{}, err
+		}
+		return types.StatsData{}, platformError(resp.StatusCode, body, fmt.Errorf("x stats api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.StatsData{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var result struct {
-		Data struct {
+		Data *struct {
 			PublicMetrics struct {
-				RetweetCount int `json:"retweet_count"`
-				LikeCount    int `json:"like_count"`
-				ReplyCount   int `json:"reply_count"`
-				QuoteCount   int `json:"quote_count"`
+				RetweetCount    int  `json:"retweet_count"`
+				LikeCount       int  `json:"like_count"`
+				ReplyCount      int  `json:"reply_count"`
+				QuoteCount      int  `json:"quote_count"`
+				BookmarkCount   *int `json:"bookmark_count"`
+				ImpressionCount *int `json:"impression_count"` // missing for tweets predating impression counts
 			} `json:"public_metrics"`
 		} `json:"data"`
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return types.StatsData{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return types.StatsData{
-		Likes:    result.Data.PublicMetrics.LikeCount,
-		Retweets: result.Data.PublicMetrics.RetweetCount,
-		Replies:  result.Data.PublicMetrics.ReplyCount,
-		Shares:   result.Data.PublicMetrics.QuoteCount,
-	}, nil
+	// Deleted or unavailable tweets come back as 200 with only an errors array
+	if result.Data == nil {
+		if len(result.Errors) > 0 {
+			return types.StatsData{}, platformError(http.StatusNotFound, body, fmt.Errorf("x post not found: %s", result.Errors[0].Detail))
+		}
+		return types.StatsData{}, platformError(http.StatusNotFound, body, fmt.Errorf("x post not found: %s", mediaID))
+	}
+
+	metrics := result.Data.PublicMetrics
+	stats := types.StatsData{
+		Likes:    metrics.LikeCount,
+		Retweets: metrics.RetweetCount,
+		Replies:  metrics.ReplyCount,
+		Shares:   metrics.QuoteCount,
+		Extra:    map[string]int{extraQuoteCount: metrics.QuoteCount},
+	}
+	if metrics.BookmarkCount != nil {
+		stats.Extra[extraBookmarkCount] = *metrics.BookmarkCount
+	}
+	var missing []string
+	if metrics.ImpressionCount != nil {
+		stats.Views = *metrics.ImpressionCount
+		stats.Extra[extraImpressionCount] = *metrics.ImpressionCount
+	} else {
+		missing = append(missing, metricViews)
+	}
+
+	return fetchedStats(stats, missing...), nil
 }
 
 // CheckAccountStatus checks if the X account is in good standing
@@ -159,7 +591,7 @@ func (x *XPlatform) CheckAccountStatus(ctx context.Context, client *http.Client)
 		return fmt.Errorf("failed to create account status request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return fmt.Errorf("failed to check account status: %w", err)
 	}
@@ -177,6 +609,10 @@ func (x *XPlatform) CheckAccountStatus(ctx context.Context, client *http.Client)
 		return nil
 	}
 
+	if err := xAccessLevelError(resp.StatusCode, body, xOperationUserLookup); err != nil {
+		return err
+	}
+
 	// Parse error response
 	var errorResponse struct {
 		Detail string `json:"detail"`
@@ -189,17 +625,17 @@ func (x *XPlatform) CheckAccountStatus(ctx context.Context, client *http.Client)
 		switch errorResponse.Status {
 		case 403:
 			if strings.Contains(errorResponse.Detail, "suspended") {
-				return fmt.Errorf("account suspended: %s", errorResponse.Detail)
+				return platformError(resp.StatusCode, body, fmt.Errorf("account suspended: %s", errorResponse.Detail))
 			}
-			return fmt.Errorf("access forbidden: %s", errorResponse.Detail)
+			return platformError(resp.StatusCode, body, fmt.Errorf("access forbidden: %s", errorResponse.Detail))
 		case 401:
-			return fmt.Errorf("authentication failed: %s", errorResponse.Detail)
+			return platformError(resp.StatusCode, body, fmt.Errorf("authentication failed: %s", errorResponse.Detail))
 		default:
-			return fmt.Errorf("account status check failed (%d): %s", errorResponse.Status, errorResponse.Detail)
+			return platformError(resp.StatusCode, body, fmt.Errorf("account status check failed (%d): %s", errorResponse.Status, errorResponse.Detail))
 		}
 	}
 
-	return fmt.Errorf("account status check failed: status=%d body=%s", resp.StatusCode, string(body))
+	return platformError(resp.StatusCode, body, fmt.Errorf("account status check failed: status=%d body=%s", resp.StatusCode, string(body)))
 }
 
 // GetUserInfo retrieves user information from X platform
@@ -209,7 +645,7 @@ func (x *XPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types
 		return types.UserInfo{}, fmt.Errorf("failed to create user info request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return types.UserInfo{}, fmt.Errorf("failed to get user info: %w", err)
 	}
@@ -223,6 +659,10 @@ func (x *XPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if err := xAccessLevelError(resp.StatusCode, body, xOperationUserLookup); err != nil {
+			return types.UserInfo{}, err
+		}
+
 		// Parse error response
 		var errorResponse struct {
 			Detail string `json:"detail"`
@@ -232,10 +672,10 @@ func (x *XPlatform) GetUserInfo(ctx context.Context, client *http.Client) (types
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return types.UserInfo{}, fmt.Errorf("x user info api error (%d): %s", errorResponse.Status, errorResponse.Detail)
+			return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("x user info api error (%d): %s", errorResponse.Status, errorResponse.Detail))
 		}
 
-		return types.UserInfo{}, fmt.Errorf("x user info api error: status=%d body=%s", resp.StatusCode, string(body))
+		return types.UserInfo{}, platformError(resp.StatusCode, body, fmt.Errorf("x user info api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
@@ -290,7 +730,7 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 	}
 
 	// Build query parameters
-	params := fmt.Sprintf("max_results=%d&tweet.fields=id,text,created_at,public_metrics,attachments", limit)
+	params := fmt.Sprintf("max_results=%d&tweet.fields=id,text,created_at,public_metrics,attachments&expansions=attachments.media_keys&media.fields=type,url,preview_image_url", limit)
 
 	// Add time range filters if provided
 	if startTime > 0 {
@@ -323,7 +763,7 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpx.Do(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -337,6 +777,10 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if err := xAccessLevelError(resp.StatusCode, body, xOperationRecentPosts); err != nil {
+			return nil, err
+		}
+
 		// Parse error response
 		var errorResponse struct {
 			Detail string `json:"detail"`
@@ -346,10 +790,10 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 		}
 
 		if err := json.Unmarshal(body, &errorResponse); err == nil {
-			return nil, fmt.Errorf("x api error (%d): %s", errorResponse.Status, errorResponse.Detail)
+			return nil, platformError(resp.StatusCode, body, fmt.Errorf("x api error (%d): %s", errorResponse.Status, errorResponse.Detail))
 		}
 
-		return nil, fmt.Errorf("x api error: status=%d body=%s", resp.StatusCode, string(body))
+		return nil, platformError(resp.StatusCode, body, fmt.Errorf("x api error: status=%d body=%s", resp.StatusCode, string(body)))
 	}
 
 	// Parse successful response
@@ -368,12 +812,35 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 				MediaKeys []string `json:"media_keys"`
 			} `json:"attachments,omitempty"`
 		} `json:"data"`
+		Includes struct {
+			Media []struct {
+				MediaKey        string `json:"media_key"`
+				Type            string `json:"type"`
+				URL             string `json:"url,omitempty"`
+				PreviewImageURL string `json:"preview_image_url,omitempty"`
+			} `json:"media"`
+		} `json:"includes"`
 	}
 
 	if err := json.Unmarshal(body, &tweetsResponse); err != nil {
 		return nil, fmt.Errorf("failed to parse tweets response: %w", err)
 	}
 
+	// Index expanded media by key
+	type xMedia struct {
+		Type string
+		URL  string
+	}
+	mediaByKey := make(map[string]xMedia, len(tweetsResponse.Includes.Media))
+	for _, media := range tweetsResponse.Includes.Media {
+		// Videos and GIFs only have a preview image URL
+		mediaURL := media.URL
+		if mediaURL == "" {
+			mediaURL = media.PreviewImageURL
+		}
+		mediaByKey[media.MediaKey] = xMedia{Type: media.Type, URL: mediaURL}
+	}
+
 	// Convert to Post structs
 	var posts []types.Post
 	for _, tweet := range tweetsResponse.Data {
@@ -386,10 +853,14 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 		// Build tweet URL
 		tweetURL := fmt.Sprintf("https://x.com/i/web/status/%s", tweet.ID)
 
-		// Determine media type
-		mediaType := ""
+		// Determine media type from the first attachment, tweets with mixed media report the first one
+		var mediaType, mediaURL string
 		if len(tweet.Attachments.MediaKeys) > 0 {
-			mediaType = "image" // Default to image, could be enhanced to detect video
+			mediaType = "image"
+			if media, ok := mediaByKey[tweet.Attachments.MediaKeys[0]]; ok {
+				mediaType = xMediaType(media.Type)
+				mediaURL = media.URL
+			}
 		}
 
 		// Extract hashtags from tweet text
@@ -401,13 +872,14 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 			Content:   tweet.Text,
 			CreatedAt: createdTime.Unix(),
 			UpdatedAt: createdTime.Unix(), // X doesn't provide separate updated time
-			Stats: types.StatsData{
+			Stats: fetchedStats(types.StatsData{
 				Likes:    tweet.PublicMetrics.LikeCount,
 				Retweets: tweet.PublicMetrics.RetweetCount,
 				Replies:  tweet.PublicMetrics.ReplyCount,
 				Shares:   tweet.PublicMetrics.QuoteCount,
-			},
+			}),
 			URL:       tweetURL,
+			MediaURL:  mediaURL,
 			MediaType: mediaType,
 			Tags:      tags,
 		}
@@ -418,6 +890,111 @@ func (x *XPlatform) GetRecentPosts(ctx context.Context, client *http.Client, lim
 	return posts, nil
 }
 
+// GetPost retrieves a single tweet by ID
+func (x *XPlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	if mediaID == "" {
+		return types.Post{}, fmt.Errorf("media_id required")
+	}
+
+	url := fmt.Sprintf("https://api.x.com/2/tweets/%s?tweet.fields=id,text,created_at,public_metrics,attachments&expansions=attachments.media_keys&media.fields=type,url,preview_image_url", mediaID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpx.Do(client, req)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if err := xAccessLevelError(resp.StatusCode, body, xOperationPostLookup); err != nil {
+			return types.Post{}, err
+		}
+
+		return types.Post{}, platformError(resp.StatusCode, body, fmt.Errorf("x api error: status=%d body=%s", resp.StatusCode, string(body)))
+	}
+
+	var tweetResponse struct {
+		Data *struct {
+			ID            string `json:"id"`
+			Text          string `json:"text"`
+			CreatedAt     string `json:"created_at"`
+			PublicMetrics struct {
+				RetweetCount    int `json:"retweet_count"`
+				LikeCount       int `json:"like_count"`
+				ReplyCount      int `json:"reply_count"`
+				QuoteCount      int `json:"quote_count"`
+				ImpressionCount int `json:"impression_count"`
+			} `json:"public_metrics"`
+		} `json:"data"`
+		Includes struct {
+			Media []struct {
+				Type            string `json:"type"`
+				URL             string `json:"url,omitempty"`
+				PreviewImageURL string `json:"preview_image_url,omitempty"`
+			} `json:"media"`
+		} `json:"includes"`
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &tweetResponse); err != nil {
+		return types.Post{}, fmt.Errorf("failed to parse tweet response: %w", err)
+	}
+
+	// Deleted or unavailable tweets come back as 200 with only an errors array
+	if tweetResponse.Data == nil {
+		if len(tweetResponse.Errors) > 0 {
+			return types.Post{}, fmt.Errorf("x post not found: %s", tweetResponse.Errors[0].Detail)
+		}
+		return types.Post{}, fmt.Errorf("x post not found: %s", mediaID)
+	}
+
+	tweet := tweetResponse.Data
+	createdTime, err := time.Parse(time.RFC3339, tweet.CreatedAt)
+	if err != nil {
+		createdTime = time.Now()
+	}
+
+	var mediaType, mediaURL string
+	if len(tweetResponse.Includes.Media) > 0 {
+		media := tweetResponse.Includes.Media[0]
+		mediaType = xMediaType(media.Type)
+		mediaURL = media.URL
+		if mediaURL == "" {
+			mediaURL = media.PreviewImageURL
+		}
+	}
+
+	return types.Post{
+		ID:        tweet.ID,
+		Content:   tweet.Text,
+		CreatedAt: createdTime.Unix(),
+		UpdatedAt: createdTime.Unix(),
+		Stats: fetchedStats(types.StatsData{
+			Likes:    tweet.PublicMetrics.LikeCount,
+			Retweets: tweet.PublicMetrics.RetweetCount,
+			Replies:  tweet.PublicMetrics.ReplyCount,
+			Shares:   tweet.PublicMetrics.QuoteCount,
+			Views:    tweet.PublicMetrics.ImpressionCount,
+		}),
+		URL:       fmt.Sprintf("https://x.com/i/web/status/%s", tweet.ID),
+		MediaURL:  mediaURL,
+		MediaType: mediaType,
+		Tags:      extractHashtags(tweet.Text),
+	}, nil
+}
+
 // extractHashtags extracts hashtags from tweet text
 func extractHashtags(text string) []string {
 	var hashtags []string
@@ -438,6 +1015,18 @@ func extractHashtags(text string) []string {
 	return hashtags
 }
 
+// xMediaType maps an X media type to a post media type
+func xMediaType(mediaType string) string {
+	switch mediaType {
+	case "video":
+		return "video"
+	case "animated_gif":
+		return "gif"
+	default:
+		return "image"
+	}
+}
+
 // HandleOAuthCallback handles OAuth callback for X platform
 func (x *XPlatform) HandleOAuthCallback(ctx context.Context, code, state string) error {
 	// X平台特定的OAuth回调处理逻辑
@@ -0,0 +1,65 @@
+package platforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// X operations, named in access level errors
+const (
+	xOperationPost        = "posting tweets"
+	xOperationDelete      = "deleting tweets"
+	xOperationMediaUpload = "uploading media"
+	xOperationUserLookup  = "looking up the authenticated user"
+	xOperationStats       = "reading tweet metrics"
+	xOperationRecentPosts = "listing the user's tweets"
+	xOperationPostLookup  = "looking up tweets"
+)
+
+// xRequiredAccessLevels is the lowest X API access level that permits each operation
+var xRequiredAccessLevels = map[string]string{
+	xOperationPost:        "Free",
+	xOperationDelete:      "Free",
+	xOperationMediaUpload: "Free",
+	xOperationUserLookup:  "Free",
+	xOperationStats:       "Basic",
+	xOperationRecentPosts: "Basic",
+	xOperationPostLookup:  "Basic",
+}
+
+// xAccessLevelError returns an error explaining which access level an operation needs if the
+// response is X's 403 for an app whose access level doesn't include the endpoint, or nil for any
+// other response. X reports these as "client-not-enrolled" or "client-forbidden" problems, often
+// without a status field, so the reason and type are checked instead.
+func xAccessLevelError(statusCode int, body []byte, operation string) error {
+	if statusCode != http.StatusForbidden {
+		return nil
+	}
+
+	var problem struct {
+		Reason string `json:"reason"`
+		Type   string `json:"type"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return nil
+	}
+	if problem.Reason != "client-not-enrolled" && problem.Reason != "not-permitted" &&
+		!strings.HasSuffix(problem.Type, "/client-forbidden") && !strings.Contains(problem.Detail, "access level") {
+		return nil
+	}
+
+	level := xRequiredAccessLevels[operation]
+	if level == "" {
+		level = "Basic"
+	}
+
+	return &PlatformError{
+		Category:   CategoryAccessLevel,
+		StatusCode: statusCode,
+		Err: fmt.Errorf("x app's API access level does not permit %s, which requires the %s access level or higher; upgrade the app's plan in the X developer portal (%s)",
+			operation, level, problem.Detail),
+	}
+}
@@ -0,0 +1,266 @@
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"social/internal/types"
+)
+
+// xMediaUploadURL is the chunked media upload endpoint (INIT/APPEND/FINALIZE/STATUS)
+const xMediaUploadURL = "https://api.x.com/2/media/upload"
+
+// xMediaMetadataURL sets metadata such as alt text on uploaded media, the v2 successor of metadata/create
+const xMediaMetadataURL = "https://api.x.com/2/media/metadata"
+
+// X media size limits and upload chunking
+const (
+	xMaxImageSize     = 5 * 1024 * 1024
+	xMaxGIFSize       = 15 * 1024 * 1024
+	xMaxVideoSize     = 512 * 1024 * 1024
+	xMediaChunkSize   = 4 * 1024 * 1024
+	xMaxStatusChecks  = 30
+	xDefaultCheckWait = 2 * time.Second
+)
+
+// xMediaProcessingInfo is the async processing state X reports for videos and GIFs
+type xMediaProcessingInfo struct {
+	State          string `json:"state"`
+	CheckAfterSecs int    `json:"check_after_secs"`
+	Error          *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// xMediaUploadResponse is the response of the INIT, FINALIZE and STATUS commands
+type xMediaUploadResponse struct {
+	Data struct {
+		ID             string                `json:"id"`
+		ProcessingInfo *xMediaProcessingInfo `json:"processing_info,omitempty"`
+	} `json:"data"`
+}
+
+// uploadMedia downloads the media at mediaURL and uploads it to X in chunks, returning the media ID
+// to attach to a tweet. Videos and GIFs are polled until X has finished processing them.
+func (x *XPlatform) uploadMedia(ctx context.Context, client *http.Client, mediaURL string) (string, error) {
+	mediaData, mediaType, err := downloadMedia(ctx, mediaURL, xMaxVideoSize)
+	if err != nil {
+		return "", err
+	}
+
+	category, err := xMediaCategory(mediaType, len(mediaData))
+	if err != nil {
+		return "", err
+	}
+
+	initResp, err := x.mediaCommand(ctx, client, url.Values{
+		"command":        {"INIT"},
+		"total_bytes":    {strconv.Itoa(len(mediaData))},
+		"media_type":     {mediaType},
+		"media_category": {category},
+	})
+	if err != nil {
+		return "", fmt.Errorf("media upload INIT failed: %w", err)
+	}
+
+	mediaID := initResp.Data.ID
+	if mediaID == "" {
+		return "", fmt.Errorf("media upload INIT returned no media id")
+	}
+
+	for segment := 0; segment*xMediaChunkSize < len(mediaData); segment++ {
+		start := segment * xMediaChunkSize
+		end := min(start+xMediaChunkSize, len(mediaData))
+		if err := x.appendMediaChunk(ctx, client, mediaID, segment, mediaData[start:end]); err != nil {
+			return "", fmt.Errorf("media upload APPEND failed at segment %d: %w", segment, err)
+		}
+		types.ReportUploadProgress(ctx, int64(end), int64(len(mediaData)))
+	}
+
+	finalizeResp, err := x.mediaCommand(ctx, client, url.Values{
+		"command":  {"FINALIZE"},
+		"media_id": {mediaID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("media upload FINALIZE failed: %w", err)
+	}
+
+	if err := x.waitForMediaProcessing(ctx, client, mediaID, finalizeResp.Data.ProcessingInfo); err != nil {
+		return "", err
+	}
+
+	types.AddUploadedBytes(ctx, int64(len(mediaData)))
+	return mediaID, nil
+}
+
+// setMediaAltText sets the alt text screen readers announce for uploaded media
+func (x *XPlatform) setMediaAltText(ctx context.Context, client *http.Client, mediaID, altText string) error {
+	payload := map[string]any{
+		"id": mediaID,
+		"metadata": map[string]any{
+			"alt_text": map[string]string{"text": altText},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal media metadata: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", xMediaMetadataURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	_, err = x.doMediaRequest(client, httpReq)
+	return err
+}
+
+// xMediaCategory returns the upload category for a media type, enforcing X's size limit for it
+func xMediaCategory(mediaType string, size int) (string, error) {
+	var category string
+	var limit int
+	switch {
+	case mediaType == "image/gif":
+		category, limit = "tweet_gif", xMaxGIFSize
+	case strings.HasPrefix(mediaType, "image/"):
+		category, limit = "tweet_image", xMaxImageSize
+	case strings.HasPrefix(mediaType, "video/"):
+		category, limit = "tweet_video", xMaxVideoSize
+	default:
+		return "", fmt.Errorf("unsupported media type %q, x accepts images, GIFs and videos", mediaType)
+	}
+
+	if size > limit {
+		return "", fmt.Errorf("media exceeds x's %dMB limit for %s: %w", limit/(1024*1024), category, types.ErrMediaTooLarge)
+	}
+
+	return category, nil
+}
+
+// mediaCommand sends a form-encoded media upload command and parses its response
+func (x *XPlatform) mediaCommand(ctx context.Context, client *http.Client, form url.Values) (xMediaUploadResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", xMediaUploadURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return xMediaUploadResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return x.doMediaRequest(client, httpReq)
+}
+
+// appendMediaChunk uploads one segment of the media as multipart form data
+func (x *XPlatform) appendMediaChunk(ctx context.Context, client *http.Client, mediaID string, segment int, chunk []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("command", "APPEND")
+	_ = writer.WriteField("media_id", mediaID)
+	_ = writer.WriteField("segment_index", strconv.Itoa(segment))
+	part, err := writer.CreateFormFile("media", "media")
+	if err != nil {
+		return fmt.Errorf("failed to create multipart form: %w", err)
+	}
+	if _, err := part.Write(chunk); err != nil {
+		return fmt.Errorf("failed to write media chunk: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart form: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", xMediaUploadURL, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	_, err = x.doMediaRequest(client, httpReq)
+	return err
+}
+
+// waitForMediaProcessing polls STATUS until X has processed the media, which videos and GIFs need
+// before they can be attached
+func (x *XPlatform) waitForMediaProcessing(ctx context.Context, client *http.Client, mediaID string, info *xMediaProcessingInfo) error {
+	for checks := 0; info != nil; checks++ {
+		switch info.State {
+		case "succeeded":
+			return nil
+		case "failed":
+			if info.Error != nil && info.Error.Message != "" {
+				return fmt.Errorf("x media processing failed: %s", info.Error.Message)
+			}
+			return fmt.Errorf("x media processing failed")
+		}
+
+		if checks >= xMaxStatusChecks {
+			return fmt.Errorf("x media processing did not finish after %d status checks", checks)
+		}
+
+		wait := xDefaultCheckWait
+		if info.CheckAfterSecs > 0 {
+			wait = time.Duration(info.CheckAfterSecs) * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		statusURL := fmt.Sprintf("%s?command=STATUS&media_id=%s", xMediaUploadURL, url.QueryEscape(mediaID))
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		statusResp, err := x.doMediaRequest(client, httpReq)
+		if err != nil {
+			return fmt.Errorf("media upload STATUS failed: %w", err)
+		}
+		info = statusResp.Data.ProcessingInfo
+	}
+
+	// No processing info means the media is ready
+	return nil
+}
+
+// doMediaRequest sends a media upload request and parses its response, which is empty for APPEND
+func (x *XPlatform) doMediaRequest(client *http.Client, httpReq *http.Request) (xMediaUploadResponse, error) {
+	var uploadResp xMediaUploadResponse
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return uploadResp, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return uploadResp, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if err := xAccessLevelError(resp.StatusCode, body, xOperationMediaUpload); err != nil {
+			return uploadResp, err
+		}
+		return uploadResp, platformError(resp.StatusCode, body, fmt.Errorf("x media api error: status=%d, body=%s", resp.StatusCode, string(body)))
+	}
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &uploadResp); err != nil {
+			return uploadResp, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return uploadResp, nil
+}
@@ -4,16 +4,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"net/http"
-	"path/filepath"
-	"strings"
 	"time"
+	"unicode/utf8"
 
 	"social/internal/types"
+	"social/pkg/media"
 
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
+	"google.golang.org/api/youtubeanalytics/v2"
 )
 
 // Context key type for storing video ID
@@ -29,28 +29,15 @@ type VideoDetails struct {
 	Tags []string
 }
 
-// Audio file extensions
-var audioExtensions = map[string]bool{
-	".mp3":  true,
-	".wav":  true,
-	".flac": true,
-	".aac":  true,
-	".ogg":  true,
-	".m4a":  true,
-	".wma":  true,
-}
+// youtubeAudioOnlyError explains how to upload audio, YouTube only ingests files with a video track
+const youtubeAudioOnlyError = "youtube doesn't accept audio-only files, combine the audio with a still image into a video " +
+	"(e.g. ffmpeg -loop 1 -i cover.jpg -i audio.mp3 -c:v libx264 -tune stillimage -c:a aac -shortest video.mp4) and share the video"
 
-// Video file extensions
-var videoExtensions = map[string]bool{
-	".mp4":  true,
-	".avi":  true,
-	".mov":  true,
-	".wmv":  true,
-	".flv":  true,
-	".webm": true,
-	".mkv":  true,
-	".m4v":  true,
-}
+// YouTube video metadata length limits
+const (
+	youtubeMaxTitleLength       = 100
+	youtubeMaxDescriptionLength = 5000
+)
 
 // YouTubePlatform implements the YouTube platform
 type YouTubePlatform struct{}
@@ -65,23 +52,45 @@ func (y *YouTubePlatform) GetName() string {
 	return "youtube"
 }
 
-// detectMediaType detects if the file is audio or video based on URL extension
-func (y *YouTubePlatform) detectMediaType(mediaURL string) string {
-	// Extract file extension from URL
-	ext := strings.ToLower(filepath.Ext(mediaURL))
+// Capabilities reports what YouTube supports, every upload needs a video file
+func (y *YouTubePlatform) Capabilities() types.PlatformCapabilities {
+	return types.PlatformCapabilities{
+		CanShare:            true,
+		CanDelete:           true,
+		RequiresMedia:       true,
+		SupportsStats:       true,
+		SupportsRecentPosts: true,
+		SupportsScheduling:  true,
+		SupportsVideo:       true,
+	}
+}
 
-	// Check if it's an audio file
-	if audioExtensions[ext] {
+// detectMediaType detects if a file of the given MIME type is audio or video, anything that isn't
+// audio is uploaded as video
+func (y *YouTubePlatform) detectMediaType(mimeType string) string {
+	if media.IsAudio(mimeType) {
 		return MediaTypeAudio
 	}
+	return MediaTypeVideo
+}
 
-	// Check if it's a video file
-	if videoExtensions[ext] {
-		return MediaTypeVideo
+// Validate checks that a share request can be uploaded to YouTube
+func (y *YouTubePlatform) Validate(req *types.ShareRequest) error {
+	if req.MediaURL == "" {
+		return types.NewValidationError("media_url is required for YouTube upload")
 	}
-
-	// Default to video for unknown extensions
-	return MediaTypeVideo
+	// Catch audio early when the URL gives it away, Share checks the downloaded content again
+	if y.detectMediaType(media.TypeByURL(req.MediaURL)) == MediaTypeAudio {
+		return types.NewValidationError(youtubeAudioOnlyError)
+	}
+	// A title taken from content is truncated, an explicit one is rejected
+	if utf8.RuneCountInString(req.Title) > youtubeMaxTitleLength {
+		return types.NewValidationError("youtube title exceeds %d characters", youtubeMaxTitleLength)
+	}
+	if utf8.RuneCountInString(y.getDescription(req, MediaTypeVideo)) > youtubeMaxDescriptionLength {
+		return types.NewValidationError("youtube description exceeds %d characters", youtubeMaxDescriptionLength)
+	}
+	return nil
 }
 
 // Share shares content to YouTube
@@ -90,39 +99,30 @@ func (y *YouTubePlatform) Share(ctx context.Context, client *http.Client, req *t
 	fmt.Printf("YouTube Share request - Title: '%s', Description: '%s', Content: '%s', Tags: %v\n",
 		req.Title, req.Desc, req.Content, req.Tags)
 
-	// Check if we have a media URL to upload
-	if req.MediaURL == "" {
-		return "", fmt.Errorf("media_url is required for YouTube upload")
+	if err := y.Validate(req); err != nil {
+		return "", err
 	}
 
-	// Detect media type (audio or video)
-	mediaType := y.detectMediaType(req.MediaURL)
-	fmt.Printf("Detected media type: %s for URL: %s\n", mediaType, req.MediaURL)
-
 	// Download the media file from the URL
-	mediaData, err := y.downloadMedia(ctx, client, req.MediaURL)
+	mediaData, contentType, err := downloadMedia(ctx, req.MediaURL, youtubeMaxMediaSize)
 	if err != nil {
 		return "", fmt.Errorf("failed to download media: %w", err)
 	}
 
-	// Create metadata based on media type
-	metadata := y.createMetadata(req, mediaType)
+	// Audio behind a URL without an audio extension is only recognized once downloaded,
+	// YouTube would accept the upload and then fail processing it
+	if y.detectMediaType(contentType) == MediaTypeAudio {
+		return "", types.NewValidationError(youtubeAudioOnlyError)
+	}
 
-	// Upload based on media type
-	var mediaID string
-	if mediaType == MediaTypeAudio {
-		// For audio files, upload to YouTube with music-specific metadata
-		mediaID, err = y.uploadAudio(ctx, client, mediaData, metadata)
-		if err != nil {
-			return "", fmt.Errorf("failed to upload audio: %w", err)
-		}
-	} else {
-		// For video files, upload to YouTube normally
-		mediaID, err = y.uploadVideo(ctx, client, mediaData, metadata)
-		if err != nil {
-			return "", fmt.Errorf("failed to upload video: %w", err)
-		}
+	metadata := y.createMetadata(req, MediaTypeVideo)
+
+	mediaID, err := y.uploadVideo(ctx, client, mediaData, metadata)
+	if err != nil {
+		return "", googleError(fmt.Errorf("failed to upload video: %w", err))
 	}
+	types.ReportUploadProgress(ctx, int64(len(mediaData)), int64(len(mediaData)))
+	types.AddUploadedBytes(ctx, int64(len(mediaData)))
 
 	return mediaID, nil
 }
@@ -139,31 +139,250 @@ func (y *YouTubePlatform) GetStats(ctx context.Context, client *http.Client, med
 		return types.StatsData{}, fmt.Errorf("failed to create YouTube service: %w", err)
 	}
 
-	// Call the videos.list method to get video statistics
-	call := service.Videos.List([]string{"statistics"}).Id(mediaID)
+	// Call the videos.list method to get video statistics, the snippet has the publish date for analytics
+	call := service.Videos.List([]string{"statistics", "snippet"}).Id(mediaID)
 	response, err := call.Context(ctx).Do()
 	if err != nil {
-		return types.StatsData{}, fmt.Errorf("failed to get video statistics: %w", err)
+		return types.StatsData{}, googleError(fmt.Errorf("failed to get video statistics: %w", err))
 	}
 
 	if len(response.Items) == 0 {
 		return types.StatsData{}, fmt.Errorf("video not found")
 	}
 
-	stats := response.Items[0].Statistics
+	video := response.Items[0]
+	stats := video.Statistics
 
 	// Parse counts - YouTube SDK returns uint64 values directly
 	views := int(stats.ViewCount)
 	likes := int(stats.LikeCount)
 	comments := int(stats.CommentCount)
 
-	return types.StatsData{
+	data := types.StatsData{
 		Views:    views,
 		Likes:    likes,
 		Replies:  comments,
 		Shares:   0, // YouTube doesn't provide share count in basic stats
 		Retweets: 0, // YouTube doesn't have retweets
-	}, nil
+	}
+
+	// Watch time needs the yt-analytics.readonly scope, the core stats are still returned without it
+	if video.Snippet != nil {
+		if minutes, err := y.minutesWatched(ctx, client, mediaID, video.Snippet.PublishedAt); err == nil {
+			data.Extra = map[string]int{extraEstimatedMinutesWatched: minutes}
+		}
+	}
+
+	return fetchedStats(data), nil
+}
+
+// minutesWatched returns the estimated minutes a video has been watched since it was published
+func (y *YouTubePlatform) minutesWatched(ctx context.Context, client *http.Client, videoID, publishedAt string) (int, error) {
+	published, err := time.Parse(time.RFC3339, publishedAt)
+	if err != nil {
+		return 0, fmt.Errorf("invalid publish date %q: %w", publishedAt, err)
+	}
+
+	service, err := youtubeanalytics.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create YouTube Analytics service: %w", err)
+	}
+
+	report, err := service.Reports.Query().
+		Ids("channel==MINE").
+		StartDate(published.UTC().Format(time.DateOnly)).
+		EndDate(time.Now().UTC().Format(time.DateOnly)).
+		Metrics(extraEstimatedMinutesWatched).
+		Filters("video==" + videoID).
+		Context(ctx).Do()
+	if err != nil {
+		return 0, googleError(fmt.Errorf("failed to get video analytics: %w", err))
+	}
+
+	// Videos without views in the range come back without rows
+	if len(report.Rows) == 0 || len(report.Rows[0]) == 0 {
+		return 0, nil
+	}
+	minutes, ok := report.Rows[0][0].(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected analytics value %v", report.Rows[0][0])
+	}
+	return int(minutes), nil
+}
+
+// GetPost retrieves a single video by ID, with its snippet and statistics in one call
+func (y *YouTubePlatform) GetPost(ctx context.Context, client *http.Client, mediaID string) (types.Post, error) {
+	if mediaID == "" {
+		return types.Post{}, fmt.Errorf("media_id required")
+	}
+
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return types.Post{}, fmt.Errorf("failed to create YouTube service: %w", err)
+	}
+
+	response, err := service.Videos.List([]string{"snippet", "statistics"}).Id(mediaID).Context(ctx).Do()
+	if err != nil {
+		return types.Post{}, googleError(fmt.Errorf("failed to get video: %w", err))
+	}
+
+	if len(response.Items) == 0 {
+		return types.Post{}, platformError(http.StatusNotFound, nil, fmt.Errorf("video not found"))
+	}
+
+	video := response.Items[0]
+	post := types.Post{
+		ID:        video.Id,
+		URL:       fmt.Sprintf("https://www.youtube.com/watch?v=%s", video.Id),
+		MediaType: "video",
+		Tags:      []string{},
+	}
+	if video.Snippet != nil {
+		// Use title as content if description is empty
+		post.Title = video.Snippet.Title
+		post.Description = video.Snippet.Description
+		post.Content = video.Snippet.Description
+		if post.Content == "" {
+			post.Content = video.Snippet.Title
+		}
+		if published, err := time.Parse(time.RFC3339, video.Snippet.PublishedAt); err == nil {
+			post.CreatedAt = published.Unix()
+			post.UpdatedAt = published.Unix() // YouTube doesn't provide separate updated time
+		}
+		if video.Snippet.Thumbnails != nil && video.Snippet.Thumbnails.Default != nil {
+			post.MediaURL = video.Snippet.Thumbnails.Default.Url
+		}
+		if video.Snippet.Tags != nil {
+			post.Tags = video.Snippet.Tags
+		}
+	}
+	if video.Statistics != nil {
+		post.Stats = fetchedStats(types.StatsData{
+			Views:   int(video.Statistics.ViewCount),
+			Likes:   int(video.Statistics.LikeCount),
+			Replies: int(video.Statistics.CommentCount),
+			Shares:  0, // YouTube doesn't provide share count in basic stats
+		})
+	}
+
+	return post, nil
+}
+
+// youtubeProcessingReasons explains the failure, rejection and processing failure reasons YouTube
+// reports for a video that didn't go live
+var youtubeProcessingReasons = map[string]string{
+	// status.failureReason
+	"codec":         "youtube doesn't support the video's codec",
+	"conversion":    "youtube couldn't convert the video",
+	"emptyFile":     "the uploaded file is empty",
+	"invalidFile":   "the uploaded file isn't a valid video",
+	"tooSmall":      "the uploaded file is too small",
+	"uploadAborted": "the upload was aborted",
+	// status.rejectionReason
+	"claim":                    "the video was rejected because of a content claim",
+	"copyright":                "the video was rejected for copyright infringement",
+	"duplicate":                "the video duplicates one already uploaded",
+	"inappropriate":            "the video was rejected for inappropriate content",
+	"legal":                    "the video was rejected for legal reasons",
+	"length":                   "the video is longer than the channel is allowed to upload",
+	"termsOfUse":               "the video violates youtube's terms of use",
+	"trademark":                "the video was rejected for trademark infringement",
+	"uploaderAccountClosed":    "the uploader's account is closed",
+	"uploaderAccountSuspended": "the uploader's account is suspended",
+	// processingDetails.processingFailureReason
+	"other":           "youtube failed to process the video",
+	"streamingFailed": "youtube failed to prepare the video for streaming",
+	"transcodeFailed": "youtube failed to transcode the video",
+	"uploadFailed":    "the upload failed before processing",
+}
+
+// GetPostStatus maps the video's upload and processing status to a post status, explaining
+// failures with YouTube's failure, rejection or processing failure reason
+func (y *YouTubePlatform) GetPostStatus(ctx context.Context, client *http.Client, mediaID string) (types.PostStatusDetail, error) {
+	if mediaID == "" {
+		return types.PostStatusDetail{}, fmt.Errorf("media_id required")
+	}
+
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return types.PostStatusDetail{}, fmt.Errorf("failed to create YouTube service: %w", err)
+	}
+
+	response, err := service.Videos.List([]string{"status", "processingDetails"}).Id(mediaID).Context(ctx).Do()
+	if err != nil {
+		return types.PostStatusDetail{}, googleError(fmt.Errorf("failed to get video status: %w", err))
+	}
+
+	if len(response.Items) == 0 || response.Items[0].Status == nil {
+		return types.PostStatusDetail{}, fmt.Errorf("video not found")
+	}
+
+	status := response.Items[0].Status
+	var processingStatus, processingFailure string
+	if details := response.Items[0].ProcessingDetails; details != nil {
+		processingStatus = details.ProcessingStatus
+		processingFailure = details.ProcessingFailureReason
+	}
+
+	switch {
+	case status.UploadStatus == "failed":
+		return youtubeFailedStatus(status.FailureReason, processingFailure), nil
+	case status.UploadStatus == "rejected":
+		return youtubeFailedStatus(status.RejectionReason, processingFailure), nil
+	case status.UploadStatus == "deleted":
+		return types.PostStatusDetail{
+			Status:          types.PostStatusFailed,
+			ProcessingError: &types.ProcessingError{Reason: "deleted", Message: "the video was deleted"},
+		}, nil
+	case processingStatus == "failed" || processingStatus == "terminated":
+		return youtubeFailedStatus(processingFailure, ""), nil
+	case status.UploadStatus == "processed":
+		if status.PublishAt != "" && status.PrivacyStatus == "private" {
+			return types.PostStatusDetail{Status: types.PostStatusScheduled}, nil
+		}
+		return types.PostStatusDetail{Status: types.PostStatusPublished}, nil
+	default:
+		return types.PostStatusDetail{Status: types.PostStatusProcessing}, nil
+	}
+}
+
+// youtubeFailedStatus builds the failed status for the first reason YouTube gave
+func youtubeFailedStatus(reasons ...string) types.PostStatusDetail {
+	reason := "other"
+	for _, r := range reasons {
+		if r != "" {
+			reason = r
+			break
+		}
+	}
+
+	message, ok := youtubeProcessingReasons[reason]
+	if !ok {
+		message = "youtube didn't publish the video: " + reason
+	}
+
+	return types.PostStatusDetail{
+		Status:          types.PostStatusFailed,
+		ProcessingError: &types.ProcessingError{Reason: reason, Message: message},
+	}
+}
+
+// DeletePost deletes a YouTube video
+func (y *YouTubePlatform) DeletePost(ctx context.Context, client *http.Client, mediaID string) error {
+	if mediaID == "" {
+		return fmt.Errorf("media_id required")
+	}
+
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return fmt.Errorf("failed to create YouTube service: %w", err)
+	}
+
+	if err := service.Videos.Delete(mediaID).Context(ctx).Do(); err != nil {
+		return googleError(fmt.Errorf("failed to delete video: %w", err))
+	}
+
+	return nil
 }
 
 // GetUserInfo retrieves user information from YouTube platform using the official SDK
@@ -178,7 +397,7 @@ func (y *YouTubePlatform) GetUserInfo(ctx context.Context, client *http.Client)
 	call := service.Channels.List([]string{"snippet", "statistics"}).Mine(true)
 	response, err := call.Context(ctx).Do()
 	if err != nil {
-		return types.UserInfo{}, fmt.Errorf("failed to get channel info: %w", err)
+		return types.UserInfo{}, googleError(fmt.Errorf("failed to get channel info: %w", err))
 	}
 
 	if len(response.Items) == 0 {
@@ -225,7 +444,7 @@ func (y *YouTubePlatform) GetRecentPosts(ctx context.Context, client *http.Clien
 	channelsCall := service.Channels.List([]string{"id"}).Mine(true)
 	channelsResponse, err := channelsCall.Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user channel: %w", err)
+		return nil, googleError(fmt.Errorf("failed to get user channel: %w", err))
 	}
 
 	if len(channelsResponse.Items) == 0 {
@@ -239,7 +458,7 @@ func (y *YouTubePlatform) GetRecentPosts(ctx context.Context, client *http.Clien
 	channelsCall2 := service.Channels.List([]string{"contentDetails"}).Id(channelID)
 	channelsResponse2, err := channelsCall2.Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get channel details: %w", err)
+		return nil, googleError(fmt.Errorf("failed to get channel details: %w", err))
 	}
 
 	if len(channelsResponse2.Items) == 0 {
@@ -268,7 +487,7 @@ func (y *YouTubePlatform) GetRecentPosts(ctx context.Context, client *http.Clien
 	playlistResponse, err := playlistItemsCall.Context(ctx).Do()
 	if err != nil {
 		fmt.Printf("DEBUG: Playlist items request failed with error: %v\n", err)
-		return nil, fmt.Errorf("failed to get playlist items: %w", err)
+		return nil, googleError(fmt.Errorf("failed to get playlist items: %w", err))
 	}
 
 	fmt.Printf("DEBUG: Playlist items request successful, found %d items\n", len(playlistResponse.Items))
@@ -327,7 +546,7 @@ func (y *YouTubePlatform) GetRecentPosts(ctx context.Context, client *http.Clien
 		// Get video statistics and tags
 		stats, err := y.getVideoStats(ctx, service, item.Snippet.ResourceId.VideoId)
 		if err != nil {
-			// If stats fail, continue with zero stats
+			// If stats fail, continue without stats, HasStats stays false
 			stats = types.StatsData{}
 		}
 
@@ -398,12 +617,12 @@ func (y *YouTubePlatform) getVideoStats(ctx context.Context, service *youtube.Se
 
 	stats := response.Items[0].Statistics
 
-	return types.StatsData{
+	return fetchedStats(types.StatsData{
 		Views:   int(stats.ViewCount),
 		Likes:   int(stats.LikeCount),
 		Replies: int(stats.CommentCount),
 		Shares:  0, // YouTube doesn't provide share count in basic stats
-	}, nil
+	}), nil
 }
 
 // getVideoDetails gets detailed information for a specific video including tags
@@ -446,33 +665,6 @@ func (y *YouTubePlatform) HandleOAuthCallback(ctx context.Context, code, state s
 	return nil
 }
 
-// downloadMedia downloads media file from the given URL
-func (y *YouTubePlatform) downloadMedia(ctx context.Context, client *http.Client, mediaURL string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create download request: %w", err)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download media: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("failed to download media: status=%d", resp.StatusCode)
-	}
-
-	mediaData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read media data: %w", err)
-	}
-
-	return mediaData, nil
-}
-
 // createMetadata creates metadata for YouTube upload based on media type
 func (y *YouTubePlatform) createMetadata(req *types.ShareRequest, mediaType string) map[string]any {
 	title := y.getTitle(req, mediaType)
@@ -491,6 +683,7 @@ func (y *YouTubePlatform) createMetadata(req *types.ShareRequest, mediaType stri
 		"status": map[string]any{
 			"privacyStatus": y.getPrivacyStatus(req),
 		},
+		"notifySubscribers": y.getNotifySubscribers(req),
 	}
 
 	// Add category ID based on media type
@@ -574,14 +767,12 @@ func (y *YouTubePlatform) getPrivacyStatus(req *types.ShareRequest) string {
 	}
 }
 
-// uploadAudio uploads audio to YouTube with music-specific settings
-func (y *YouTubePlatform) uploadAudio(ctx context.Context, client *http.Client, audioData []byte, metadata map[string]any) (string, error) {
-	// For audio files, we upload to YouTube but with music-specific metadata
-	// This will make the content more discoverable in YouTube Music
-	fmt.Printf("Uploading audio file to YouTube with music metadata\n")
-
-	// Use the same upload logic as video, but with music-specific metadata
-	return y.uploadVideo(ctx, client, audioData, metadata)
+// getNotifySubscribers returns whether subscribers are notified of the upload, true unless disabled
+func (y *YouTubePlatform) getNotifySubscribers(req *types.ShareRequest) bool {
+	if req.NotifySubscribers == nil {
+		return true
+	}
+	return *req.NotifySubscribers
 }
 
 // uploadVideo uploads video to YouTube using the official YouTube Go client library
@@ -608,7 +799,7 @@ func (y *YouTubePlatform) uploadVideo(ctx context.Context, client *http.Client,
 		Snippet: &youtube.VideoSnippet{
 			Title:       getStringFromInterface(snippetData["title"]),
 			Description: getStringFromInterface(snippetData["description"]),
-			// CategoryId:  "22", // Default category for "People & Blogs"
+			CategoryId:  getStringFromInterface(snippetData["categoryId"]),
 		},
 		Status: &youtube.VideoStatus{
 			PrivacyStatus: getStringFromInterface(statusData["privacyStatus"]),
@@ -626,14 +817,21 @@ func (y *YouTubePlatform) uploadVideo(ctx context.Context, client *http.Client,
 
 	// Create the insert call
 	call := service.Videos.Insert([]string{"snippet", "status"}, upload)
+	if notify, ok := metadata["notifySubscribers"].(bool); ok {
+		call = call.NotifySubscribers(notify)
+	}
 
 	// Create a reader from the video data
 	videoReader := bytes.NewReader(videoData)
 
-	// Execute the upload
+	// Execute the upload, progress is only reported for uploads large enough to be sent in chunks
+	total := int64(len(videoData))
+	call = call.ProgressUpdater(func(current, _ int64) {
+		types.ReportUploadProgress(ctx, current, total)
+	})
 	response, err := call.Media(videoReader).Context(ctx).Do()
 	if err != nil {
-		return "", fmt.Errorf("failed to upload video: %w", err)
+		return "", googleError(fmt.Errorf("failed to upload video: %w", err))
 	}
 
 	// Debug logging
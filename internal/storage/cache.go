@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultTokenCacheTTL is how long a token lookup is served from the in-process cache
+const DefaultTokenCacheTTL = 5 * time.Second
+
+// cachedToken holds the result of a token lookup, including a not-found result
+type cachedToken struct {
+	token     *oauth2.Token
+	err       error
+	expiresAt time.Time
+}
+
+// CachedStorage wraps a Storage and caches token lookups for a short time to
+// avoid repeated round trips on hot auth-check paths. Saving or deleting a
+// token invalidates its cache entry.
+type CachedStorage struct {
+	Storage
+
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cachedToken
+}
+
+// NewCachedStorage creates a storage that caches token lookups for ttl
+func NewCachedStorage(storage Storage, ttl time.Duration) *CachedStorage {
+	return &CachedStorage{
+		Storage: storage,
+		ttl:     ttl,
+		entries: make(map[string]cachedToken),
+	}
+}
+
+// Unwrap returns the wrapped storage
+func (s *CachedStorage) Unwrap() Storage {
+	return s.Storage
+}
+
+// cacheKey generates the cache key for a token
+func (s *CachedStorage) cacheKey(userID, provider, serverName string) string {
+	return fmt.Sprintf("%s:%s:%s", userID, provider, serverName)
+}
+
+// SaveToken stores a token and invalidates its cache entry
+func (s *CachedStorage) SaveToken(ctx context.Context, userID, provider, serverName string, token *oauth2.Token) error {
+	err := s.Storage.SaveToken(ctx, userID, provider, serverName, token)
+	s.invalidate(userID, provider, serverName)
+	return err
+}
+
+// GetToken returns a cached token lookup if fresh, otherwise reads through to storage
+func (s *CachedStorage) GetToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error) {
+	key := s.cacheKey(userID, provider, serverName)
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	s.mu.Unlock()
+
+	if !ok || now.After(entry.expiresAt) {
+		token, err := s.Storage.GetToken(ctx, userID, provider, serverName)
+		if err != nil && ctx.Err() != nil {
+			// Don't cache failures caused by the caller's context
+			return nil, err
+		}
+
+		entry = cachedToken{token: token, err: err, expiresAt: now.Add(s.ttl)}
+
+		s.mu.Lock()
+		s.entries[key] = entry
+		s.pruneLocked(now)
+		s.mu.Unlock()
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	// Return a copy so callers can't modify the cached token
+	token := *entry.token
+	return &token, nil
+}
+
+// DeleteToken removes a token and invalidates its cache entry
+func (s *CachedStorage) DeleteToken(ctx context.Context, userID, provider, serverName string) error {
+	err := s.Storage.DeleteToken(ctx, userID, provider, serverName)
+	s.invalidate(userID, provider, serverName)
+	return err
+}
+
+// invalidate drops the cache entry for a token
+func (s *CachedStorage) invalidate(userID, provider, serverName string) {
+	s.mu.Lock()
+	delete(s.entries, s.cacheKey(userID, provider, serverName))
+	s.mu.Unlock()
+}
+
+// Cleanup drops expired cache entries and cleans up the wrapped storage
+func (s *CachedStorage) Cleanup(ctx context.Context) error {
+	now := time.Now()
+	s.mu.Lock()
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.mu.Unlock()
+
+	return s.Storage.Cleanup(ctx)
+}
+
+// pruneLocked drops expired entries once the cache has grown, caller must hold mu
+func (s *CachedStorage) pruneLocked(now time.Time) {
+	if len(s.entries) < 1024 {
+		return
+	}
+
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -2,8 +2,13 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
+
+	"social/internal/types"
 )
 
 // Storage defines the interface for token and PKCE storage
@@ -12,14 +17,118 @@ type Storage interface {
 	SaveToken(ctx context.Context, userID, provider, serverName string, token *oauth2.Token) error
 	GetToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error)
 	DeleteToken(ctx context.Context, userID, provider, serverName string) error
+	ListTokens(ctx context.Context) ([]TokenRecord, error)
+	ListProvidersForUser(ctx context.Context, userID, serverName string) ([]string, error)
+	ListAccounts(ctx context.Context, userID, provider, serverName string) ([]string, error)
+
+	// Reach baseline operations
+	GetReachSamples(ctx context.Context, userID, provider, serverName string) (map[string]ReachSample, error)
+	SaveReachSamples(ctx context.Context, userID, provider, serverName string, samples map[string]ReachSample) error
+
+	// Stats cache operations
+	GetCachedStats(ctx context.Context, provider, mediaID string) (types.StatsData, bool, error)
+	SaveCachedStats(ctx context.Context, provider, mediaID string, stats types.StatsData, ttl time.Duration) error
+
+	// User info cache operations
+	GetCachedUserInfo(ctx context.Context, userID, provider, serverName string) (types.UserInfo, bool, error)
+	SaveCachedUserInfo(ctx context.Context, userID, provider, serverName string, userInfo types.UserInfo, ttl time.Duration) error
 
 	// PKCE operations
 	SavePKCEVerifier(ctx context.Context, state, verifier string) error
 	GetAndDeletePKCEVerifier(ctx context.Context, state string) (string, error)
 
+	// OAuth state operations
+	SaveOAuthState(ctx context.Context, state string) error
+	ConsumeOAuthState(ctx context.Context, state string) error
+
+	// Scheduled post operations
+	SaveScheduledPost(ctx context.Context, post *ScheduledPost) error
+	ListDueScheduledPosts(ctx context.Context, now time.Time) ([]*ScheduledPost, error)
+	TakeScheduledPost(ctx context.Context, jobID string) (*ScheduledPost, error)
+
+	// Dead-letter operations for scheduled posts that failed to publish
+	SaveFailedScheduledPost(ctx context.Context, post *FailedScheduledPost) error
+	ListFailedScheduledPosts(ctx context.Context) ([]*FailedScheduledPost, error)
+	TakeFailedScheduledPost(ctx context.Context, jobID string) (*FailedScheduledPost, error)
+
+	// Idempotency key operations
+	ReserveIdempotencyKey(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error)
+	SaveIdempotencyRecord(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	DeleteIdempotencyRecord(ctx context.Context, key string) error
+
+	// Distributed lock operations
+	AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, name, owner string) error
+
 	// Health check
 	Health(ctx context.Context) error
 
+	// Expiry cleanup, purges entries past their expiry that reads already ignore
+	Cleanup(ctx context.Context) error
+
 	// Cleanup
 	Close() error
 }
+
+// TokenRecord is a stored token together with the identity it belongs to
+type TokenRecord struct {
+	UserID     string
+	Provider   string
+	ServerName string
+	Token      *oauth2.Token
+}
+
+// accountOf returns the account ID stored under keyUser when it belongs to userID, the empty
+// string for the default account
+func accountOf(keyUser, userID string) (string, bool) {
+	if keyUser == userID {
+		return "", true
+	}
+	accountID, ok := strings.CutPrefix(keyUser, userID+types.AccountSeparator)
+	return accountID, ok && accountID != ""
+}
+
+// ReachSample is a snapshot of a post's reach, keyed by media ID in a user's reach baseline
+type ReachSample struct {
+	Impressions int   `json:"impressions"`
+	Engagements int   `json:"engagements"`
+	SampledAt   int64 `json:"sampled_at"`
+}
+
+// OAuthStateTTL is how long an issued OAuth state stays valid for its callback
+const OAuthStateTTL = 30 * time.Minute
+
+// ErrOAuthStateNotFound is returned when a state was never issued, has expired or was already consumed
+var ErrOAuthStateNotFound = errors.New("oauth state not found or already used")
+
+// ScheduledPost is a share request waiting to be published at its scheduled time
+type ScheduledPost struct {
+	ID          string             `json:"id"`
+	ScheduledAt int64              `json:"scheduled_at"`
+	CreatedAt   int64              `json:"created_at"`
+	Request     types.ShareRequest `json:"request"`
+}
+
+// FailedScheduledPost is a scheduled post that failed to publish, kept in the dead-letter list
+// until it is retried
+type FailedScheduledPost struct {
+	ScheduledPost
+	Error    string `json:"error"`
+	FailedAt int64  `json:"failed_at"`
+}
+
+// ErrScheduledPostNotFound is returned when a scheduled post doesn't exist, was cancelled or was already picked up
+var ErrScheduledPostNotFound = errors.New("scheduled post not found")
+
+// IdempotencyRecord is what is remembered about a request made with an Idempotency-Key.
+// ReserveIdempotencyKey stores it incomplete while the first request is in flight, and
+// SaveIdempotencyRecord stores it completed with that request's response.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`           // hash of the request body the key was first used with
+	Completed   bool   `json:"completed"`             // the first request has finished and its response is stored
+	StatusCode  int    `json:"status_code,omitempty"` // status of the first request's response
+	Body        []byte `json:"body,omitempty"`        // body of the first request's response
+}
+
+// ReachSampleTTL is how long a user's reach baseline is kept after its last update
+const ReachSampleTTL = 90 * 24 * time.Hour
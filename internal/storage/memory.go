@@ -0,0 +1,564 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"social/internal/types"
+)
+
+// memoryEntry holds a stored value together with its expiration time
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// expired reports whether the entry is past its expiration time
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// MemoryStorage implements token and PKCE storage in process memory.
+// It is intended for tests and local development where Redis is not available.
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	tokens  map[string]memoryEntry
+	pkce    map[string]memoryEntry
+	states  map[string]memoryEntry
+	jobs    map[string]*ScheduledPost
+	failed  map[string]*FailedScheduledPost
+	reach   map[string]memoryEntry
+	stats   map[string]memoryEntry
+	users   map[string]memoryEntry // cached user info
+	keys    map[string]memoryEntry // idempotency records
+	locks   map[string]memoryEntry
+	ttlFunc TokenTTLFunc
+}
+
+// NewMemoryStorage creates a new in-memory storage instance
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		tokens: make(map[string]memoryEntry),
+		pkce:   make(map[string]memoryEntry),
+		states: make(map[string]memoryEntry),
+		jobs:   make(map[string]*ScheduledPost),
+		failed: make(map[string]*FailedScheduledPost),
+		reach:  make(map[string]memoryEntry),
+		stats:  make(map[string]memoryEntry),
+		users:  make(map[string]memoryEntry),
+		keys:   make(map[string]memoryEntry),
+		locks:  make(map[string]memoryEntry),
+	}
+}
+
+// SetTokenTTLFunc sets the configured token TTL lookup used when saving tokens
+func (m *MemoryStorage) SetTokenTTLFunc(ttlFunc TokenTTLFunc) {
+	m.ttlFunc = ttlFunc
+}
+
+// TokenKey generates a key for storing tokens, matching the Redis key layout
+func (m *MemoryStorage) TokenKey(userID, provider, serverName string) string {
+	if serverName == "" {
+		serverName = "default"
+	}
+	return fmt.Sprintf("token:%s:%s:%s", serverName, provider, userID)
+}
+
+// SaveToken stores an OAuth token in memory with expiration
+func (m *MemoryStorage) SaveToken(ctx context.Context, userID, provider, serverName string, token *oauth2.Token) error {
+	key := m.TokenKey(userID, provider, serverName)
+
+	// Serialize token to JSON so callers can't mutate the stored copy
+	data, err := marshalToken(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ttl := tokenTTL(m.ttlFunc, provider, serverName, token)
+	m.tokens[key] = memoryEntry{value: data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// GetToken retrieves an OAuth token from memory
+func (m *MemoryStorage) GetToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error) {
+	key := m.TokenKey(userID, provider, serverName)
+
+	m.mu.RLock()
+	entry, exists := m.tokens[key]
+	m.mu.RUnlock()
+
+	if !exists || entry.expired(time.Now()) {
+		return nil, fmt.Errorf("token not found")
+	}
+
+	token, err := unmarshalToken(entry.value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+
+	if err := validateToken(token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// ListTokens returns all unexpired tokens
+func (m *MemoryStorage) ListTokens(ctx context.Context) ([]TokenRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	var records []TokenRecord
+	for key, entry := range m.tokens {
+		if entry.expired(now) {
+			continue
+		}
+
+		serverName, provider, userID, ok := parseTokenKey(key)
+		if !ok {
+			continue
+		}
+
+		token, err := unmarshalToken(entry.value)
+		if err != nil {
+			continue
+		}
+		if err := validateToken(token); err != nil {
+			continue
+		}
+
+		records = append(records, TokenRecord{
+			UserID:     userID,
+			Provider:   provider,
+			ServerName: serverName,
+			Token:      token,
+		})
+	}
+
+	return records, nil
+}
+
+// ListProvidersForUser returns the providers a user has unexpired tokens for on a server
+func (m *MemoryStorage) ListProvidersForUser(ctx context.Context, userID, serverName string) ([]string, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	var providers []string
+	for key, entry := range m.tokens {
+		if entry.expired(now) {
+			continue
+		}
+
+		keyServer, provider, keyUser, ok := parseTokenKey(key)
+		if !ok || keyServer != serverName || keyUser != userID {
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// ListAccounts returns the accounts a user has unexpired tokens for on a provider, the empty
+// string for the default account
+func (m *MemoryStorage) ListAccounts(ctx context.Context, userID, provider, serverName string) ([]string, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	var accounts []string
+	for key, entry := range m.tokens {
+		if entry.expired(now) {
+			continue
+		}
+
+		keyServer, keyProvider, keyUser, ok := parseTokenKey(key)
+		if !ok || keyServer != serverName || keyProvider != provider {
+			continue
+		}
+		if accountID, ok := accountOf(keyUser, userID); ok {
+			accounts = append(accounts, accountID)
+		}
+	}
+
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// DeleteToken removes an OAuth token from memory
+func (m *MemoryStorage) DeleteToken(ctx context.Context, userID, provider, serverName string) error {
+	key := m.TokenKey(userID, provider, serverName)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tokens, key)
+	return nil
+}
+
+// GetReachSamples retrieves a user's reach baseline samples from memory
+func (m *MemoryStorage) GetReachSamples(ctx context.Context, userID, provider, serverName string) (map[string]ReachSample, error) {
+	key := m.TokenKey(userID, provider, serverName)
+
+	m.mu.RLock()
+	entry, exists := m.reach[key]
+	m.mu.RUnlock()
+
+	samples := make(map[string]ReachSample)
+	if !exists || entry.expired(time.Now()) {
+		return samples, nil
+	}
+
+	if err := json.Unmarshal(entry.value, &samples); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reach samples: %w", err)
+	}
+
+	return samples, nil
+}
+
+// SaveReachSamples stores a user's reach baseline samples in memory
+func (m *MemoryStorage) SaveReachSamples(ctx context.Context, userID, provider, serverName string, samples map[string]ReachSample) error {
+	key := m.TokenKey(userID, provider, serverName)
+
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reach samples: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reach[key] = memoryEntry{value: data, expiresAt: time.Now().Add(ReachSampleTTL)}
+	return nil
+}
+
+// GetCachedStats retrieves a post's cached stats from memory, reporting whether they were found
+func (m *MemoryStorage) GetCachedStats(ctx context.Context, provider, mediaID string) (types.StatsData, bool, error) {
+	key := fmt.Sprintf("stats:%s:%s", provider, mediaID)
+
+	m.mu.RLock()
+	entry, exists := m.stats[key]
+	m.mu.RUnlock()
+
+	if !exists || entry.expired(time.Now()) {
+		return types.StatsData{}, false, nil
+	}
+
+	var stats types.StatsData
+	if err := json.Unmarshal(entry.value, &stats); err != nil {
+		return types.StatsData{}, false, fmt.Errorf("failed to unmarshal cached stats: %w", err)
+	}
+
+	return stats, true, nil
+}
+
+// SaveCachedStats caches a post's stats in memory for ttl
+func (m *MemoryStorage) SaveCachedStats(ctx context.Context, provider, mediaID string, stats types.StatsData, ttl time.Duration) error {
+	key := fmt.Sprintf("stats:%s:%s", provider, mediaID)
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats[key] = memoryEntry{value: data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// GetCachedUserInfo retrieves a user's cached profile from memory, reporting whether it was found
+func (m *MemoryStorage) GetCachedUserInfo(ctx context.Context, userID, provider, serverName string) (types.UserInfo, bool, error) {
+	key := fmt.Sprintf("userinfo:%s:%s:%s", provider, userID, serverName)
+
+	m.mu.RLock()
+	entry, exists := m.users[key]
+	m.mu.RUnlock()
+
+	if !exists || entry.expired(time.Now()) {
+		return types.UserInfo{}, false, nil
+	}
+
+	var userInfo types.UserInfo
+	if err := json.Unmarshal(entry.value, &userInfo); err != nil {
+		return types.UserInfo{}, false, fmt.Errorf("failed to unmarshal cached user info: %w", err)
+	}
+
+	return userInfo, true, nil
+}
+
+// SaveCachedUserInfo caches a user's profile in memory for ttl
+func (m *MemoryStorage) SaveCachedUserInfo(ctx context.Context, userID, provider, serverName string, userInfo types.UserInfo, ttl time.Duration) error {
+	key := fmt.Sprintf("userinfo:%s:%s:%s", provider, userID, serverName)
+
+	data, err := json.Marshal(userInfo)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user info: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users[key] = memoryEntry{value: data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// SavePKCEVerifier stores a PKCE verifier in memory with short expiration
+func (m *MemoryStorage) SavePKCEVerifier(ctx context.Context, state, verifier string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// PKCE verifiers should expire quickly (30 minutes to allow for user interaction time)
+	m.pkce[state] = memoryEntry{value: []byte(verifier), expiresAt: time.Now().Add(30 * time.Minute)}
+	return nil
+}
+
+// GetAndDeletePKCEVerifier retrieves and deletes a PKCE verifier from memory
+func (m *MemoryStorage) GetAndDeletePKCEVerifier(ctx context.Context, state string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.pkce[state]
+	delete(m.pkce, state)
+
+	if !exists || entry.expired(time.Now()) {
+		return "", fmt.Errorf("PKCE verifier not found or expired")
+	}
+
+	return string(entry.value), nil
+}
+
+// SaveOAuthState records an issued OAuth state in memory
+func (m *MemoryStorage) SaveOAuthState(ctx context.Context, state string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[state] = memoryEntry{expiresAt: time.Now().Add(OAuthStateTTL)}
+	return nil
+}
+
+// ConsumeOAuthState verifies an OAuth state was issued and deletes it from memory
+func (m *MemoryStorage) ConsumeOAuthState(ctx context.Context, state string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.states[state]
+	delete(m.states, state)
+
+	if !exists || entry.expired(time.Now()) {
+		return ErrOAuthStateNotFound
+	}
+	return nil
+}
+
+// SaveScheduledPost stores a pending scheduled post in memory
+func (m *MemoryStorage) SaveScheduledPost(ctx context.Context, post *ScheduledPost) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *post
+	m.jobs[post.ID] = &stored
+	return nil
+}
+
+// ListDueScheduledPosts returns the pending scheduled posts whose time has come, earliest first
+func (m *MemoryStorage) ListDueScheduledPosts(ctx context.Context, now time.Time) ([]*ScheduledPost, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var posts []*ScheduledPost
+	for _, post := range m.jobs {
+		if post.ScheduledAt <= now.Unix() {
+			due := *post
+			posts = append(posts, &due)
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].ScheduledAt < posts[j].ScheduledAt
+	})
+	return posts, nil
+}
+
+// TakeScheduledPost removes a pending scheduled post from memory and returns it
+func (m *MemoryStorage) TakeScheduledPost(ctx context.Context, jobID string) (*ScheduledPost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	post, exists := m.jobs[jobID]
+	if !exists {
+		return nil, ErrScheduledPostNotFound
+	}
+	delete(m.jobs, jobID)
+	return post, nil
+}
+
+// SaveFailedScheduledPost adds a scheduled post that failed to publish to the dead-letter list
+func (m *MemoryStorage) SaveFailedScheduledPost(ctx context.Context, post *FailedScheduledPost) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *post
+	m.failed[post.ID] = &stored
+	return nil
+}
+
+// ListFailedScheduledPosts returns the dead-letter list, most recently failed first
+func (m *MemoryStorage) ListFailedScheduledPosts(ctx context.Context) ([]*FailedScheduledPost, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	posts := make([]*FailedScheduledPost, 0, len(m.failed))
+	for _, post := range m.failed {
+		failed := *post
+		posts = append(posts, &failed)
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].FailedAt > posts[j].FailedAt
+	})
+	return posts, nil
+}
+
+// TakeFailedScheduledPost removes a post from the dead-letter list and returns it
+func (m *MemoryStorage) TakeFailedScheduledPost(ctx context.Context, jobID string) (*FailedScheduledPost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	post, exists := m.failed[jobID]
+	if !exists {
+		return nil, ErrScheduledPostNotFound
+	}
+	delete(m.failed, jobID)
+	return post, nil
+}
+
+// ReserveIdempotencyKey stores record under an unused Idempotency-Key and returns nil, or returns
+// the record already stored under the key without changing it
+func (m *MemoryStorage) ReserveIdempotencyKey(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, exists := m.keys[key]; exists && !entry.expired(time.Now()) {
+		var stored IdempotencyRecord
+		if err := json.Unmarshal(entry.value, &stored); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+		}
+		return &stored, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	m.keys[key] = memoryEntry{value: data, expiresAt: time.Now().Add(ttl)}
+	return nil, nil
+}
+
+// SaveIdempotencyRecord stores the record of an Idempotency-Key for ttl, replacing any reservation
+func (m *MemoryStorage) SaveIdempotencyRecord(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys[key] = memoryEntry{value: data, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// DeleteIdempotencyRecord releases an Idempotency-Key so it can be used again
+func (m *MemoryStorage) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.keys, key)
+	return nil
+}
+
+// AcquireLock takes the named lock for owner until ttl passes or it is released, returning false
+// if someone else holds it
+func (m *MemoryStorage) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, exists := m.locks[name]; exists && !entry.expired(time.Now()) {
+		return false, nil
+	}
+
+	m.locks[name] = memoryEntry{value: []byte(owner), expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLock releases the named lock if owner still holds it
+func (m *MemoryStorage) ReleaseLock(ctx context.Context, name, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, exists := m.locks[name]; exists && string(entry.value) == owner {
+		delete(m.locks, name)
+	}
+	return nil
+}
+
+// Cleanup deletes expired tokens, PKCE verifiers, OAuth states, caches, idempotency records and locks
+func (m *MemoryStorage) Cleanup(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, entries := range []map[string]memoryEntry{m.tokens, m.pkce, m.states, m.reach, m.stats, m.users, m.keys, m.locks} {
+		for key, entry := range entries {
+			if entry.expired(now) {
+				delete(entries, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Close releases the stored data
+func (m *MemoryStorage) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens = make(map[string]memoryEntry)
+	m.pkce = make(map[string]memoryEntry)
+	m.states = make(map[string]memoryEntry)
+	m.jobs = make(map[string]*ScheduledPost)
+	m.failed = make(map[string]*FailedScheduledPost)
+	m.reach = make(map[string]memoryEntry)
+	m.stats = make(map[string]memoryEntry)
+	m.users = make(map[string]memoryEntry)
+	m.keys = make(map[string]memoryEntry)
+	m.locks = make(map[string]memoryEntry)
+	return nil
+}
+
+// Health always succeeds for in-memory storage
+func (m *MemoryStorage) Health(ctx context.Context) error {
+	return nil
+}
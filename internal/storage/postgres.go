@@ -0,0 +1,837 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"golang.org/x/oauth2"
+
+	"social/internal/types"
+	"social/pkg/logger"
+)
+
+// postgresDriver is the database/sql driver used for Postgres, registered by github.com/jackc/pgx/v5/stdlib
+const postgresDriver = "pgx"
+
+// postgresMigrationLock is the advisory lock key held while migrating, so instances starting
+// together don't apply the same migration twice
+const postgresMigrationLock = 0x736f6369616c // "social"
+
+// postgresMigrations are the schema versions in order, each applied once in a transaction and
+// recorded in schema_migrations. Released migrations must not change, add a new one instead.
+var postgresMigrations = [][]string{
+	{
+		`CREATE TABLE tokens (
+			server_name  TEXT NOT NULL,
+			provider     TEXT NOT NULL,
+			user_id      TEXT NOT NULL,
+			token        BYTEA NOT NULL,
+			token_expiry TIMESTAMPTZ,
+			expires_at   TIMESTAMPTZ NOT NULL,
+			created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+			updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (server_name, provider, user_id)
+		)`,
+		`CREATE INDEX tokens_expires_at_idx ON tokens (expires_at)`,
+		`CREATE TABLE pkce (
+			state      TEXT PRIMARY KEY,
+			verifier   TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE TABLE oauth_states (
+			state      TEXT PRIMARY KEY,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE TABLE entries (
+			key        TEXT PRIMARY KEY,
+			value      BYTEA NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE TABLE scheduled_posts (
+			id           TEXT PRIMARY KEY,
+			scheduled_at BIGINT NOT NULL,
+			data         JSONB NOT NULL
+		)`,
+		`CREATE INDEX scheduled_posts_scheduled_at_idx ON scheduled_posts (scheduled_at)`,
+		`CREATE TABLE failed_scheduled_posts (
+			id        TEXT PRIMARY KEY,
+			failed_at BIGINT NOT NULL,
+			data      JSONB NOT NULL
+		)`,
+		`CREATE TABLE locks (
+			name       TEXT PRIMARY KEY,
+			owner      TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`,
+	},
+}
+
+// PostgresStorage implements token and PKCE storage in Postgres. Tokens are encrypted with
+// AES-GCM before they are written; everything else is stored as is. Expired rows are ignored
+// by reads and deleted by Cleanup.
+type PostgresStorage struct {
+	db      *sql.DB
+	aead    cipher.AEAD
+	ttlFunc TokenTTLFunc
+	logger  *logger.Logger
+}
+
+// NewPostgresStorage connects to the Postgres database at dsn, migrates its schema and returns
+// a storage encrypting tokens with encryptionKey, which must be 32 bytes (AES-256)
+func NewPostgresStorage(dsn string, encryptionKey []byte, logger *logger.Logger) (*PostgresStorage, error) {
+	aead, err := newTokenCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(postgresDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	p := &PostgresStorage{
+		db:     db,
+		aead:   aead,
+		logger: logger,
+	}
+
+	if err := p.migrate(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// newTokenCipher creates the AES-GCM cipher tokens are encrypted with
+func newTokenCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("token encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// migrate applies the migrations the database doesn't have yet
+func (p *PostgresStorage) migrate(ctx context.Context) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin postgres migration: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, postgresMigrationLock); err != nil {
+		return fmt.Errorf("failed to lock postgres migrations: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read postgres schema version: %w", err)
+	}
+
+	for version := current + 1; version <= len(postgresMigrations); version++ {
+		for _, statement := range postgresMigrations[version-1] {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("failed to apply postgres migration %d: %w", version, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			return fmt.Errorf("failed to record postgres migration %d: %w", version, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit postgres migrations: %w", err)
+	}
+	return nil
+}
+
+// SetTokenTTLFunc sets the configured token TTL lookup used when saving tokens
+func (p *PostgresStorage) SetTokenTTLFunc(ttlFunc TokenTTLFunc) {
+	p.ttlFunc = ttlFunc
+}
+
+// tokenAAD returns the additional data a token is sealed with, binding it to its row so a
+// ciphertext copied into another user's row doesn't decrypt there
+func tokenAAD(userID, provider, serverName string) []byte {
+	return []byte(serverName + "\x00" + provider + "\x00" + userID)
+}
+
+// encrypt seals data, authenticating aad with it, with a random nonce, which is prepended to the result
+func (p *PostgresStorage) encrypt(data, aad []byte) ([]byte, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return p.aead.Seal(nonce, nonce, data, aad), nil
+}
+
+// decrypt opens data sealed by encrypt with the same aad
+func (p *PostgresStorage) decrypt(data, aad []byte) ([]byte, error) {
+	nonceSize := p.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return p.aead.Open(nil, data[:nonceSize], data[nonceSize:], aad)
+}
+
+// decodeToken decrypts and decodes the token stored in the row of aad
+func (p *PostgresStorage) decodeToken(data, aad []byte) (*oauth2.Token, error) {
+	plaintext, err := p.decrypt(data, aad)
+	if err != nil {
+		// Tokens that can't be decrypted, e.g. after the key changed or when copied from another
+		// row, can't be used or refreshed
+		return nil, fmt.Errorf("%w: failed to decrypt token: %v", ErrInvalidToken, err)
+	}
+
+	token, err := unmarshalToken(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+
+	if err := validateToken(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// SaveToken encrypts and stores an OAuth token with expiration
+func (p *PostgresStorage) SaveToken(ctx context.Context, userID, provider, serverName string, token *oauth2.Token) error {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	data, err := marshalToken(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	encrypted, err := p.encrypt(data, tokenAAD(userID, provider, serverName))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token: %w", err)
+	}
+
+	// The access token's own expiry is kept in the clear so tokens can be queried by it
+	var tokenExpiry sql.NullTime
+	if !token.Expiry.IsZero() {
+		tokenExpiry = sql.NullTime{Time: token.Expiry, Valid: true}
+	}
+
+	ttl := tokenTTL(p.ttlFunc, provider, serverName, token)
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO tokens (server_name, provider, user_id, token, token_expiry, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (server_name, provider, user_id) DO UPDATE
+		SET token = EXCLUDED.token, token_expiry = EXCLUDED.token_expiry, expires_at = EXCLUDED.expires_at, updated_at = now()`,
+		serverName, provider, userID, encrypted, tokenExpiry, time.Now().Add(ttl))
+	if err != nil {
+		return fmt.Errorf("failed to save token to postgres: %w", err)
+	}
+
+	return nil
+}
+
+// GetToken retrieves and decrypts an OAuth token
+func (p *PostgresStorage) GetToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	var data []byte
+	err := p.db.QueryRowContext(ctx, `
+		SELECT token FROM tokens
+		WHERE server_name = $1 AND provider = $2 AND user_id = $3 AND expires_at > $4`,
+		serverName, provider, userID, time.Now()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("token not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token from postgres: %w", err)
+	}
+
+	return p.decodeToken(data, tokenAAD(userID, provider, serverName))
+}
+
+// ListTokens returns all unexpired tokens. Records that can't be decrypted or decoded are skipped
+// with a warning, so a wrong encryption key shows up in the logs rather than as users without accounts.
+func (p *PostgresStorage) ListTokens(ctx context.Context) ([]TokenRecord, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT server_name, provider, user_id, token FROM tokens
+		WHERE expires_at > $1`, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens from postgres: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var records []TokenRecord
+	for rows.Next() {
+		var record TokenRecord
+		var data []byte
+		if err := rows.Scan(&record.ServerName, &record.Provider, &record.UserID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", err)
+		}
+
+		token, err := p.decodeToken(data, tokenAAD(record.UserID, record.Provider, record.ServerName))
+		if err != nil {
+			p.logger.Warn(ctx, "skipping token that can't be decoded", "server_name", record.ServerName, "provider", record.Provider, "user_id", record.UserID, "error", err)
+			continue
+		}
+		record.Token = token
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tokens from postgres: %w", err)
+	}
+	return records, nil
+}
+
+// ListProvidersForUser returns the providers a user has unexpired tokens for on a server
+func (p *PostgresStorage) ListProvidersForUser(ctx context.Context, userID, serverName string) ([]string, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT provider FROM tokens
+		WHERE server_name = $1 AND user_id = $2 AND expires_at > $3
+		ORDER BY provider`, serverName, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers from postgres: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var providers []string
+	for rows.Next() {
+		var provider string
+		if err := rows.Scan(&provider); err != nil {
+			return nil, fmt.Errorf("failed to scan provider: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list providers from postgres: %w", err)
+	}
+	return providers, nil
+}
+
+// ListAccounts returns the accounts a user has unexpired tokens for on a provider, the empty
+// string for the default account
+func (p *PostgresStorage) ListAccounts(ctx context.Context, userID, provider, serverName string) ([]string, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT user_id FROM tokens
+		WHERE server_name = $1 AND provider = $2 AND (user_id = $3 OR starts_with(user_id, $4)) AND expires_at > $5`,
+		serverName, provider, userID, userID+types.AccountSeparator, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts from postgres: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var accounts []string
+	for rows.Next() {
+		var keyUser string
+		if err := rows.Scan(&keyUser); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		if accountID, ok := accountOf(keyUser, userID); ok {
+			accounts = append(accounts, accountID)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts from postgres: %w", err)
+	}
+
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// DeleteToken removes an OAuth token
+func (p *PostgresStorage) DeleteToken(ctx context.Context, userID, provider, serverName string) error {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	if _, err := p.db.ExecContext(ctx, `
+		DELETE FROM tokens WHERE server_name = $1 AND provider = $2 AND user_id = $3`,
+		serverName, provider, userID); err != nil {
+		return fmt.Errorf("failed to delete token from postgres: %w", err)
+	}
+	return nil
+}
+
+// getEntry reads an unexpired entry, reporting whether it was found
+func (p *PostgresStorage) getEntry(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := p.db.QueryRowContext(ctx, `
+		SELECT value FROM entries WHERE key = $1 AND expires_at > $2`, key, time.Now()).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// putEntry stores an entry for ttl, replacing any entry under the same key
+func (p *PostgresStorage) putEntry(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO entries (key, value, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`,
+		key, value, time.Now().Add(ttl))
+	return err
+}
+
+// GetReachSamples retrieves a user's reach baseline samples
+func (p *PostgresStorage) GetReachSamples(ctx context.Context, userID, provider, serverName string) (map[string]ReachSample, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	data, found, err := p.getEntry(ctx, fmt.Sprintf("reach:%s:%s:%s", serverName, provider, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reach samples from postgres: %w", err)
+	}
+
+	samples := make(map[string]ReachSample)
+	if !found {
+		return samples, nil
+	}
+
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reach samples: %w", err)
+	}
+
+	return samples, nil
+}
+
+// SaveReachSamples stores a user's reach baseline samples
+func (p *PostgresStorage) SaveReachSamples(ctx context.Context, userID, provider, serverName string, samples map[string]ReachSample) error {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reach samples: %w", err)
+	}
+
+	if err := p.putEntry(ctx, fmt.Sprintf("reach:%s:%s:%s", serverName, provider, userID), data, ReachSampleTTL); err != nil {
+		return fmt.Errorf("failed to save reach samples to postgres: %w", err)
+	}
+	return nil
+}
+
+// GetCachedStats retrieves a post's cached stats, reporting whether they were found
+func (p *PostgresStorage) GetCachedStats(ctx context.Context, provider, mediaID string) (types.StatsData, bool, error) {
+	data, found, err := p.getEntry(ctx, fmt.Sprintf("stats:%s:%s", provider, mediaID))
+	if err != nil {
+		return types.StatsData{}, false, fmt.Errorf("failed to get cached stats from postgres: %w", err)
+	}
+	if !found {
+		return types.StatsData{}, false, nil
+	}
+
+	var stats types.StatsData
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return types.StatsData{}, false, fmt.Errorf("failed to unmarshal cached stats: %w", err)
+	}
+
+	return stats, true, nil
+}
+
+// SaveCachedStats caches a post's stats for ttl
+func (p *PostgresStorage) SaveCachedStats(ctx context.Context, provider, mediaID string, stats types.StatsData, ttl time.Duration) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	if err := p.putEntry(ctx, fmt.Sprintf("stats:%s:%s", provider, mediaID), data, ttl); err != nil {
+		return fmt.Errorf("failed to cache stats in postgres: %w", err)
+	}
+	return nil
+}
+
+// GetCachedUserInfo retrieves a user's cached profile, reporting whether it was found
+func (p *PostgresStorage) GetCachedUserInfo(ctx context.Context, userID, provider, serverName string) (types.UserInfo, bool, error) {
+	data, found, err := p.getEntry(ctx, fmt.Sprintf("userinfo:%s:%s:%s", provider, userID, serverName))
+	if err != nil {
+		return types.UserInfo{}, false, fmt.Errorf("failed to get cached user info from postgres: %w", err)
+	}
+	if !found {
+		return types.UserInfo{}, false, nil
+	}
+
+	var userInfo types.UserInfo
+	if err := json.Unmarshal(data, &userInfo); err != nil {
+		return types.UserInfo{}, false, fmt.Errorf("failed to unmarshal cached user info: %w", err)
+	}
+
+	return userInfo, true, nil
+}
+
+// SaveCachedUserInfo caches a user's profile for ttl
+func (p *PostgresStorage) SaveCachedUserInfo(ctx context.Context, userID, provider, serverName string, userInfo types.UserInfo, ttl time.Duration) error {
+	data, err := json.Marshal(userInfo)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user info: %w", err)
+	}
+
+	if err := p.putEntry(ctx, fmt.Sprintf("userinfo:%s:%s:%s", provider, userID, serverName), data, ttl); err != nil {
+		return fmt.Errorf("failed to cache user info in postgres: %w", err)
+	}
+	return nil
+}
+
+// SavePKCEVerifier stores a PKCE verifier with short expiration
+func (p *PostgresStorage) SavePKCEVerifier(ctx context.Context, state, verifier string) error {
+	// PKCE verifiers should expire quickly (30 minutes to allow for user interaction time)
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO pkce (state, verifier, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (state) DO UPDATE SET verifier = EXCLUDED.verifier, expires_at = EXCLUDED.expires_at`,
+		state, verifier, time.Now().Add(30*time.Minute))
+	if err != nil {
+		return fmt.Errorf("failed to save PKCE verifier to postgres: %w", err)
+	}
+	return nil
+}
+
+// GetAndDeletePKCEVerifier retrieves and deletes a PKCE verifier in one statement
+func (p *PostgresStorage) GetAndDeletePKCEVerifier(ctx context.Context, state string) (string, error) {
+	var verifier string
+	var expiresAt time.Time
+	err := p.db.QueryRowContext(ctx, `
+		DELETE FROM pkce WHERE state = $1 RETURNING verifier, expires_at`, state).Scan(&verifier, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && time.Now().After(expiresAt)) {
+		return "", fmt.Errorf("PKCE verifier not found or expired")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get PKCE verifier from postgres: %w", err)
+	}
+
+	return verifier, nil
+}
+
+// SaveOAuthState records an issued OAuth state
+func (p *PostgresStorage) SaveOAuthState(ctx context.Context, state string) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO oauth_states (state, expires_at) VALUES ($1, $2)
+		ON CONFLICT (state) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		state, time.Now().Add(OAuthStateTTL))
+	if err != nil {
+		return fmt.Errorf("failed to save oauth state to postgres: %w", err)
+	}
+	return nil
+}
+
+// ConsumeOAuthState verifies an OAuth state was issued and deletes it in one statement,
+// so a state can only be consumed once
+func (p *PostgresStorage) ConsumeOAuthState(ctx context.Context, state string) error {
+	var expiresAt time.Time
+	err := p.db.QueryRowContext(ctx, `
+		DELETE FROM oauth_states WHERE state = $1 RETURNING expires_at`, state).Scan(&expiresAt)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && time.Now().After(expiresAt)) {
+		return ErrOAuthStateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to consume oauth state in postgres: %w", err)
+	}
+	return nil
+}
+
+// SaveScheduledPost stores a pending scheduled post
+func (p *PostgresStorage) SaveScheduledPost(ctx context.Context, post *ScheduledPost) error {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled post: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO scheduled_posts (id, scheduled_at, data) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET scheduled_at = EXCLUDED.scheduled_at, data = EXCLUDED.data`,
+		post.ID, post.ScheduledAt, data)
+	if err != nil {
+		return fmt.Errorf("failed to save scheduled post to postgres: %w", err)
+	}
+	return nil
+}
+
+// ListDueScheduledPosts returns the pending scheduled posts whose time has come, earliest first
+func (p *PostgresStorage) ListDueScheduledPosts(ctx context.Context, now time.Time) ([]*ScheduledPost, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT data FROM scheduled_posts WHERE scheduled_at <= $1 ORDER BY scheduled_at`, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled posts from postgres: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var posts []*ScheduledPost
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled post: %w", err)
+		}
+
+		var post ScheduledPost
+		if err := json.Unmarshal(data, &post); err != nil {
+			continue
+		}
+		posts = append(posts, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled posts from postgres: %w", err)
+	}
+	return posts, nil
+}
+
+// TakeScheduledPost removes a pending scheduled post and returns it. Only one caller can take a
+// post, so an instance that takes it owns publishing it.
+func (p *PostgresStorage) TakeScheduledPost(ctx context.Context, jobID string) (*ScheduledPost, error) {
+	var data []byte
+	err := p.db.QueryRowContext(ctx, `
+		DELETE FROM scheduled_posts WHERE id = $1 RETURNING data`, jobID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrScheduledPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to take scheduled post from postgres: %w", err)
+	}
+
+	var post ScheduledPost
+	if err := json.Unmarshal(data, &post); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduled post: %w", err)
+	}
+	return &post, nil
+}
+
+// SaveFailedScheduledPost adds a scheduled post that failed to publish to the dead-letter list
+func (p *PostgresStorage) SaveFailedScheduledPost(ctx context.Context, post *FailedScheduledPost) error {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return fmt.Errorf("failed to marshal failed scheduled post: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO failed_scheduled_posts (id, failed_at, data) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET failed_at = EXCLUDED.failed_at, data = EXCLUDED.data`,
+		post.ID, post.FailedAt, data)
+	if err != nil {
+		return fmt.Errorf("failed to save failed scheduled post to postgres: %w", err)
+	}
+	return nil
+}
+
+// ListFailedScheduledPosts returns the dead-letter list, most recently failed first
+func (p *PostgresStorage) ListFailedScheduledPosts(ctx context.Context) ([]*FailedScheduledPost, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT data FROM failed_scheduled_posts ORDER BY failed_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed scheduled posts from postgres: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	posts := make([]*FailedScheduledPost, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan failed scheduled post: %w", err)
+		}
+
+		var post FailedScheduledPost
+		if err := json.Unmarshal(data, &post); err != nil {
+			continue
+		}
+		posts = append(posts, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list failed scheduled posts from postgres: %w", err)
+	}
+	return posts, nil
+}
+
+// TakeFailedScheduledPost removes a post from the dead-letter list and returns it
+func (p *PostgresStorage) TakeFailedScheduledPost(ctx context.Context, jobID string) (*FailedScheduledPost, error) {
+	var data []byte
+	err := p.db.QueryRowContext(ctx, `
+		DELETE FROM failed_scheduled_posts WHERE id = $1 RETURNING data`, jobID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrScheduledPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to take failed scheduled post from postgres: %w", err)
+	}
+
+	var post FailedScheduledPost
+	if err := json.Unmarshal(data, &post); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal failed scheduled post: %w", err)
+	}
+	return &post, nil
+}
+
+// ReserveIdempotencyKey stores record under an unused Idempotency-Key and returns nil, or returns
+// the record already stored under the key without changing it
+func (p *PostgresStorage) ReserveIdempotencyKey(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	storageKey := fmt.Sprintf("idempotency:%s", key)
+
+	// The stored record can expire or be released between the insert and the read,
+	// in which case the key is free and the insert is tried again
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		result, err := p.db.ExecContext(ctx, `
+			INSERT INTO entries (key, value, expires_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+			WHERE entries.expires_at <= $4`,
+			storageKey, data, now.Add(ttl), now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key in postgres: %w", err)
+		}
+		if reserved, err := result.RowsAffected(); err == nil && reserved == 1 {
+			return nil, nil
+		}
+
+		existing, found, err := p.getEntry(ctx, storageKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency record from postgres: %w", err)
+		}
+		if !found {
+			continue
+		}
+
+		var stored IdempotencyRecord
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+		}
+		return &stored, nil
+	}
+
+	return nil, fmt.Errorf("failed to reserve idempotency key %s", key)
+}
+
+// SaveIdempotencyRecord stores the record of an Idempotency-Key for ttl, replacing any reservation
+func (p *PostgresStorage) SaveIdempotencyRecord(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	if err := p.putEntry(ctx, fmt.Sprintf("idempotency:%s", key), data, ttl); err != nil {
+		return fmt.Errorf("failed to save idempotency record to postgres: %w", err)
+	}
+	return nil
+}
+
+// DeleteIdempotencyRecord releases an Idempotency-Key so it can be used again
+func (p *PostgresStorage) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM entries WHERE key = $1`, fmt.Sprintf("idempotency:%s", key)); err != nil {
+		return fmt.Errorf("failed to delete idempotency record from postgres: %w", err)
+	}
+	return nil
+}
+
+// AcquireLock takes the named lock for owner until ttl passes or it is released, returning false
+// if someone else holds it. An expired lock is taken over in the same statement.
+func (p *PostgresStorage) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	result, err := p.db.ExecContext(ctx, `
+		INSERT INTO locks (name, owner, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE locks.expires_at <= $4`,
+		name, owner, now.Add(ttl), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock in postgres: %w", err)
+	}
+
+	acquired, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock in postgres: %w", err)
+	}
+	return acquired == 1, nil
+}
+
+// ReleaseLock releases the named lock if owner still holds it
+func (p *PostgresStorage) ReleaseLock(ctx context.Context, name, owner string) error {
+	if _, err := p.db.ExecContext(ctx, `DELETE FROM locks WHERE name = $1 AND owner = $2`, name, owner); err != nil {
+		return fmt.Errorf("failed to release lock in postgres: %w", err)
+	}
+	return nil
+}
+
+// Cleanup deletes expired tokens, PKCE verifiers, OAuth states, entries and locks
+func (p *PostgresStorage) Cleanup(ctx context.Context) error {
+	now := time.Now()
+	for _, table := range []string{"tokens", "pkce", "oauth_states", "entries", "locks"} {
+		if _, err := p.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE expires_at <= $1`, now); err != nil {
+			return fmt.Errorf("failed to clean up postgres %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// Health checks the Postgres connection
+func (p *PostgresStorage) Health(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// Close closes the database connection
+func (p *PostgresStorage) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"social/pkg/logger"
+)
+
+// testEncryptionKey is a fixed 32 byte AES-256 key
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+// testAAD binds test ciphertexts to a token row
+var testAAD = tokenAAD("user123", "x", "myapp")
+
+func newTestCipherStorage(t *testing.T, key []byte) *PostgresStorage {
+	t.Helper()
+	aead, err := newTokenCipher(key)
+	if err != nil {
+		t.Fatalf("newTokenCipher: %v", err)
+	}
+	return &PostgresStorage{aead: aead}
+}
+
+func TestTokenCipherRoundTrip(t *testing.T) {
+	p := newTestCipherStorage(t, testEncryptionKey)
+	plaintext := []byte(`{"access_token":"secret-access-token"}`)
+
+	first, err := p.encrypt(plaintext, testAAD)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if bytes.Contains(first, []byte("secret-access-token")) {
+		t.Fatal("ciphertext contains the plaintext token")
+	}
+
+	second, err := p.encrypt(plaintext, testAAD)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if bytes.Equal(first, second) {
+		t.Fatal("encrypting twice gave the same ciphertext, nonces must be random")
+	}
+
+	for _, ciphertext := range [][]byte{first, second} {
+		decrypted, err := p.decrypt(ciphertext, testAAD)
+		if err != nil {
+			t.Fatalf("decrypt: %v", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("decrypt = %q, want %q", decrypted, plaintext)
+		}
+	}
+}
+
+func TestTokenCipherRejectsOtherKeyAndTampering(t *testing.T) {
+	p := newTestCipherStorage(t, testEncryptionKey)
+	ciphertext, err := p.encrypt([]byte("token"), testAAD)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	other := newTestCipherStorage(t, []byte("fedcba9876543210fedcba9876543210"))
+	if _, err := other.decrypt(ciphertext, testAAD); err == nil {
+		t.Fatal("decrypt with another key succeeded")
+	}
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := p.decrypt(tampered, testAAD); err == nil {
+		t.Fatal("decrypt of tampered ciphertext succeeded")
+	}
+
+	if _, err := p.decrypt(ciphertext[:3], testAAD); err == nil {
+		t.Fatal("decrypt of truncated ciphertext succeeded")
+	}
+}
+
+func TestDecodeTokenWithOtherKeyIsInvalid(t *testing.T) {
+	p := newTestCipherStorage(t, testEncryptionKey)
+	data, err := marshalToken(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("marshalToken: %v", err)
+	}
+	ciphertext, err := p.encrypt(data, testAAD)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	token, err := p.decodeToken(ciphertext, testAAD)
+	if err != nil {
+		t.Fatalf("decodeToken: %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" {
+		t.Fatalf("decodeToken = %+v, want the saved token", token)
+	}
+
+	other := newTestCipherStorage(t, []byte("fedcba9876543210fedcba9876543210"))
+	if _, err := other.decodeToken(ciphertext, testAAD); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("decodeToken with another key = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestDecodeTokenFromOtherRowIsInvalid(t *testing.T) {
+	p := newTestCipherStorage(t, testEncryptionKey)
+	data, err := marshalToken(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("marshalToken: %v", err)
+	}
+	ciphertext, err := p.encrypt(data, tokenAAD("victim", "x", "myapp"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// A ciphertext copied into another user's, provider's or server's row must not decrypt there
+	for _, aad := range [][]byte{
+		tokenAAD("attacker", "x", "myapp"),
+		tokenAAD("victim", "youtube", "myapp"),
+		tokenAAD("victim", "x", "otherapp"),
+		nil,
+	} {
+		if _, err := p.decodeToken(ciphertext, aad); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("decodeToken in row %q = %v, want ErrInvalidToken", aad, err)
+		}
+	}
+}
+
+func TestNewTokenCipherKeyLength(t *testing.T) {
+	if _, err := newTokenCipher(testEncryptionKey[:16]); err == nil {
+		t.Fatal("newTokenCipher accepted a 16 byte key")
+	}
+}
+
+// TestPostgresMigrateAndTokenRoundTrip runs against the database in POSTGRES_TEST_DSN, which
+// should be a disposable database: the test creates the schema and writes a token to it.
+func TestPostgresMigrateAndTokenRoundTrip(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+	ctx := context.Background()
+
+	p, err := NewPostgresStorage(dsn, testEncryptionKey, logger.NewLogger(logger.Config{Level: "error"}))
+	if err != nil {
+		t.Fatalf("NewPostgresStorage: %v", err)
+	}
+	defer func() {
+		_ = p.Close()
+	}()
+
+	// Migrating an up to date schema is a no-op
+	if err := p.migrate(ctx); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+	var version int
+	if err := p.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		t.Fatalf("read schema version: %v", err)
+	}
+	if version != len(postgresMigrations) {
+		t.Fatalf("schema version = %d, want %d", version, len(postgresMigrations))
+	}
+
+	userID := "migrate-test-" + time.Now().Format("20060102150405.000000000")
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}
+	if err := p.SaveToken(ctx, userID, "x", "test", saved); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	defer func() {
+		_ = p.DeleteToken(ctx, userID, "x", "test")
+	}()
+
+	var stored []byte
+	if err := p.db.QueryRowContext(ctx, `SELECT token FROM tokens WHERE server_name = $1 AND provider = $2 AND user_id = $3`, "test", "x", userID).Scan(&stored); err != nil {
+		t.Fatalf("read stored token: %v", err)
+	}
+	if bytes.Contains(stored, []byte("refresh")) {
+		t.Fatal("token is stored unencrypted")
+	}
+
+	got, err := p.GetToken(ctx, userID, "x", "test")
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if got.AccessToken != saved.AccessToken || got.RefreshToken != saved.RefreshToken {
+		t.Fatalf("GetToken = %+v, want %+v", got, saved)
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/oauth2"
+
+	"social/internal/types"
+	"social/pkg/logger"
 )
 
 // RedisStorage implements token and PKCE storage using Redis
 type RedisStorage struct {
-	client *redis.Client
+	client  *redis.Client
+	ttlFunc TokenTTLFunc
+	logger  *logger.Logger
 }
 
 // NewRedisStorage creates a new Redis storage instance
-func NewRedisStorage(addr, password string, db int) (*RedisStorage, error) {
+func NewRedisStorage(addr, password string, db int, logger *logger.Logger) (*RedisStorage, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     addr,
 		Password: password,
@@ -31,7 +39,17 @@ func NewRedisStorage(addr, password string, db int) (*RedisStorage, error) {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	return &RedisStorage{client: rdb}, nil
+	return &RedisStorage{client: rdb, logger: logger}, nil
+}
+
+// Client returns the underlying Redis client so other components can share the connection pool
+func (r *RedisStorage) Client() *redis.Client {
+	return r.client
+}
+
+// SetTokenTTLFunc sets the configured token TTL lookup used when saving tokens
+func (r *RedisStorage) SetTokenTTLFunc(ttlFunc TokenTTLFunc) {
+	r.ttlFunc = ttlFunc
 }
 
 // TokenKey generates a Redis key for storing tokens
@@ -42,34 +60,80 @@ func (r *RedisStorage) TokenKey(userID, provider, serverName string) string {
 	return fmt.Sprintf("token:%s:%s:%s", serverName, provider, userID)
 }
 
+// ReachKey generates a Redis key for storing a user's reach baseline
+func (r *RedisStorage) ReachKey(userID, provider, serverName string) string {
+	if serverName == "" {
+		serverName = "default"
+	}
+	return fmt.Sprintf("reach:%s:%s:%s", serverName, provider, userID)
+}
+
+// StatsKey generates a Redis key for caching a post's stats
+func (r *RedisStorage) StatsKey(provider, mediaID string) string {
+	return fmt.Sprintf("stats:%s:%s", provider, mediaID)
+}
+
+// UserInfoKey generates a Redis key for caching a user's profile on a platform
+func (r *RedisStorage) UserInfoKey(userID, provider, serverName string) string {
+	return fmt.Sprintf("userinfo:%s:%s:%s", provider, userID, serverName)
+}
+
 // PKCEKey generates a Redis key for storing PKCE verifiers
 func (r *RedisStorage) PKCEKey(state string) string {
 	return fmt.Sprintf("pkce:%s", state)
 }
 
+// OAuthStateKey generates a Redis key for tracking issued OAuth states
+func (r *RedisStorage) OAuthStateKey(state string) string {
+	return fmt.Sprintf("oauth_state:%s", state)
+}
+
+// ScheduledPostKey generates a Redis key for storing a scheduled post
+func (r *RedisStorage) ScheduledPostKey(jobID string) string {
+	return fmt.Sprintf("scheduled:job:%s", jobID)
+}
+
+// FailedScheduledPostKey generates a Redis key for storing a scheduled post that failed to publish
+func (r *RedisStorage) FailedScheduledPostKey(jobID string) string {
+	return fmt.Sprintf("scheduled:failed:job:%s", jobID)
+}
+
+// IdempotencyKey generates a Redis key for storing the record of an Idempotency-Key
+func (r *RedisStorage) IdempotencyKey(key string) string {
+	return fmt.Sprintf("idempotency:%s", key)
+}
+
+// LockKey generates a Redis key for a distributed lock
+func (r *RedisStorage) LockKey(name string) string {
+	return fmt.Sprintf("lock:%s", name)
+}
+
+// scheduledFailedKey is the sorted set of failed scheduled post IDs, scored by when they failed
+const scheduledFailedKey = "scheduled:failed"
+
+// scheduledDueKey is the sorted set of pending scheduled post IDs, scored by their scheduled time
+const scheduledDueKey = "scheduled:due"
+
 // SaveToken stores an OAuth token in Redis with expiration
 func (r *RedisStorage) SaveToken(ctx context.Context, userID, provider, serverName string, token *oauth2.Token) error {
 	key := r.TokenKey(userID, provider, serverName)
 
 	// Serialize token to JSON
-	data, err := json.Marshal(token)
+	data, err := marshalToken(token)
 	if err != nil {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
 
-	// Set expiration to 30 days (tokens should be refreshed before this)
-	expiration := 30 * 24 * time.Hour
+	expiration := tokenTTL(r.ttlFunc, provider, serverName, token)
 
-	// Debug: log the key and data size
-	fmt.Printf("DEBUG: Saving token to Redis with key: %s, data size: %d bytes\n", key, len(data))
+	r.logger.Debug(ctx, "saving token to redis", "key", key, "size", len(data))
 
 	err = r.client.Set(ctx, key, data, expiration).Err()
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to save token to Redis: %v\n", err)
 		return err
 	}
 
-	fmt.Printf("DEBUG: Token saved successfully to Redis with key: %s\n", key)
+	r.logger.Debug(ctx, "token saved to redis", "key", key)
 	return nil
 }
 
@@ -77,34 +141,153 @@ func (r *RedisStorage) SaveToken(ctx context.Context, userID, provider, serverNa
 func (r *RedisStorage) GetToken(ctx context.Context, userID, provider, serverName string) (*oauth2.Token, error) {
 	key := r.TokenKey(userID, provider, serverName)
 
-	fmt.Printf("DEBUG: Looking for token in Redis with key: %s\n", key)
+	r.logger.Debug(ctx, "looking up token in redis", "key", key)
 
 	// Test Redis connection first
 	if err := r.client.Ping(ctx).Err(); err != nil {
-		fmt.Printf("DEBUG: Redis connection test failed: %v\n", err)
 		return nil, fmt.Errorf("redis connection failed: %w", err)
 	}
 
 	data, err := r.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			fmt.Printf("DEBUG: Token not found in Redis with key: %s\n", key)
 			return nil, fmt.Errorf("token not found")
 		}
-		fmt.Printf("DEBUG: Failed to get token from Redis: %v\n", err)
 		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Token found in Redis with key: %s, data size: %d bytes\n", key, len(data))
+	r.logger.Debug(ctx, "token found in redis", "key", key, "size", len(data))
 
-	var token oauth2.Token
-	if err := json.Unmarshal([]byte(data), &token); err != nil {
-		fmt.Printf("DEBUG: Failed to unmarshal token: %v\n", err)
+	token, err := unmarshalToken([]byte(data))
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Token retrieved successfully from Redis with key: %s, access_token length: %d\n", key, len(token.AccessToken))
-	return &token, nil
+	if err := validateToken(token); err != nil {
+		r.logger.Warn(ctx, "invalid token in redis", "key", key, "error", err)
+		return nil, err
+	}
+
+	r.logger.Debug(ctx, "token retrieved from redis", "key", key, "access_token", logger.Redact(token.AccessToken))
+	return token, nil
+}
+
+// ListTokens returns all stored tokens by scanning token keys
+func (r *RedisStorage) ListTokens(ctx context.Context) ([]TokenRecord, error) {
+	var records []TokenRecord
+
+	iter := r.client.Scan(ctx, 0, "token:*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+
+		serverName, provider, userID, ok := parseTokenKey(key)
+		if !ok {
+			continue
+		}
+
+		data, err := r.client.Get(ctx, key).Result()
+		if err != nil {
+			if err == redis.Nil {
+				// Key expired between scan and get
+				continue
+			}
+			return nil, fmt.Errorf("failed to get token %s: %w", key, err)
+		}
+
+		token, err := unmarshalToken([]byte(data))
+		if err != nil {
+			r.logger.Warn(ctx, "skipping token with invalid data", "key", key, "error", err)
+			continue
+		}
+		if err := validateToken(token); err != nil {
+			r.logger.Warn(ctx, "skipping invalid token", "key", key, "error", err)
+			continue
+		}
+
+		records = append(records, TokenRecord{
+			UserID:     userID,
+			Provider:   provider,
+			ServerName: serverName,
+			Token:      token,
+		})
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan tokens: %w", err)
+	}
+
+	return records, nil
+}
+
+// ListProvidersForUser returns the providers a user has stored tokens for on a server
+func (r *RedisStorage) ListProvidersForUser(ctx context.Context, userID, serverName string) ([]string, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	pattern := fmt.Sprintf("token:%s:*:%s", escapeGlob(serverName), escapeGlob(userID))
+
+	var providers []string
+	iter := r.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keyServer, provider, keyUser, ok := parseTokenKey(iter.Val())
+		// The wildcard can span colons, so check the parsed key matches exactly
+		if !ok || keyServer != serverName || keyUser != userID {
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan tokens: %w", err)
+	}
+
+	return providers, nil
+}
+
+// ListAccounts returns the accounts a user has stored tokens for on a provider, the empty string
+// for the default account
+func (r *RedisStorage) ListAccounts(ctx context.Context, userID, provider, serverName string) ([]string, error) {
+	if serverName == "" {
+		serverName = "default"
+	}
+
+	pattern := fmt.Sprintf("token:%s:%s:%s*", escapeGlob(serverName), escapeGlob(provider), escapeGlob(userID))
+
+	var accounts []string
+	iter := r.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keyServer, keyProvider, keyUser, ok := parseTokenKey(iter.Val())
+		if !ok || keyServer != serverName || keyProvider != provider {
+			continue
+		}
+		// The wildcard also matches other users whose ID starts with this one
+		if accountID, ok := accountOf(keyUser, userID); ok {
+			accounts = append(accounts, accountID)
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan tokens: %w", err)
+	}
+
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// escapeGlob escapes Redis glob pattern characters in a key segment
+func escapeGlob(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+	return replacer.Replace(s)
+}
+
+// parseTokenKey splits a token key of the form token:{server}:{provider}:{user}
+func parseTokenKey(key string) (serverName, provider, userID string, ok bool) {
+	parts := strings.SplitN(key, ":", 4)
+	if len(parts) != 4 || parts[0] != "token" {
+		return "", "", "", false
+	}
+	return parts[1], parts[2], parts[3], true
 }
 
 // DeleteToken removes an OAuth token from Redis
@@ -113,6 +296,94 @@ func (r *RedisStorage) DeleteToken(ctx context.Context, userID, provider, server
 	return r.client.Del(ctx, key).Err()
 }
 
+// GetReachSamples retrieves a user's reach baseline samples from Redis
+func (r *RedisStorage) GetReachSamples(ctx context.Context, userID, provider, serverName string) (map[string]ReachSample, error) {
+	key := r.ReachKey(userID, provider, serverName)
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return map[string]ReachSample{}, nil
+		}
+		return nil, fmt.Errorf("failed to get reach samples: %w", err)
+	}
+
+	samples := make(map[string]ReachSample)
+	if err := json.Unmarshal([]byte(data), &samples); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reach samples: %w", err)
+	}
+
+	return samples, nil
+}
+
+// SaveReachSamples stores a user's reach baseline samples in Redis
+func (r *RedisStorage) SaveReachSamples(ctx context.Context, userID, provider, serverName string, samples map[string]ReachSample) error {
+	key := r.ReachKey(userID, provider, serverName)
+
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reach samples: %w", err)
+	}
+
+	return r.client.Set(ctx, key, data, ReachSampleTTL).Err()
+}
+
+// GetCachedStats retrieves a post's cached stats from Redis, reporting whether they were found
+func (r *RedisStorage) GetCachedStats(ctx context.Context, provider, mediaID string) (types.StatsData, bool, error) {
+	data, err := r.client.Get(ctx, r.StatsKey(provider, mediaID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return types.StatsData{}, false, nil
+		}
+		return types.StatsData{}, false, fmt.Errorf("failed to get cached stats: %w", err)
+	}
+
+	var stats types.StatsData
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return types.StatsData{}, false, fmt.Errorf("failed to unmarshal cached stats: %w", err)
+	}
+
+	return stats, true, nil
+}
+
+// SaveCachedStats caches a post's stats in Redis for ttl
+func (r *RedisStorage) SaveCachedStats(ctx context.Context, provider, mediaID string, stats types.StatsData, ttl time.Duration) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	return r.client.Set(ctx, r.StatsKey(provider, mediaID), data, ttl).Err()
+}
+
+// GetCachedUserInfo retrieves a user's cached profile from Redis, reporting whether it was found
+func (r *RedisStorage) GetCachedUserInfo(ctx context.Context, userID, provider, serverName string) (types.UserInfo, bool, error) {
+	data, err := r.client.Get(ctx, r.UserInfoKey(userID, provider, serverName)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return types.UserInfo{}, false, nil
+		}
+		return types.UserInfo{}, false, fmt.Errorf("failed to get cached user info: %w", err)
+	}
+
+	var userInfo types.UserInfo
+	if err := json.Unmarshal(data, &userInfo); err != nil {
+		return types.UserInfo{}, false, fmt.Errorf("failed to unmarshal cached user info: %w", err)
+	}
+
+	return userInfo, true, nil
+}
+
+// SaveCachedUserInfo caches a user's profile in Redis for ttl
+func (r *RedisStorage) SaveCachedUserInfo(ctx context.Context, userID, provider, serverName string, userInfo types.UserInfo, ttl time.Duration) error {
+	data, err := json.Marshal(userInfo)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user info: %w", err)
+	}
+
+	return r.client.Set(ctx, r.UserInfoKey(userID, provider, serverName), data, ttl).Err()
+}
+
 // SavePKCEVerifier stores a PKCE verifier in Redis with short expiration
 func (r *RedisStorage) SavePKCEVerifier(ctx context.Context, state, verifier string) error {
 	key := r.PKCEKey(state)
@@ -120,33 +391,29 @@ func (r *RedisStorage) SavePKCEVerifier(ctx context.Context, state, verifier str
 	// PKCE verifiers should expire quickly (30 minutes to allow for user interaction time)
 	expiration := 30 * time.Minute
 
-	fmt.Printf("DEBUG: Saving PKCE verifier to Redis with key: %s, verifier length: %d\n", key, len(verifier))
+	r.logger.Debug(ctx, "saving PKCE verifier to redis", "key", key, "verifier", logger.Redact(verifier))
 
 	// Test Redis connection first
 	if err := r.client.Ping(ctx).Err(); err != nil {
-		fmt.Printf("DEBUG: Redis connection test failed: %v\n", err)
 		return fmt.Errorf("redis connection failed: %w", err)
 	}
 
 	err := r.client.Set(ctx, key, verifier, expiration).Err()
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to save PKCE verifier to Redis: %v\n", err)
 		return err
 	}
 
 	// Verify the save was successful
 	savedVerifier, err := r.client.Get(ctx, key).Result()
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to verify PKCE verifier save: %v\n", err)
 		return fmt.Errorf("failed to verify PKCE verifier save: %w", err)
 	}
 
 	if savedVerifier != verifier {
-		fmt.Printf("DEBUG: PKCE verifier mismatch after save\n")
 		return fmt.Errorf("PKCE verifier mismatch after save")
 	}
 
-	fmt.Printf("DEBUG: PKCE verifier saved and verified successfully to Redis with key: %s\n", key)
+	r.logger.Debug(ctx, "PKCE verifier saved to redis", "key", key)
 	return nil
 }
 
@@ -154,7 +421,7 @@ func (r *RedisStorage) SavePKCEVerifier(ctx context.Context, state, verifier str
 func (r *RedisStorage) GetAndDeletePKCEVerifier(ctx context.Context, state string) (string, error) {
 	key := r.PKCEKey(state)
 
-	fmt.Printf("DEBUG: Looking for PKCE verifier in Redis with key: %s\n", key)
+	r.logger.Debug(ctx, "looking up PKCE verifier in redis", "key", key)
 
 	// Use Redis pipeline for atomic get and delete
 	pipe := r.client.Pipeline()
@@ -163,37 +430,284 @@ func (r *RedisStorage) GetAndDeletePKCEVerifier(ctx context.Context, state strin
 
 	_, err := pipe.Exec(ctx)
 	if err != nil && err != redis.Nil {
-		fmt.Printf("DEBUG: Failed to execute Redis pipeline for PKCE verifier: %v\n", err)
 		return "", fmt.Errorf("failed to get PKCE verifier: %w", err)
 	}
 
 	verifier, err := getCmd.Result()
 	if err != nil {
 		if err == redis.Nil {
-			fmt.Printf("DEBUG: PKCE verifier not found in Redis with key: %s\n", key)
 			return "", fmt.Errorf("PKCE verifier not found or expired")
 		}
-		fmt.Printf("DEBUG: Failed to get PKCE verifier from Redis: %v\n", err)
 		return "", fmt.Errorf("failed to get PKCE verifier: %w", err)
 	}
 
-	fmt.Printf("DEBUG: PKCE verifier found in Redis with key: %s, verifier length: %d\n", key, len(verifier))
+	r.logger.Debug(ctx, "PKCE verifier found in redis", "key", key, "verifier", logger.Redact(verifier))
 
 	// Check if delete was successful
 	if delCmd.Err() != nil {
-		fmt.Printf("DEBUG: Failed to delete PKCE verifier from Redis: %v\n", delCmd.Err())
 		return "", fmt.Errorf("failed to delete PKCE verifier: %w", delCmd.Err())
 	}
 
-	fmt.Printf("DEBUG: PKCE verifier retrieved and deleted successfully from Redis with key: %s\n", key)
+	r.logger.Debug(ctx, "PKCE verifier retrieved and deleted from redis", "key", key)
 	return verifier, nil
 }
 
+// SaveOAuthState records an issued OAuth state so its callback can be verified
+func (r *RedisStorage) SaveOAuthState(ctx context.Context, state string) error {
+	return r.client.Set(ctx, r.OAuthStateKey(state), "1", OAuthStateTTL).Err()
+}
+
+// ConsumeOAuthState verifies an OAuth state was issued and deletes it so it can't be replayed
+func (r *RedisStorage) ConsumeOAuthState(ctx context.Context, state string) error {
+	// DEL is atomic, so only one callback can consume a given state
+	deleted, err := r.client.Del(ctx, r.OAuthStateKey(state)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+	if deleted == 0 {
+		return ErrOAuthStateNotFound
+	}
+	return nil
+}
+
+// SaveScheduledPost stores a pending scheduled post and indexes it by its scheduled time
+func (r *RedisStorage) SaveScheduledPost(ctx context.Context, post *ScheduledPost) error {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled post: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, r.ScheduledPostKey(post.ID), data, 0)
+	pipe.ZAdd(ctx, scheduledDueKey, redis.Z{Score: float64(post.ScheduledAt), Member: post.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save scheduled post: %w", err)
+	}
+	return nil
+}
+
+// ListDueScheduledPosts returns the pending scheduled posts whose time has come
+func (r *RedisStorage) ListDueScheduledPosts(ctx context.Context, now time.Time) ([]*ScheduledPost, error) {
+	ids, err := r.client.ZRangeByScore(ctx, scheduledDueKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled posts: %w", err)
+	}
+
+	var posts []*ScheduledPost
+	for _, id := range ids {
+		data, err := r.client.Get(ctx, r.ScheduledPostKey(id)).Result()
+		if err != nil {
+			if err == redis.Nil {
+				// Taken by another instance since the range query
+				continue
+			}
+			return nil, fmt.Errorf("failed to get scheduled post: %w", err)
+		}
+
+		var post ScheduledPost
+		if err := json.Unmarshal([]byte(data), &post); err != nil {
+			r.logger.Warn(ctx, "skipping scheduled post with invalid data", "job_id", id, "error", err)
+			continue
+		}
+		posts = append(posts, &post)
+	}
+
+	return posts, nil
+}
+
+// TakeScheduledPost removes a pending scheduled post and returns it.
+// Only one caller can take a given post, so it is used both to claim a post for publishing and to cancel it.
+func (r *RedisStorage) TakeScheduledPost(ctx context.Context, jobID string) (*ScheduledPost, error) {
+	// ZREM is atomic, whoever removes the ID from the due set owns the post
+	removed, err := r.client.ZRem(ctx, scheduledDueKey, jobID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take scheduled post: %w", err)
+	}
+	if removed == 0 {
+		return nil, ErrScheduledPostNotFound
+	}
+
+	key := r.ScheduledPostKey(jobID)
+	pipe := r.client.Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err == redis.Nil {
+			return nil, ErrScheduledPostNotFound
+		}
+		return nil, fmt.Errorf("failed to take scheduled post: %w", err)
+	}
+
+	var post ScheduledPost
+	if err := json.Unmarshal([]byte(getCmd.Val()), &post); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduled post: %w", err)
+	}
+	return &post, nil
+}
+
+// SaveFailedScheduledPost adds a scheduled post that failed to publish to the dead-letter list
+func (r *RedisStorage) SaveFailedScheduledPost(ctx context.Context, post *FailedScheduledPost) error {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return fmt.Errorf("failed to marshal failed scheduled post: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, r.FailedScheduledPostKey(post.ID), data, 0)
+	pipe.ZAdd(ctx, scheduledFailedKey, redis.Z{Score: float64(post.FailedAt), Member: post.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save failed scheduled post: %w", err)
+	}
+	return nil
+}
+
+// ListFailedScheduledPosts returns the dead-letter list, most recently failed first
+func (r *RedisStorage) ListFailedScheduledPosts(ctx context.Context) ([]*FailedScheduledPost, error) {
+	ids, err := r.client.ZRevRange(ctx, scheduledFailedKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed scheduled posts: %w", err)
+	}
+
+	var posts []*FailedScheduledPost
+	for _, id := range ids {
+		data, err := r.client.Get(ctx, r.FailedScheduledPostKey(id)).Result()
+		if err != nil {
+			if err == redis.Nil {
+				// Retried since the range query
+				continue
+			}
+			return nil, fmt.Errorf("failed to get failed scheduled post: %w", err)
+		}
+
+		var post FailedScheduledPost
+		if err := json.Unmarshal([]byte(data), &post); err != nil {
+			r.logger.Warn(ctx, "skipping failed scheduled post with invalid data", "job_id", id, "error", err)
+			continue
+		}
+		posts = append(posts, &post)
+	}
+
+	return posts, nil
+}
+
+// TakeFailedScheduledPost removes a post from the dead-letter list and returns it, only one caller can take it
+func (r *RedisStorage) TakeFailedScheduledPost(ctx context.Context, jobID string) (*FailedScheduledPost, error) {
+	removed, err := r.client.ZRem(ctx, scheduledFailedKey, jobID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take failed scheduled post: %w", err)
+	}
+	if removed == 0 {
+		return nil, ErrScheduledPostNotFound
+	}
+
+	key := r.FailedScheduledPostKey(jobID)
+	pipe := r.client.Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err == redis.Nil {
+			return nil, ErrScheduledPostNotFound
+		}
+		return nil, fmt.Errorf("failed to take failed scheduled post: %w", err)
+	}
+
+	var post FailedScheduledPost
+	if err := json.Unmarshal([]byte(getCmd.Val()), &post); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal failed scheduled post: %w", err)
+	}
+	return &post, nil
+}
+
+// ReserveIdempotencyKey stores record under an unused Idempotency-Key and returns nil, or returns
+// the record already stored under the key without changing it
+func (r *RedisStorage) ReserveIdempotencyKey(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	for {
+		// SETNX is atomic, so only one request can reserve a given key
+		reserved, err := r.client.SetNX(ctx, r.IdempotencyKey(key), data, ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		existing, err := r.client.Get(ctx, r.IdempotencyKey(key)).Bytes()
+		if err != nil {
+			if err == redis.Nil {
+				// Expired or released since SETNX, try to reserve it again
+				continue
+			}
+			return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+		}
+
+		var stored IdempotencyRecord
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+		}
+		return &stored, nil
+	}
+}
+
+// SaveIdempotencyRecord stores the record of an Idempotency-Key for ttl, replacing any reservation
+func (r *RedisStorage) SaveIdempotencyRecord(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	return r.client.Set(ctx, r.IdempotencyKey(key), data, ttl).Err()
+}
+
+// DeleteIdempotencyRecord releases an Idempotency-Key so it can be used again
+func (r *RedisStorage) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.IdempotencyKey(key)).Err()
+}
+
+// releaseLockScript deletes a lock only if it is still held by the given owner, so an owner whose
+// lock expired can't release the lock someone else acquired since
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLock takes the named lock for owner until ttl passes or it is released, returning false
+// if someone else holds it
+func (r *RedisStorage) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := r.client.SetNX(ctx, r.LockKey(name), owner, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	return acquired, nil
+}
+
+// ReleaseLock releases the named lock if owner still holds it
+func (r *RedisStorage) ReleaseLock(ctx context.Context, name, owner string) error {
+	if err := releaseLockScript.Run(ctx, r.client, []string{r.LockKey(name)}, owner).Err(); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
 // Close closes the Redis connection
 func (r *RedisStorage) Close() error {
 	return r.client.Close()
 }
 
+// Cleanup is a no-op, Redis expires keys through their TTL
+func (r *RedisStorage) Cleanup(ctx context.Context) error {
+	return nil
+}
+
 // Health checks Redis connection health
 func (r *RedisStorage) Health(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
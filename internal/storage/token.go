@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"golang.org/x/oauth2"
+)
+
+// storedTokenExtras are the provider-specific token response fields kept with a stored token:
+// TikTok's open_id, Google's id_token, Instagram's user_id and the scopes actually granted
+var storedTokenExtras = []string{"open_id", "id_token", "user_id", "scope"}
+
+// StoredToken is how an OAuth token is serialized in storage.
+// oauth2.Token drops its Extra fields when marshaled, so the ones in storedTokenExtras are
+// stored alongside the standard fields and restored on read. Records written before extras
+// were stored decode with Extras empty.
+type StoredToken struct {
+	oauth2.Token
+	Extras map[string]any `json:"extras,omitempty"`
+}
+
+// NewStoredToken captures a token and the extras we care about for storage
+func NewStoredToken(token *oauth2.Token) *StoredToken {
+	stored := &StoredToken{Token: *token}
+
+	for _, key := range storedTokenExtras {
+		value := token.Extra(key)
+		if value == nil || value == "" {
+			continue
+		}
+		if stored.Extras == nil {
+			stored.Extras = make(map[string]any)
+		}
+		stored.Extras[key] = value
+	}
+
+	return stored
+}
+
+// OAuthToken returns the stored token with its extras attached
+func (s *StoredToken) OAuthToken() *oauth2.Token {
+	token := s.Token
+	if len(s.Extras) == 0 {
+		return &token
+	}
+	return token.WithExtra(s.Extras)
+}
+
+// marshalToken serializes a token for storage
+func marshalToken(token *oauth2.Token) ([]byte, error) {
+	return json.Marshal(NewStoredToken(token))
+}
+
+// unmarshalToken decodes a stored token
+func unmarshalToken(data []byte) (*oauth2.Token, error) {
+	var stored StoredToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return stored.OAuthToken(), nil
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultTokenTTL is used when neither the configuration nor the token itself bounds its lifetime
+	DefaultTokenTTL = 30 * 24 * time.Hour
+
+	// tokenExpiryBuffer keeps a token around past its expiry so it can still be refreshed or inspected
+	tokenExpiryBuffer = 24 * time.Hour
+)
+
+// TokenTTLFunc returns the configured TTL for a provider's tokens on a server, or 0 if none is configured
+type TokenTTLFunc func(provider, serverName string) time.Duration
+
+// tokenTTL returns how long a token should be stored. A configured TTL wins; otherwise tokens
+// whose lifetime is bounded by their own expiry are kept until then plus a buffer.
+func tokenTTL(ttlFunc TokenTTLFunc, provider, serverName string, token *oauth2.Token) time.Duration {
+	if ttlFunc != nil {
+		if ttl := ttlFunc(provider, serverName); ttl > 0 {
+			return ttl
+		}
+	}
+
+	// A separate refresh token usually outlives the access token's expiry
+	hasRefreshToken := token.RefreshToken != "" && token.RefreshToken != token.AccessToken
+	if !hasRefreshToken && !token.Expiry.IsZero() {
+		if ttl := time.Until(token.Expiry) + tokenExpiryBuffer; ttl > tokenExpiryBuffer {
+			return ttl
+		}
+		return tokenExpiryBuffer
+	}
+
+	return DefaultTokenTTL
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrInvalidToken is returned when a stored token is corrupted or incomplete and the user has to re-authorize
+var ErrInvalidToken = errors.New("stored token is invalid")
+
+// Bounds of a plausible token expiry, anything outside them comes from a corrupted record
+var (
+	minTokenExpiry   = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTokenLifetime = 10 * 365 * 24 * time.Hour
+)
+
+// validateToken checks that a decoded token has the fields needed to authenticate requests
+func validateToken(token *oauth2.Token) error {
+	if token.AccessToken == "" {
+		return fmt.Errorf("%w: access token is empty", ErrInvalidToken)
+	}
+
+	// A zero expiry means the token doesn't expire
+	if !token.Expiry.IsZero() {
+		if token.Expiry.Before(minTokenExpiry) || token.Expiry.After(time.Now().Add(maxTokenLifetime)) {
+			return fmt.Errorf("%w: implausible expiry %s", ErrInvalidToken, token.Expiry.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
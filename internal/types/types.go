@@ -2,34 +2,81 @@ package types
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 )
 
 // ShareRequest represents a request to share content to a social platform
 type ShareRequest struct {
-	Provider   string   `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"`   // 平台名称 可选值：youtube x facebook tiktok instagram
-	UserID     string   `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                          // 用户ID 必填 同一服务名称下user_id唯一
-	ServerName string   `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                         // 服务名称 必填
-	Content    string   `json:"content,omitempty" binding:"max=280" example:"Hello World!"`                          // text content
-	MediaURL   string   `json:"media_url,omitempty" binding:"omitempty,url" example:"https://example.com/image.jpg"` // url to media (backend should download & upload)
-	Title      string   `json:"title,omitempty" binding:"max=100" example:"My Post"`
-	Desc       string   `json:"description,omitempty" binding:"max=500" example:"This is a description"`
+	Provider   string   `json:"provider" binding:"required,provider" example:"x"`                                          // 平台名称，须为已注册的平台
+	UserID     string   `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`                     // 用户ID 必填 同一服务名称下user_id唯一
+	AccountID  string   `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"`               // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string   `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                               // 服务名称 必填
+	Content    string   `json:"content,omitempty" binding:"max=63206" example:"Hello World!"`                              // text content, X splits content over 280 chars into a thread, other platforms check their own limits
+	MediaURL   string   `json:"media_url,omitempty" binding:"omitempty,media_url" example:"https://example.com/image.jpg"` // url to media (backend should download & upload)，配置了media.base_url时可以是相对路径
+	Title      string   `json:"title,omitempty" binding:"max=300" example:"My Post"`
+	Desc       string   `json:"description,omitempty" binding:"max=5000" example:"This is a description"`
 	Tags       []string `json:"tags,omitempty" binding:"max=10" example:"hello,world"`
 	Privacy    string   `json:"privacy,omitempty" binding:"omitempty,oneof=public private unlisted friends followers" example:"public"`
+
+	MediaAltText string `json:"media_alt_text,omitempty" binding:"max=1000" example:"A golden retriever catching a frisbee"` // 媒体的替代文本，供读屏软件朗读（X、Instagram单图），其他平台忽略
+
+	DisableComments bool `json:"disable_comments,omitempty" example:"false"` // 禁用评论（仅Instagram）
+	HideLikeCounts  bool `json:"hide_like_counts,omitempty" example:"false"` // 隐藏点赞和播放数（仅Instagram）
+
+	NotifySubscribers *bool `json:"notify_subscribers,omitempty" example:"true"` // 上传后是否通知订阅者（仅YouTube），默认通知，批量上传时可关闭
+
+	MediaURLs []string `json:"media_urls,omitempty" binding:"omitempty,max=10,dive,media_url" example:"https://example.com/1.jpg,https://example.com/2.jpg"` // 多图轮播（仅Instagram），2-10张
+
+	Thread             []string `json:"thread,omitempty" binding:"omitempty,max=25,dive,min=1,max=4000" example:"second tweet,third tweet"` // 串推后续内容（仅X），每条按X的加权长度不超过280（中日韩等字符计2，链接计23）
+	NumberThread       bool     `json:"number_thread,omitempty" example:"false"`                                                            // 串推时在每条末尾追加编号，如 (1/5)（仅X）
+	ThreadNumberFormat string   `json:"thread_number_format,omitempty" binding:"max=30" example:" ({n}/{total})"`                           // 编号格式，{n}为序号，{total}为总数，默认 " ({n}/{total})"
+
+	ReplySettings string `json:"reply_settings,omitempty" binding:"omitempty,oneof=everyone mentionedUsers following" example:"everyone"` // 谁可以回复（仅X）：everyone（默认）所有人，mentionedUsers 被提及的用户，following 关注的用户
+
+	BoardID string `json:"board_id,omitempty" binding:"max=64" example:"549755885175"` // Pinterest画板ID（仅Pinterest，必填）
+
+	Subreddit string `json:"subreddit,omitempty" binding:"max=21" example:"golang"` // 发布到的子版块名称（仅Reddit，必填），有media_url时发布链接帖，否则以content发布文字帖
+
+	PageID string `json:"page_id,omitempty" binding:"omitempty,numeric,max=32" example:"102938475610293"` // Facebook主页ID（仅Facebook），提供时以主页身份发布到该主页，用户须管理该主页；不填时发布到用户自己的动态
+
+	ChannelID  string `json:"channel_id,omitempty" binding:"max=32" example:"1234567890123456789"`                                       // 发布到的频道ID（仅Discord），由服务配置的机器人发布，与webhook_url二选一
+	WebhookURL string `json:"webhook_url,omitempty" binding:"omitempty,url,max=2048" example:"https://discord.com/api/webhooks/123/abc"` // 频道Webhook地址（仅Discord），提供时通过Webhook发布，优先于channel_id
+
+	InstanceURL string `json:"instance_url,omitempty" binding:"omitempty,url,max=255" example:"https://mastodon.social"` // Mastodon实例地址（仅Mastodon），须与服务配置的实例一致，不填时使用配置的实例
+
+	CallbackURL string `json:"callback_url,omitempty" binding:"omitempty,url,max=2048" example:"https://example.com/hooks/share"` // 分享成功后将ShareResponse以POST方式推送到该地址，失败最多重试3次
+
+	ValidateLink bool `json:"validate_link,omitempty" example:"false"` // 发布前检查内容中首个链接是否可访问及是否有卡片预览标签（仅X），问题以警告返回
+
+	VerifyAfterShare bool `json:"verify_after_share,omitempty" example:"false"` // 发布后按ID回读帖子确认已上线，会增加一次平台请求，结果在verification中返回
+
+	ScheduledAt int64 `json:"scheduled_at,omitempty" binding:"omitempty,min=0" example:"1767225600"` // 定时发布的Unix时间戳（秒），为将来时间时返回job_id并在到点后发布；Facebook由平台原生定时发布，直接返回帖子ID，仅支持发布到主页（须填page_id）
+
+	DryRun bool `json:"dry_run,omitempty" example:"false"` // 仅预检：校验请求、token和平台对内容/媒体的要求，返回将要发布的内容，不实际发布
+
+	Async bool `json:"async,omitempty" example:"false"` // 后台发布：立即返回status为queued的job_id，通过GET /api/share/progress/{job_id}（SSE）获取上传进度和发布结果
 }
 
 // StatsRequest represents a request to get statistics from a social platform
 type StatsRequest struct {
-	Provider   string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称 可选值：youtube x facebook tiktok instagram
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                        // 用户ID 必填 同一服务名称下user_id唯一
-	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`
-	MediaID    string `json:"media_id,omitempty" binding:"max=100" example:"1234567890"`
+	Provider     string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称，须为已注册的平台
+	UserID       string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID 必填 同一服务名称下user_id唯一
+	AccountID    string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName   string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`
+	MediaID      string `json:"media_id,omitempty" binding:"max=100" example:"1234567890"`
+	CheckReach   bool   `json:"check_reach,omitempty" example:"false"`   // 对比历史基线检查曝光是否异常偏低（仅X），结果以警告返回
+	ForceRefresh bool   `json:"force_refresh,omitempty" example:"false"` // 跳过统计缓存，直接从平台获取
 }
 
 // StartAuthRequest represents a request to start OAuth authentication
 type StartAuthRequest struct {
-	Provider    string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称 可选值：youtube x facebook tiktok instagram
-	UserID      string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                        // 用户ID 必填 同一服务名称下user_id唯一
+	Provider    string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称，须为已注册的平台
+	UserID      string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID 必填 同一服务名称下user_id唯一
+	AccountID   string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
 	RedirectURI string `json:"redirect_uri" binding:"required,url" example:"https://test-pubproject.wondera.io/static/callback.html"`
 	ServerName  string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`
 }
@@ -37,9 +84,10 @@ type StartAuthRequest struct {
 // CallbackRequest represents a request for OAuth callback
 // 前端收到OAuth回调后，调用此接口处理授权码交换
 type CallbackRequest struct {
-	Provider    string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"`                      // 平台名称 可选值：youtube x facebook tiktok instagram
+	Provider    string `json:"provider" binding:"required,provider" example:"x"`                                                       // 平台名称，须为已注册的平台
 	ServerName  string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                                            // 服务器名称
-	UserID      string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                                             // 服务内部用户ID 必填
+	UserID      string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`                                  // 服务内部用户ID 必填
+	AccountID   string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"`                            // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
 	State       string `json:"state" binding:"required,min=1" example:"encoded_state_string"`                                          // 状态参数，包含用户ID等信息
 	Code        string `json:"code" binding:"required,min=1" example:"authorization_code"`                                             // 授权码
 	RedirectURI string `json:"redirect_uri" binding:"required,url" example:"hhttps://test-pubproject.wondera.io/static/callback.html"` // 重定向URI
@@ -57,6 +105,7 @@ type StartAuthResponse struct {
 type CallbackResponse struct {
 	Provider   string `json:"provider" example:"x"`
 	UserID     string `json:"user_id" example:"user123"`
+	AccountID  string `json:"account_id,omitempty" example:"brand"` // 授权保存到的账号ID，默认账号时不返回
 	ServerName string `json:"server_name" example:"myapp"`
 	ExpiresAt  int64  `json:"expires_at" example:"1704067199"` // 时间戳格式
 	ReferAt    int64  `json:"refer_at" example:"1704067199"`   // 时间戳格式
@@ -72,6 +121,80 @@ type ShareResponse struct {
 	MediaURL   string   `json:"media_url,omitempty" example:"https://example.com/image.jpg"`
 	Tags       []string `json:"tags,omitempty" example:"social,oauth,test"`
 	MediaID    string   `json:"media_id,omitempty" example:"1234567890"` // Tweet ID or post ID for status query
+	Status     string   `json:"status" example:"published"`              // 发布状态：published, processing, scheduled, queued, failed, dry_run
+	Warnings   []string `json:"warnings,omitempty"`                      // 不影响发布的警告，如链接预览问题
+	PostRef    *PostRef `json:"post_ref,omitempty"`                      // 结构化的内容ID，包含平台特定的ID组成部分
+
+	ProcessingError *ProcessingError `json:"processing_error,omitempty"` // 平台上传后处理失败的原因，仅status为failed时返回
+
+	UploadedBytes int64 `json:"uploaded_bytes,omitempty" example:"10485760"` // 上传到平台的媒体字节数，仅在本服务下载并上传媒体时返回（YouTube、TikTok、X），平台按URL自行拉取媒体时不返回
+
+	Verification *PostVerification `json:"verification,omitempty"` // 发布后校验结果，仅在verify_after_share时返回
+
+	JobID       string `json:"job_id,omitempty" example:"k3J9xQ2mP7vL4nR8"` // 任务ID：status为scheduled时为定时发布任务ID，可用于取消；status为queued时为后台发布任务ID，可用于获取进度
+	ScheduledAt int64  `json:"scheduled_at,omitempty" example:"1767225600"` // 计划发布时间
+}
+
+// PostVerification is the result of reading a post back after sharing it
+type PostVerification struct {
+	Verified  bool   `json:"verified" example:"true"`                      // 帖子是否已确认在平台上可见
+	Error     string `json:"error,omitempty" example:"post is processing"` // 未确认的原因
+	CheckedAt int64  `json:"checked_at" example:"1704067199"`              // 校验时间戳
+}
+
+// PostRef is a structured reference to a post, splitting provider-specific composite IDs into their parts
+type PostRef struct {
+	Provider   string            `json:"provider" example:"facebook"`
+	ID         string            `json:"id" example:"123456789_987654321"` // 平台返回的原始ID，与media_id一致
+	Components map[string]string `json:"components,omitempty"`             // ID的组成部分，如Facebook的page_id和post_id
+}
+
+// Post status values reported after sharing
+const (
+	PostStatusPublished  = "published"
+	PostStatusProcessing = "processing"
+	PostStatusScheduled  = "scheduled"
+	PostStatusQueued     = "queued" // the share runs as a background job, follow it by job ID
+	PostStatusFailed     = "failed"
+	PostStatusDryRun     = "dry_run" // nothing was posted, the request passed pre-flight checks
+	PostStatusSkipped    = "skipped" // nothing was posted, a batch stopped at an earlier failure
+)
+
+// PostStatusRequest represents a request to get the publish status of a shared post
+type PostStatusRequest struct {
+	Provider   string `json:"provider" binding:"required,provider" example:"youtube"`                      // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
+	MediaID    string `json:"media_id" binding:"required,max=100" example:"dQw4w9WgXcQ"`                   // 分享返回的媒体ID
+}
+
+// PostStatusResponse represents the publish status of a shared post
+type PostStatusResponse struct {
+	Provider   string `json:"provider" example:"youtube"`
+	UserID     string `json:"user_id" example:"user123"`
+	ServerName string `json:"server_name" example:"myapp"`
+	MediaID    string `json:"media_id" example:"dQw4w9WgXcQ"`
+	Status     string `json:"status" example:"processing"` // 发布状态：published, processing, scheduled, failed
+
+	ProcessingError *ProcessingError `json:"processing_error,omitempty"` // 平台处理失败或拒绝发布的原因，仅status为failed时返回
+}
+
+// PostRequest represents a request to get the current state of a single post
+type PostRequest struct {
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
+	MediaID    string `json:"media_id" binding:"required,max=100" example:"1234567890"`                    // 分享返回的媒体ID
+}
+
+// PostResponse represents the current state of a single post
+type PostResponse struct {
+	Provider   string `json:"provider" example:"x"`
+	UserID     string `json:"user_id" example:"user123"`
+	ServerName string `json:"server_name" example:"myapp"`
+	Post       Post   `json:"post"` // 帖子内容、媒体和统计信息
 }
 
 // StatsData represents the statistics data structure
@@ -81,6 +204,15 @@ type StatsData struct {
 	Replies  int `json:"replies" example:"25"`
 	Views    int `json:"views,omitempty" example:"1000"`
 	Shares   int `json:"shares,omitempty" example:"10"`
+
+	Score       int     `json:"score,omitempty" example:"42"`          // 得分，赞成减反对（仅Reddit）
+	UpvoteRatio float64 `json:"upvote_ratio,omitempty" example:"0.95"` // 赞成比例（仅Reddit）
+
+	Extra map[string]int `json:"extra,omitempty"` // 平台特有的附加指标，如X的impression_count、Instagram的reach和saved、YouTube的estimatedMinutesWatched，平台未返回的指标不包含
+
+	HasStats       bool     `json:"has_stats" example:"true"`                  // 是否成功获取到统计数据，为false时各项计数没有意义
+	Partial        bool     `json:"partial,omitempty" example:"false"`         // 平台只返回了部分指标
+	MissingMetrics []string `json:"missing_metrics,omitempty" example:"views"` // 平台本次未返回的指标，区别于真实的0
 }
 
 // StatsResponse represents the response for statistics
@@ -90,21 +222,47 @@ type StatsResponse struct {
 	ServerName string    `json:"server_name" example:"myapp"`
 	MediaID    string    `json:"media_id" example:"1234567890"`
 	Stats      StatsData `json:"stats"`
+	Cached     bool      `json:"cached,omitempty" example:"false"` // 统计来自缓存
+	Warnings   []string  `json:"warnings,omitempty"`               // 曝光检查等不影响结果的警告
 }
 
 // APIResponse represents a standard API response
 type APIResponse struct {
-	Status    string `json:"status"`
-	Message   string `json:"message,omitempty"`
-	Data      any    `json:"data,omitempty"`
-	RequestID string `json:"request_id,omitempty"`
+	Status    string   `json:"status"`
+	Message   string   `json:"message,omitempty"`
+	Data      any      `json:"data,omitempty"`
+	RequestID string   `json:"request_id,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"` // 不影响结果的警告，如内容被截断、只返回了部分统计
+}
+
+// Readiness component states
+const (
+	ComponentStatusUp      = "up"
+	ComponentStatusDown    = "down"
+	ComponentStatusSkipped = "skipped"
+)
+
+// ComponentStatus is the readiness of a single dependency
+type ComponentStatus struct {
+	Status   string `json:"status" example:"up"`                                 // up、down或skipped
+	Critical bool   `json:"critical" example:"true"`                             // 关键组件不可用时就绪检查返回503
+	Error    string `json:"error,omitempty" example:"redis: connection refused"` // 不可用的原因
+}
+
+// ReadinessResponse represents the response of the readiness check
+type ReadinessResponse struct {
+	Ready      bool                       `json:"ready" example:"true"`
+	Timestamp  int64                      `json:"timestamp" example:"1704067199"`
+	Components map[string]ComponentStatus `json:"components"` // 按组件名称，平台可达性为 provider:<平台>，熔断器为 breaker:<平台>
 }
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error     string `json:"error"`
-	Code      string `json:"code,omitempty"`
-	RequestID string `json:"request_id,omitempty"`
+	Error     string            `json:"error"`
+	Code      string            `json:"code,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty" example:"provider:provider must be a supported platform"` // 请求校验失败时各字段的错误信息
+	ResetAt   int64             `json:"reset_at,omitempty" example:"1704067199"`                                   // 平台限流时窗口重置的时间戳，之后可以重试
+	RequestID string            `json:"request_id,omitempty"`
 }
 
 // UserInfo represents user information from a social platform
@@ -122,9 +280,11 @@ type UserInfo struct {
 
 // GetUserInfoRequest represents a request to get user information
 type GetUserInfoRequest struct {
-	Provider   string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                        // 用户ID
-	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                       // 服务名称
+	Provider     string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	UserID       string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID    string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName   string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
+	ForceRefresh bool   `json:"force_refresh,omitempty" example:"false"`                                     // 跳过缓存，直接从平台获取
 }
 
 // GetUserInfoResponse represents the response for user information
@@ -133,6 +293,130 @@ type GetUserInfoResponse struct {
 	UserID     string   `json:"user_id" example:"user123"`
 	ServerName string   `json:"server_name" example:"myapp"`
 	UserInfo   UserInfo `json:"user_info"`
+	Cached     bool     `json:"cached,omitempty" example:"false"` // 用户信息来自缓存
+}
+
+// ErrOperationNotSupported is returned (wrapped) by platforms whose API doesn't offer an operation
+var ErrOperationNotSupported = errors.New("operation not supported by platform")
+
+// ErrMediaTooLarge is returned (wrapped) when the media to share exceeds the platform's size limit
+var ErrMediaTooLarge = errors.New("media exceeds the platform's size limit")
+
+// ErrInsufficientScope is returned (wrapped) by platforms when the provider rejects a request
+// because the token wasn't granted a scope the operation needs
+var ErrInsufficientScope = errors.New("token is missing a required scope")
+
+// ValidationError reports a well-formed request that the target platform can't accept,
+// such as content too long or incompatible options. Handlers respond with 422.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// NewValidationError creates a validation error with a formatted message
+func NewValidationError(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// IsValidationError reports whether err is or wraps a ValidationError
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+type mediaSizeLimitKey struct{}
+
+// WithMediaSizeLimit returns a context that overrides the platform's default limit on media
+// downloaded from media_url
+func WithMediaSizeLimit(ctx context.Context, maxBytes int64) context.Context {
+	return context.WithValue(ctx, mediaSizeLimitKey{}, maxBytes)
+}
+
+// MediaSizeLimit returns the media download limit set on the context, if any
+func MediaSizeLimit(ctx context.Context) (int64, bool) {
+	maxBytes, ok := ctx.Value(mediaSizeLimitKey{}).(int64)
+	return maxBytes, ok && maxBytes > 0
+}
+
+// ShareWarnings collects problems a platform worked around while sharing, such as media it had
+// to leave out, so handlers can report them alongside a successful result
+type ShareWarnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+type shareWarningsKey struct{}
+
+// WithShareWarnings returns a context that platforms can report share warnings to
+func WithShareWarnings(ctx context.Context) (context.Context, *ShareWarnings) {
+	warnings := &ShareWarnings{}
+	return context.WithValue(ctx, shareWarningsKey{}, warnings), warnings
+}
+
+// AddShareWarning records a warning on the context's collector, if there is one
+func AddShareWarning(ctx context.Context, format string, args ...any) {
+	warnings, ok := ctx.Value(shareWarningsKey{}).(*ShareWarnings)
+	if !ok {
+		return
+	}
+
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	warnings.messages = append(warnings.messages, fmt.Sprintf(format, args...))
+}
+
+// Messages returns the recorded warnings
+func (w *ShareWarnings) Messages() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.messages...)
+}
+
+// UploadCounter totals the media bytes a platform uploaded while sharing, for usage metering
+type UploadCounter struct {
+	bytes atomic.Int64
+}
+
+type uploadCounterKey struct{}
+
+// WithUploadCounter returns a context that platforms can report uploaded media bytes to
+func WithUploadCounter(ctx context.Context) (context.Context, *UploadCounter) {
+	counter := &UploadCounter{}
+	return context.WithValue(ctx, uploadCounterKey{}, counter), counter
+}
+
+// AddUploadedBytes records media bytes uploaded to the platform on the context's counter, if
+// there is one. Platforms record media once the platform has accepted it.
+func AddUploadedBytes(ctx context.Context, n int64) {
+	if counter, ok := ctx.Value(uploadCounterKey{}).(*UploadCounter); ok {
+		counter.bytes.Add(n)
+	}
+}
+
+// Bytes returns the total uploaded bytes recorded
+func (u *UploadCounter) Bytes() int64 {
+	return u.bytes.Load()
+}
+
+// UploadProgress receives how many media bytes a platform has sent so far out of the total
+type UploadProgress func(sent, total int64)
+
+type uploadProgressKey struct{}
+
+// WithUploadProgress returns a context that platforms report upload progress to while sending media
+func WithUploadProgress(ctx context.Context, progress UploadProgress) context.Context {
+	return context.WithValue(ctx, uploadProgressKey{}, progress)
+}
+
+// ReportUploadProgress reports media upload progress to the context's UploadProgress, if there is one
+func ReportUploadProgress(ctx context.Context, sent, total int64) {
+	if progress, ok := ctx.Value(uploadProgressKey{}).(UploadProgress); ok && total > 0 {
+		progress(min(sent, total), total)
+	}
 }
 
 // Platform represents a social media platform interface
@@ -140,39 +424,228 @@ type Platform interface {
 	// Share shares content to the platform and returns the media ID
 	Share(ctx context.Context, client *http.Client, req *ShareRequest) (string, error)
 
-	// GetStats retrieves statistics from the platform
+	// GetStats retrieves statistics from the platform. It returns either stats with HasStats set and
+	// a nil error, or the zero StatsData and a non-nil error, never zero stats without an error.
 	GetStats(ctx context.Context, client *http.Client, mediaID string) (StatsData, error)
 
+	// GetPost returns the current state of a single post, including its content, media and stats,
+	// or an error if it doesn't exist
+	GetPost(ctx context.Context, client *http.Client, mediaID string) (Post, error)
+
 	// GetUserInfo retrieves user information from the platform
 	GetUserInfo(ctx context.Context, client *http.Client) (UserInfo, error)
 
 	// GetRecentPosts retrieves recent posts from the platform
 	GetRecentPosts(ctx context.Context, client *http.Client, limit int, startTime, endTime int64) ([]Post, error)
 
+	// DeletePost removes a published post, returning ErrOperationNotSupported if the platform can't
+	DeletePost(ctx context.Context, client *http.Client, mediaID string) error
+
 	// GetName returns the platform name
 	GetName() string
 
+	// Capabilities reports which operations the platform supports
+	Capabilities() PlatformCapabilities
+
 	// HandleOAuthCallback handles OAuth callback for the platform
 	HandleOAuthCallback(ctx context.Context, code, state string) error
 }
 
+// PlatformCapabilities describes the operations a platform supports, so clients can adapt to it
+type PlatformCapabilities struct {
+	CanShare            bool `json:"can_share" example:"true"`             // 可以发布内容
+	CanDelete           bool `json:"can_delete" example:"true"`            // 可以删除已发布的内容
+	RequiresMedia       bool `json:"requires_media" example:"false"`       // 发布时必须提供媒体
+	SupportsStats       bool `json:"supports_stats" example:"true"`        // 可以获取统计信息
+	SupportsRecentPosts bool `json:"supports_recent_posts" example:"true"` // 可以获取最近发布的内容
+	SupportsScheduling  bool `json:"supports_scheduling" example:"true"`   // 可以通过scheduled_at定时发布
+	SupportsVideo       bool `json:"supports_video" example:"true"`        // media_url可以是视频
+}
+
+// PlatformCapabilityFilter filters the platform list by capability, unset fields match any platform
+type PlatformCapabilityFilter struct {
+	CanShare            *bool `form:"can_share"`
+	CanDelete           *bool `form:"can_delete"`
+	RequiresMedia       *bool `form:"requires_media"`
+	SupportsStats       *bool `form:"supports_stats"`
+	SupportsRecentPosts *bool `form:"supports_recent_posts"`
+	SupportsScheduling  *bool `form:"supports_scheduling"`
+	SupportsVideo       *bool `form:"supports_video"`
+}
+
+// Matches reports whether the capabilities have every flag set in the filter
+func (f PlatformCapabilityFilter) Matches(c PlatformCapabilities) bool {
+	checks := []struct {
+		want *bool
+		have bool
+	}{
+		{f.CanShare, c.CanShare},
+		{f.CanDelete, c.CanDelete},
+		{f.RequiresMedia, c.RequiresMedia},
+		{f.SupportsStats, c.SupportsStats},
+		{f.SupportsRecentPosts, c.SupportsRecentPosts},
+		{f.SupportsScheduling, c.SupportsScheduling},
+		{f.SupportsVideo, c.SupportsVideo},
+	}
+	for _, check := range checks {
+		if check.want != nil && *check.want != check.have {
+			return false
+		}
+	}
+	return true
+}
+
+// PlatformInfo describes a registered platform
+type PlatformInfo struct {
+	Name         string               `json:"name" example:"x"`
+	Capabilities PlatformCapabilities `json:"capabilities"`
+}
+
+// ListPlatformsResponse represents the response for the platform capabilities list
+type ListPlatformsResponse struct {
+	Platforms []PlatformInfo `json:"platforms"`
+}
+
+// ErrorCodeInfo describes an error code the API can return
+type ErrorCodeInfo struct {
+	Code        string `json:"code" example:"TOKEN_NOT_FOUND"`
+	Status      int    `json:"status" example:"401"`                        // 返回该错误码时的HTTP状态码
+	Description string `json:"description" example:"OAuth token not found"` // 默认错误信息，实际响应可能带有更具体的信息
+}
+
+// ListErrorCodesResponse represents the response for the error code list
+type ListErrorCodesResponse struct {
+	Errors []ErrorCodeInfo `json:"errors"`
+}
+
+// PostStatusChecker is implemented by platforms that process posts asynchronously,
+// so a returned media ID doesn't necessarily mean the post is live
+type PostStatusChecker interface {
+	// GetPostStatus returns the status of the given media ID, with the platform's reason when
+	// processing failed
+	GetPostStatus(ctx context.Context, client *http.Client, mediaID string) (PostStatusDetail, error)
+}
+
+// PostStatusDetail is the status of a post on a platform processing it asynchronously
+type PostStatusDetail struct {
+	Status          string           // one of the PostStatus values
+	ProcessingError *ProcessingError // why the platform didn't publish the post, if Status is failed
+}
+
+// ProcessingError explains why a platform failed to process or rejected an uploaded post
+type ProcessingError struct {
+	Reason  string `json:"reason" example:"transcodeFailed"`                        // 平台给出的原因代码，如YouTube的failureReason、rejectionReason、processingFailureReason，TikTok的fail_reason
+	Message string `json:"message" example:"youtube failed to transcode the video"` // 原因说明
+}
+
+// Error implements the error interface
+func (e *ProcessingError) Error() string {
+	return e.Message
+}
+
+// IsProcessingError reports whether err is or wraps a ProcessingError
+func IsProcessingError(err error) bool {
+	var processingErr *ProcessingError
+	return errors.As(err, &processingErr)
+}
+
+// NativeScheduler is implemented by platforms that schedule posts themselves. Shares with a future
+// scheduled_at are passed straight to Share instead of being stored for the scheduler.
+type NativeScheduler interface {
+	// SchedulesNatively reports whether the platform publishes scheduled posts on its own
+	SchedulesNatively() bool
+}
+
+// AnonymousSharer is implemented by platforms that can post some requests without the user's token,
+// such as Discord webhooks, which carry their own credentials. Such requests get a plain client.
+type AnonymousSharer interface {
+	// SharesWithoutAuth reports whether req is posted without an authenticated client
+	SharesWithoutAuth(req *ShareRequest) bool
+}
+
+// ShareValidator is implemented by platforms that can check a share request's content and media
+// against their requirements without posting it
+type ShareValidator interface {
+	// Validate returns a ValidationError if the platform can't accept the request
+	Validate(req *ShareRequest) error
+}
+
 // IsAuthorizedRequest represents a request to check if a user is authorized for a platform
 type IsAuthorizedRequest struct {
-	Provider   string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"`
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
 	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`
 }
 
 // IsAuthorizedResponse represents a response to check if a user is authorized for a platform
 type IsAuthorizedResponse struct {
-	IsAuthorized bool `json:"is_authorized" example:"true"`
+	IsAuthorized     bool  `json:"is_authorized" example:"true"`
+	ExpiresAt        int64 `json:"expires_at" example:"1704067199"`   // token过期时间戳，0表示未知或不过期
+	ExpiresInSeconds int64 `json:"expires_in_seconds" example:"3600"` // 距离过期的剩余秒数，0表示未知或不过期
+}
+
+// ListAuthorizedRequest represents a request to list the platforms a user has authorized
+type ListAuthorizedRequest struct {
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`
+}
+
+// AuthorizedProvider represents the authorization state of a single platform
+type AuthorizedProvider struct {
+	Provider  string `json:"provider" example:"x"`
+	IsValid   bool   `json:"is_valid" example:"true"`         // token是否有效（未过期）
+	ExpiresAt int64  `json:"expires_at" example:"1704067199"` // 时间戳格式，0表示未知
+}
+
+// ListAuthorizedResponse represents the platforms a user has authorized
+type ListAuthorizedResponse struct {
+	UserID     string               `json:"user_id" example:"user123"`
+	ServerName string               `json:"server_name" example:"myapp"`
+	Providers  []AuthorizedProvider `json:"providers"`
+}
+
+// AccountSeparator separates the user ID from the account ID in the user part of a token key
+const AccountSeparator = "#"
+
+// AccountUserID returns the user ID a user's account is stored under. The default account, an
+// empty account ID, is stored under the plain user ID so tokens saved before accounts existed
+// keep working.
+func AccountUserID(userID, accountID string) string {
+	if accountID == "" {
+		return userID
+	}
+	return userID + AccountSeparator + accountID
+}
+
+// ListAccountsRequest represents a request to list the accounts a user has connected for a platform
+type ListAccountsRequest struct {
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`                      // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"` // 用户ID
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`           // 服务名称
+}
+
+// ConnectedAccount represents the authorization state of one connected account
+type ConnectedAccount struct {
+	AccountID string `json:"account_id" example:"brand"`      // 账号ID，默认账号为空字符串
+	IsValid   bool   `json:"is_valid" example:"true"`         // token是否有效（未过期）
+	ExpiresAt int64  `json:"expires_at" example:"1704067199"` // 时间戳格式，0表示未知
+}
+
+// ListAccountsResponse represents the accounts a user has connected for a platform
+type ListAccountsResponse struct {
+	Provider   string             `json:"provider" example:"x"`
+	UserID     string             `json:"user_id" example:"user123"`
+	ServerName string             `json:"server_name" example:"myapp"`
+	Accounts   []ConnectedAccount `json:"accounts"`
 }
 
 // RefreshTokenRequest represents a request to refresh a token
 type RefreshTokenRequest struct {
-	Provider   string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                        // 用户ID
-	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                       // 服务名称
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
 }
 
 // RefreshTokenResponse represents a response for token refresh
@@ -185,11 +658,35 @@ type RefreshTokenResponse struct {
 	Message     string `json:"message" example:"Token refreshed successfully"`
 }
 
+// RefreshAllTokensRequest represents a request to refresh the tokens of all platforms a user has authorized
+type RefreshAllTokensRequest struct {
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"` // 用户ID
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`           // 服务名称
+}
+
+// ProviderRefreshResult represents the refresh outcome for a single platform
+type ProviderRefreshResult struct {
+	Provider  string `json:"provider" example:"x"`
+	ExpiresAt int64  `json:"expires_at,omitempty" example:"1704067199"`      // 新token的过期时间戳，刷新成功时返回
+	Error     string `json:"error,omitempty" example:"token refresh failed"` // 如果该平台刷新失败，记录错误信息
+}
+
+// RefreshAllTokensResponse represents the response for refreshing all tokens of a user
+type RefreshAllTokensResponse struct {
+	UserID       string                  `json:"user_id" example:"user123"`
+	ServerName   string                  `json:"server_name" example:"myapp"`
+	Providers    []ProviderRefreshResult `json:"providers"`                 // 各平台的刷新结果
+	SuccessCount int                     `json:"success_count" example:"2"` // 刷新成功的平台数量
+	ErrorCount   int                     `json:"error_count" example:"1"`   // 刷新失败的平台数量
+	RefreshedAt  int64                   `json:"refreshed_at" example:"1704067199"`
+}
+
 // CheckTokenStatusRequest represents a request to check token status
 type CheckTokenStatusRequest struct {
-	Provider   string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                        // 用户ID
-	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                       // 服务名称
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
 }
 
 // CheckTokenStatusResponse represents a response for token status check
@@ -202,12 +699,15 @@ type CheckTokenStatusResponse struct {
 
 // GetRecentPostsRequest represents a request to get recent posts from a social platform
 type GetRecentPostsRequest struct {
-	Provider   string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`                        // 用户ID
-	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                       // 服务名称
-	Limit      int    `json:"limit,omitempty" binding:"omitempty,min=1,max=100" example:"10"`                    // 获取数量限制，默认10，最大100
-	StartTime  int64  `json:"start_time,omitempty" example:"1704067199"`                                         // 开始时间戳（可选）
-	EndTime    int64  `json:"end_time,omitempty" example:"1704153599"`                                           // 结束时间戳（可选）
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
+	Limit      int    `json:"limit,omitempty" binding:"omitempty,min=1,max=100" example:"10"`              // 获取数量限制，默认10，最大100
+	StartTime  int64  `json:"start_time,omitempty" example:"1704067199"`                                   // 开始时间戳（可选）
+	EndTime    int64  `json:"end_time,omitempty" example:"1704153599"`                                     // 结束时间戳（可选）
+
+	MediaTypeFilter string `json:"media_type,omitempty" binding:"omitempty,oneof=image video gif audio text" example:"video"` // 只返回该媒体类型的帖子（可选）：image video gif audio text，在获取后过滤，返回数量可能少于limit
 }
 
 // Post represents a single post from a social platform
@@ -219,7 +719,7 @@ type Post struct {
 	UpdatedAt   int64     `json:"updated_at,omitempty" example:"1704067199"`                    // 更新时间戳
 	Stats       StatsData `json:"stats"`                                                        // 统计信息
 	URL         string    `json:"url,omitempty" example:"https://x.com/user/status/1234567890"` // 帖子链接
-	MediaType   string    `json:"media_type,omitempty" example:"image"`                         // 媒体类型：image, video, audio
+	MediaType   string    `json:"media_type,omitempty" example:"image"`                         // 媒体类型：image, video, gif, audio
 	Title       string    `json:"title,omitempty" example:"My Post"`                            // 标题（YouTube等平台）
 	Description string    `json:"description,omitempty" example:"Post description"`             // 描述
 	Tags        []string  `json:"tags" example:"tag1,tag2"`                                     // 标签列表
@@ -236,14 +736,153 @@ type GetRecentPostsResponse struct {
 
 // BatchGetRecentPostsRequest represents a request to get recent posts from multiple platforms
 type BatchGetRecentPostsRequest struct {
-	UserID     string `json:"user_id" binding:"required,min=1,max=100" example:"user123"`  // 用户ID
-	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"` // 服务名称
-	StartTime  int64  `json:"start_time,omitempty" example:"1704067199"`                   // 开始时间戳（可选）
-	EndTime    int64  `json:"end_time,omitempty" example:"1704153599"`                     // 结束时间戳（可选）
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"` // 用户ID
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`           // 服务名称
+	StartTime  int64  `json:"start_time,omitempty" example:"1704067199"`                             // 开始时间戳（可选）
+	EndTime    int64  `json:"end_time,omitempty" example:"1704153599"`                               // 结束时间戳（可选）
 	Platforms  []struct {
-		Provider string `json:"provider" binding:"required,oneof=youtube x facebook tiktok instagram" example:"x"` // 平台名称
-		Limit    int    `json:"limit,omitempty" binding:"omitempty,min=1,max=100" example:"10"`                    // 获取数量限制，默认10，最大100
-	} `json:"platforms" binding:"required,min=1,max=10"` // 平台列表，最多10个平台
+		Provider string `json:"provider" binding:"required,provider" example:"x"`               // 平台名称
+		Limit    int    `json:"limit,omitempty" binding:"omitempty,min=1,max=100" example:"10"` // 获取数量限制，默认10，最大100
+	} `json:"platforms" binding:"required,min=1,max=10,dive"` // 平台列表，最多10个平台
+}
+
+// DeletePostRequest represents a request to delete a published post
+type DeletePostRequest struct {
+	Provider   string `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`       // 用户ID
+	AccountID  string `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），同一平台连接多个账号时区分账号，不填为默认账号
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                 // 服务名称
+	MediaID    string `json:"media_id" binding:"required,max=100" example:"1234567890"`                    // 分享时返回的内容ID
+}
+
+// DeletePostResponse represents the response for post deletion
+type DeletePostResponse struct {
+	Provider   string `json:"provider" example:"x"`
+	UserID     string `json:"user_id" example:"user123"`
+	ServerName string `json:"server_name" example:"myapp"`
+	MediaID    string `json:"media_id" example:"1234567890"`
+	Deleted    bool   `json:"deleted" example:"true"`
+}
+
+// CancelScheduledRequest represents a request to cancel a pending scheduled share
+type CancelScheduledRequest struct {
+	JobID      string `json:"job_id" binding:"required,max=64" example:"k3J9xQ2mP7vL4nR8"`           // 定时发布任务ID
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"` // 用户ID，须与创建任务时一致
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`           // 服务名称，须与创建任务时一致
+}
+
+// CancelScheduledResponse represents the response for cancelling a scheduled share
+type CancelScheduledResponse struct {
+	JobID       string `json:"job_id" example:"k3J9xQ2mP7vL4nR8"`
+	Provider    string `json:"provider" example:"x"`
+	ScheduledAt int64  `json:"scheduled_at" example:"1767225600"`
+	Cancelled   bool   `json:"cancelled" example:"true"`
+}
+
+// ListFailedScheduledRequest represents a request to list a user's scheduled shares that failed to publish
+type ListFailedScheduledRequest struct {
+	UserID     string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"` // 用户ID
+	ServerName string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`           // 服务名称
+}
+
+// FailedScheduledShare is a scheduled share that failed at publish time
+type FailedScheduledShare struct {
+	JobID       string `json:"job_id" example:"k3J9xQ2mP7vL4nR8"`
+	Provider    string `json:"provider" example:"x"`
+	Content     string `json:"content,omitempty" example:"Hello World!"`
+	MediaURL    string `json:"media_url,omitempty" example:"https://example.com/image.jpg"`
+	ScheduledAt int64  `json:"scheduled_at" example:"1767225600"`                          // 原定发布时间
+	FailedAt    int64  `json:"failed_at" example:"1767225630"`                             // 发布失败的时间
+	Error       string `json:"error" example:"failed to get valid token: token not found"` // 失败原因
+}
+
+// ListFailedScheduledResponse represents the response for listing failed scheduled shares
+type ListFailedScheduledResponse struct {
+	Failed []FailedScheduledShare `json:"failed"` // 按失败时间倒序
+	Total  int                    `json:"total" example:"1"`
+}
+
+// RetryScheduledRequest represents a request to retry a failed scheduled share
+type RetryScheduledRequest struct {
+	JobID       string `json:"job_id" binding:"required,max=64" example:"k3J9xQ2mP7vL4nR8"`           // 失败的定时发布任务ID
+	UserID      string `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"` // 用户ID，须与创建任务时一致
+	ServerName  string `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`           // 服务名称，须与创建任务时一致
+	ScheduledAt int64  `json:"scheduled_at,omitempty" binding:"omitempty,min=0" example:"1767225600"` // 重新发布的时间，不填或为过去时间时由调度器尽快发布
+}
+
+// BatchShareRequest represents a request to share content to multiple platforms
+type BatchShareRequest struct {
+	UserID     string               `json:"user_id" binding:"required,min=1,max=100,excludes=#" example:"user123"`                                    // 用户ID
+	ServerName string               `json:"server_name" binding:"required,min=1,max=50" example:"myapp"`                                              // 服务名称
+	Platforms  []BatchSharePlatform `json:"platforms" binding:"required,min=1,max=10,dive"`                                                           // 平台列表，最多10个平台
+	OnError    string               `json:"on_error,omitempty" binding:"omitempty,oneof=continue_on_error stop_on_error" example:"continue_on_error"` // 某个平台失败后的处理方式：continue_on_error（默认）继续发布其余平台，stop_on_error 跳过其余平台
+}
+
+// Batch share failure modes
+const (
+	BatchContinueOnError = "continue_on_error"
+	BatchStopOnError     = "stop_on_error"
+)
+
+// BatchSharePlatform represents the content to share to a single platform in a batch
+type BatchSharePlatform struct {
+	Provider  string   `json:"provider" binding:"required,provider" example:"x"`                            // 平台名称
+	AccountID string   `json:"account_id,omitempty" binding:"omitempty,max=100,excludes=#" example:"brand"` // 账号ID（可选），不填为默认账号
+	Priority  int      `json:"priority,omitempty" binding:"min=0,max=100" example:"0"`                      // 发布顺序，数值小的先发布，相同时按列表顺序
+	Required  bool     `json:"required,omitempty" example:"false"`                                          // 该平台失败时整个批次标记为失败
+	Content   string   `json:"content,omitempty" binding:"max=63206" example:"Hello World!"`
+	MediaURL  string   `json:"media_url,omitempty" binding:"omitempty,media_url" example:"https://example.com/image.jpg"`
+	Title     string   `json:"title,omitempty" binding:"max=300" example:"My Post"`
+	Desc      string   `json:"description,omitempty" binding:"max=5000" example:"This is a description"`
+	Tags      []string `json:"tags,omitempty" binding:"max=10" example:"hello,world"`
+	Privacy   string   `json:"privacy,omitempty" binding:"omitempty,oneof=public private unlisted friends followers" example:"public"`
+	BoardID   string   `json:"board_id,omitempty" binding:"max=64" example:"549755885175"` // Pinterest画板ID（仅Pinterest，必填）
+
+	MediaAltText string `json:"media_alt_text,omitempty" binding:"max=1000" example:"A golden retriever catching a frisbee"` // 媒体的替代文本（X、Instagram单图）
+
+	Subreddit string `json:"subreddit,omitempty" binding:"max=21" example:"golang"` // 子版块名称（仅Reddit，必填）
+
+	PageID string `json:"page_id,omitempty" binding:"omitempty,numeric,max=32" example:"102938475610293"` // Facebook主页ID（仅Facebook）
+
+	ChannelID  string `json:"channel_id,omitempty" binding:"max=32" example:"1234567890123456789"`                                       // 频道ID（仅Discord）
+	WebhookURL string `json:"webhook_url,omitempty" binding:"omitempty,url,max=2048" example:"https://discord.com/api/webhooks/123/abc"` // 频道Webhook地址（仅Discord）
+}
+
+// PlatformShareResult represents the share outcome for a single platform
+type PlatformShareResult struct {
+	Provider string   `json:"provider" example:"x"`
+	MediaID  string   `json:"media_id,omitempty" example:"1234567890"`                       // 发布成功后的内容ID
+	URL      string   `json:"url,omitempty" example:"https://x.com/i/web/status/1234567890"` // 内容链接（平台支持时返回）
+	Status   string   `json:"status,omitempty" example:"published"`                          // 发布状态
+	Error    string   `json:"error,omitempty" example:"authentication failed"`               // 如果该平台发布失败，记录错误信息
+	PostRef  *PostRef `json:"post_ref,omitempty"`                                            // 结构化的内容ID
+	Warnings []string `json:"warnings,omitempty"`                                            // 不影响发布的警告，如媒体上传失败后仅发布了文字
+	Required bool     `json:"required,omitempty" example:"false"`                            // 请求中是否标记为必须成功
+
+	ProcessingError *ProcessingError `json:"processing_error,omitempty"` // 平台上传后处理失败的原因
+
+	UploadedBytes int64 `json:"uploaded_bytes,omitempty" example:"10485760"` // 上传到平台的媒体字节数
+
+	ResetAt int64 `json:"reset_at,omitempty" example:"1704067199"` // 平台限流时窗口重置的时间戳，之后可以重试
+}
+
+// ShareProgress is the upload progress of a background share job, streamed as a progress event
+type ShareProgress struct {
+	JobID         string `json:"job_id" example:"k3J9xQ2mP7vL4nR8"`
+	UploadedBytes int64  `json:"uploaded_bytes" example:"5242880"` // 已上传到平台的媒体字节数
+	TotalBytes    int64  `json:"total_bytes" example:"10485760"`   // 媒体总字节数
+	Percent       int    `json:"percent" example:"50"`             // 上传进度百分比
+}
+
+// BatchShareResponse represents the response for batch sharing
+type BatchShareResponse struct {
+	UserID       string                `json:"user_id" example:"user123"`
+	ServerName   string                `json:"server_name" example:"myapp"`
+	Platforms    []PlatformShareResult `json:"platforms"`                 // 各平台的发布结果
+	SuccessCount int                   `json:"success_count" example:"2"` // 发布成功的平台数量
+	ErrorCount   int                   `json:"error_count" example:"1"`   // 发布失败的平台数量
+	SkippedCount int                   `json:"skipped_count" example:"0"` // stop_on_error时因前面的平台失败而跳过的平台数量
+	Failed       bool                  `json:"failed" example:"false"`    // 有required平台失败或被跳过时为true
 }
 
 // PlatformPosts represents posts from a single platform
@@ -254,14 +893,63 @@ type PlatformPosts struct {
 	Posts      []Post `json:"posts"`                                           // 该平台的帖子列表
 	Total      int    `json:"total" example:"10"`                              // 该平台的总数量
 	Error      string `json:"error,omitempty" example:"authentication failed"` // 如果该平台查询失败，记录错误信息
+	// 查询失败时的错误码，与单平台接口返回的错误码一致，如TOKEN_NOT_FOUND、RATE_LIMITED、UPSTREAM_ERROR
+	ErrorCode string `json:"error_code,omitempty" example:"TOKEN_NOT_FOUND"`
+	// 查询失败时的处理建议：reauthorize需要重新授权，retry可稍后重试，permanent重试无效
+	ErrorCategory string `json:"error_category,omitempty" example:"reauthorize"`
+	ResetAt       int64  `json:"reset_at,omitempty" example:"1704067199"` // 平台限流时窗口重置的时间戳，之后可以重试
 }
 
+// Error categories of a failed platform in a batch response, telling callers how to recover
+const (
+	ErrorCategoryReauthorize = "reauthorize" // the user has to authorize the platform again
+	ErrorCategoryRetry       = "retry"       // a temporary failure, the same request may succeed later
+	ErrorCategoryPermanent   = "permanent"   // retrying won't help without changing the request or account
+)
+
 // BatchGetRecentPostsResponse represents the response for batch recent posts
 type BatchGetRecentPostsResponse struct {
-	UserID       string          `json:"user_id" example:"user123"`
-	ServerName   string          `json:"server_name" example:"myapp"`
-	Platforms    []PlatformPosts `json:"platforms"`                 // 各平台的帖子列表
-	TotalPosts   int             `json:"total_posts" example:"25"`  // 所有平台的总帖子数
-	SuccessCount int             `json:"success_count" example:"3"` // 成功查询的平台数量
-	ErrorCount   int             `json:"error_count" example:"1"`   // 查询失败的平台数量
+	UserID       string           `json:"user_id" example:"user123"`
+	ServerName   string           `json:"server_name" example:"myapp"`
+	Platforms    []PlatformPosts  `json:"platforms"`                 // 各平台的帖子列表
+	TotalPosts   int              `json:"total_posts" example:"25"`  // 所有平台的总帖子数
+	SuccessCount int              `json:"success_count" example:"3"` // 成功查询的平台数量
+	ErrorCount   int              `json:"error_count" example:"1"`   // 查询失败的平台数量
+	Totals       EngagementTotals `json:"totals"`                    // 所有平台返回帖子的互动数据合计
+}
+
+// EngagementTotals sums engagement across posts
+type EngagementTotals struct {
+	Likes    int `json:"likes" example:"1200"`
+	Comments int `json:"comments" example:"300"` // 评论/回复数
+	Shares   int `json:"shares" example:"150"`   // 分享数，含X的转推
+	Views    int `json:"views" example:"50000"`
+}
+
+// Add adds a post's stats to the totals
+func (t *EngagementTotals) Add(stats StatsData) {
+	t.Likes += stats.Likes
+	t.Comments += stats.Replies
+	t.Shares += stats.Shares + stats.Retweets
+	t.Views += stats.Views
+}
+
+// MaintenanceRequest represents a request to toggle maintenance mode
+type MaintenanceRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required" example:"true"`                               // 是否开启维护模式
+	Message string `json:"message,omitempty" binding:"max=500" example:"X API incident, back soon"` // 返回给客户端的提示信息（可选）
+}
+
+// MaintenanceResponse represents the current maintenance mode state
+type MaintenanceResponse struct {
+	Enabled bool   `json:"enabled" example:"true"`
+	Message string `json:"message" example:"service is under maintenance, posting is temporarily disabled"`
+}
+
+// AdminConfigResponse represents the effective configuration with secrets redacted
+type AdminConfigResponse struct {
+	Environment string              `json:"environment" example:"production"`
+	Config      map[string]any      `json:"config"`                                                  // 生效的配置，键名与配置文件一致，密钥已脱敏
+	Servers     map[string][]string `json:"servers"`                                                 // 每个服务已配置凭据的平台
+	Warnings    []string            `json:"warnings" example:"No multi-server configurations found"` // 配置校验警告
 }
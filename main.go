@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -10,6 +12,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
@@ -17,9 +21,15 @@ import (
 	"social/internal/config"
 	"social/internal/handlers"
 	"social/internal/middleware"
+	"social/internal/oauth"
 	"social/internal/platforms"
 	"social/internal/storage"
+	"social/pkg/breaker"
+	"social/pkg/httpx"
 	"social/pkg/logger"
+	"social/pkg/quota"
+	"social/pkg/tracing"
+	"social/pkg/validator"
 )
 
 // @title Social Media Platform API
@@ -44,33 +54,162 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Components read the configuration through the provider so a reloaded config applies immediately
+	configProvider := config.NewAtomicProvider(cfg)
+
 	// Initialize logger
-	appLogger := logger.NewLogger()
+	appLogger := logger.NewLogger(logger.Config{Level: cfg.Log.Level, Format: cfg.Log.Format})
+
+	// Configuration that works but is likely a mistake, such as providers missing scopes
+	for _, warning := range config.NewConfigValidator(cfg).GetValidationWarnings() {
+		appLogger.Warn(context.Background(), "configuration warning", "warning", warning)
+	}
 
-	// Initialize Redis storage
-	redisStorage, err := storage.NewRedisStorage(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+	// Initialize storage backend
+	store, err := newStorage(cfg, appLogger)
 	if err != nil {
-		log.Fatalf("Failed to initialize Redis storage: %v", err)
+		log.Fatalf("Failed to initialize storage: %v", err)
 	}
 	defer func() {
-		if err := redisStorage.Close(); err != nil {
-			log.Printf("Failed to close Redis storage: %v", err)
+		if err := store.Close(); err != nil {
+			appLogger.Error(context.Background(), err, "failed to close storage")
 		}
 	}()
 
+	// Retry policy of idempotent upstream API calls
+	httpx.Configure(httpx.RetryConfig{
+		MaxRetries: cfg.Retry.MaxRetries,
+		BaseDelay:  cfg.Retry.BaseDelay,
+		MaxDelay:   cfg.Retry.MaxDelay,
+	})
+
+	// Platform API calls fail fast while their provider's circuit breaker is open
+	breaker.Configure(breaker.Config{
+		Enabled:          cfg.CircuitBreaker.Enabled,
+		FailureThreshold: cfg.CircuitBreaker.FailureThreshold,
+		OpenTimeout:      cfg.CircuitBreaker.OpenTimeout,
+	})
+
+	// Platform calls to a rate limit window that ran out are rejected until it resets, the windows
+	// are shared through Redis when it is the storage backend
+	platformRateLimit := quota.Config{Enabled: cfg.PlatformRateLimit.Enabled}
+	if redisClient := redisClientOf(store); redisClient != nil {
+		platformRateLimit.Store = quota.NewRedisStore(redisClient)
+	}
+	quota.Configure(platformRateLimit)
+
+	// Outbound requests share http.DefaultTransport, which must not negotiate below the minimum TLS version
+	if err := httpx.ConfigureTLS(cfg.TLS.MinVersion); err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+
+	// Export request traces when a collector is configured, tracing is a no-op otherwise
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	// Every outbound request, with or without a token, ends up on http.DefaultTransport
+	http.DefaultTransport = tracing.Transport(http.DefaultTransport)
+
 	// Initialize platform registry
 	platformRegistry := platforms.NewRegistry()
 
+	// Replace providers with stubs in sandbox mode, decided at startup
+	if cfg.Sandbox.Enabled {
+		platformRegistry.UseSandbox(cfg.Sandbox.Providers...)
+		appLogger.Warn(context.Background(), "sandbox mode enabled, shares are not posted to providers", "providers", cfg.Sandbox.Providers)
+	}
+
+	// Binding errors report fields by their JSON names
+	if err := validator.UseJSONFieldNames(); err != nil {
+		log.Fatalf("Failed to register validation field names: %v", err)
+	}
+	// Request providers are validated against the registry, so a registered platform is accepted everywhere
+	if err := validator.RegisterProvider(platformRegistry.Resolve); err != nil {
+		log.Fatalf("Failed to register provider validation: %v", err)
+	}
+	// Relative media URLs are accepted only while media.base_url can resolve them
+	if err := validator.RegisterMediaURL(func() bool { return configProvider.Get().Media.BaseURL != "" }); err != nil {
+		log.Fatalf("Failed to register media url validation: %v", err)
+	}
+
+	// Prewarm connections to provider APIs if enabled
+	if cfg.Warmup.Enabled {
+		warmupCtx, cancel := context.WithTimeout(context.Background(), cfg.Warmup.Timeout)
+		providers := cfg.ConfiguredProviders()
+		failures := platforms.Warmup(warmupCtx, providers)
+		cancel()
+		for host, err := range failures {
+			appLogger.Warn(context.Background(), "connection warmup failed", "host", host, "error", err)
+		}
+		appLogger.Info(context.Background(), "connection warmup completed", "providers", providers, "failed_hosts", len(failures))
+	}
+
+	// Start background token refresh worker
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	if cfg.TokenRefresh.Enabled {
+		refreshWorker := oauth.NewRefreshWorker(oauth.NewTokenManager(configProvider, store, appLogger), store, appLogger, cfg.TokenRefresh.Interval, cfg.TokenRefresh.Window)
+		go func() {
+			defer close(workerDone)
+			refreshWorker.Run(workerCtx)
+		}()
+	} else {
+		close(workerDone)
+	}
+
+	// Purge expired entries the storage backend doesn't expire by itself
+	cleanupDone := make(chan struct{})
+	if cfg.Cleanup.Enabled {
+		go func() {
+			defer close(cleanupDone)
+			runStorageCleanup(workerCtx, store, cfg.Cleanup.Interval, appLogger)
+		}()
+	} else {
+		close(cleanupDone)
+	}
+
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(cfg, redisStorage, platformRegistry, appLogger)
-	shareHandler := handlers.NewShareHandler(cfg, redisStorage, platformRegistry, appLogger)
-	healthHandler := handlers.NewHealthHandler(redisStorage, appLogger)
+	authHandler := handlers.NewAuthHandler(configProvider, store, platformRegistry, appLogger)
+	shareHandler := handlers.NewShareHandler(configProvider, store, platformRegistry, appLogger)
+	healthHandler := handlers.NewHealthHandler(configProvider, store, appLogger)
 
 	// Initialize request middleware
 	requestMiddleware := middleware.NewRequestMiddleware(appLogger)
+	maintenanceMiddleware := middleware.NewMaintenanceMiddleware(cfg.Maintenance.Enabled, cfg.Maintenance.Message, appLogger)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(store, cfg.Idempotency.Enabled, cfg.Idempotency.TTL, appLogger)
+	if cfg.Maintenance.Enabled {
+		appLogger.Warn(context.Background(), "starting in maintenance mode, write endpoints are blocked")
+	}
+
+	// Start background scheduled share publishing
+	schedulerDone := make(chan struct{})
+	if cfg.Scheduler.Enabled {
+		scheduler := handlers.NewScheduler(shareHandler, maintenanceMiddleware, cfg.Scheduler.Interval)
+		go func() {
+			defer close(schedulerDone)
+			scheduler.Run(workerCtx)
+		}()
+	} else {
+		close(schedulerDone)
+	}
+
+	// Per-user rate limiting with per-server tiers, shared across instances through Redis when available
+	var rateLimiter *middleware.RateLimiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = middleware.NewRateLimiter(redisClientOf(store), cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.Burst, appLogger)
+		for serverName, tier := range cfg.RateLimit.Servers {
+			rateLimiter.SetServerLimit(serverName, tier.RequestsPerMinute, tier.Burst)
+		}
+	}
+
+	adminHandler := handlers.NewAdminHandler(configProvider, maintenanceMiddleware, appLogger)
 
 	// Setup Gin router
-	router := setupRouter(authHandler, shareHandler, healthHandler, requestMiddleware)
+	router := setupRouter(cfg, authHandler, shareHandler, healthHandler, adminHandler, requestMiddleware, maintenanceMiddleware, idempotencyMiddleware, rateLimiter)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -93,6 +232,12 @@ func main() {
 
 	appLogger.Info(context.Background(), "Shutting down server...")
 
+	// Stop background workers
+	stopWorker()
+	<-workerDone
+	<-schedulerDone
+	<-cleanupDone
+
 	// Create a deadline for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -102,11 +247,83 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Export the spans still buffered
+	if err := shutdownTracing(ctx); err != nil {
+		appLogger.Error(context.Background(), err, "failed to flush traces")
+	}
+
 	appLogger.Info(context.Background(), "Server exited")
 }
 
+// newStorage creates the storage backend selected by STORAGE_BACKEND (redis by default)
+func newStorage(cfg *config.Config, appLogger *logger.Logger) (storage.Storage, error) {
+	switch backend := config.GetEnvWithDefault(config.EnvStorageBackend, "redis"); backend {
+	case "redis":
+		redisStorage, err := storage.NewRedisStorage(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, appLogger)
+		if err != nil {
+			return nil, err
+		}
+		redisStorage.SetTokenTTLFunc(cfg.TokenTTLFor)
+		// Serve bursty token checks from a short-lived in-process cache
+		return storage.NewCachedStorage(redisStorage, storage.DefaultTokenCacheTTL), nil
+	case "memory":
+		appLogger.Warn(context.Background(), "using in-memory storage, tokens will be lost on restart")
+		memoryStorage := storage.NewMemoryStorage()
+		memoryStorage.SetTokenTTLFunc(cfg.TokenTTLFor)
+		return memoryStorage, nil
+	case "postgres":
+		dsn := config.GetEnvWithDefault(config.EnvPostgresDSN, "")
+		if dsn == "" {
+			return nil, fmt.Errorf("%s is required for the postgres storage backend", config.EnvPostgresDSN)
+		}
+		key, err := base64.StdEncoding.DecodeString(config.GetEnvWithDefault(config.EnvTokenEncryptionKey, ""))
+		if err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("%s must be a base64 encoded 32 byte key for the postgres storage backend", config.EnvTokenEncryptionKey)
+		}
+		postgresStorage, err := storage.NewPostgresStorage(dsn, key, appLogger)
+		if err != nil {
+			return nil, err
+		}
+		postgresStorage.SetTokenTTLFunc(cfg.TokenTTLFor)
+		return storage.NewCachedStorage(postgresStorage, storage.DefaultTokenCacheTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", backend)
+	}
+}
+
+// runStorageCleanup purges expired storage entries every interval until ctx is cancelled. A failed
+// cleanup is logged and retried on the next tick.
+func runStorageCleanup(ctx context.Context, store storage.Storage, interval time.Duration, appLogger *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cleanupCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			if err := store.Cleanup(cleanupCtx); err != nil {
+				appLogger.Error(ctx, err, "storage cleanup failed")
+			}
+			cancel()
+		}
+	}
+}
+
+// redisClientOf returns the Redis client behind the storage backend, or nil if it isn't Redis
+func redisClientOf(store storage.Storage) *redis.Client {
+	if cached, ok := store.(*storage.CachedStorage); ok {
+		store = cached.Unwrap()
+	}
+	if redisStorage, ok := store.(*storage.RedisStorage); ok {
+		return redisStorage.Client()
+	}
+	return nil
+}
+
 // setupRouter configures the Gin router with all routes
-func setupRouter(authHandler *handlers.AuthHandler, shareHandler *handlers.ShareHandler, healthHandler *handlers.HealthHandler, requestMiddleware *middleware.RequestMiddleware) *gin.Engine {
+func setupRouter(cfg *config.Config, authHandler *handlers.AuthHandler, shareHandler *handlers.ShareHandler, healthHandler *handlers.HealthHandler, adminHandler *handlers.AdminHandler, requestMiddleware *middleware.RequestMiddleware, maintenanceMiddleware *middleware.MaintenanceMiddleware, idempotencyMiddleware *middleware.IdempotencyMiddleware, rateLimiter *middleware.RateLimiter) *gin.Engine {
 	// Set Gin mode based on environment
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
@@ -117,9 +334,18 @@ func setupRouter(authHandler *handlers.AuthHandler, shareHandler *handlers.Share
 	// Add middleware
 	router.Use(gin.Recovery())
 	router.Use(requestMiddleware.RequestID()) // 添加request ID中间件
+	router.Use(middleware.Metrics())          // 请求指标
+	router.Use(middleware.BodyLimit(cfg.Server.MaxBodyBytes))
+	if cfg.CORS.Enabled {
+		router.Use(middleware.CORS(cfg.CORS.AllowedOrigins, cfg.CORS.MaxAge)) // 浏览器跨域请求
+	}
 
-	// Health check endpoint
+	// Health check endpoints, /health for liveness and /health/ready for readiness
 	router.GET("/health", healthHandler.Health)
+	router.GET("/health/ready", healthHandler.Ready)
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -136,21 +362,47 @@ func setupRouter(authHandler *handlers.AuthHandler, shareHandler *handlers.Share
 	// OAuth endpoints
 	router.POST("/auth/start", authHandler.StartAuth)
 	router.POST("/auth/callback", authHandler.Callback)
+	router.GET("/auth/callback/:provider", authHandler.CallbackRedirect)
 	router.POST("/auth/is-authorized", authHandler.IsAuthorized)
+	router.POST("/auth/list-authorized", authHandler.ListAuthorized)
+	router.POST("/auth/list-accounts", authHandler.ListAccounts)
 	router.POST("/auth/user-info", authHandler.GetUserInfo)
 	router.POST("/auth/refresh-token", authHandler.RefreshToken)
+	router.POST("/auth/refresh-all", authHandler.RefreshAllTokens)
 
 	// API endpoints - RESTful design
 	api := router.Group("/api")
+	if rateLimiter != nil {
+		api.Use(rateLimiter.Limit())
+	}
 	{
-		// Legacy endpoints for backward compatibility
-		api.POST("/share", shareHandler.Share)
+		// Legacy endpoints for backward compatibility, writes are blocked in maintenance mode.
+		// Shares retried with the same Idempotency-Key get the first response back.
+		api.POST("/share", maintenanceMiddleware.BlockWrites(), idempotencyMiddleware.Handle(), shareHandler.Share)
+		api.GET("/share/progress/:job_id", shareHandler.ShareProgress)
+		api.POST("/batch-share", maintenanceMiddleware.BlockWrites(), shareHandler.BatchShare)
+		api.POST("/delete-post", maintenanceMiddleware.BlockWrites(), shareHandler.DeletePost)
+		api.POST("/scheduled/cancel", maintenanceMiddleware.BlockWrites(), shareHandler.CancelScheduled)
+		api.POST("/scheduled/failed", shareHandler.ListFailedScheduled)
+		api.POST("/scheduled/retry", maintenanceMiddleware.BlockWrites(), shareHandler.RetryScheduled)
 		api.POST("/stats", shareHandler.GetStats)
+		api.POST("/post", shareHandler.GetPost)
+		api.GET("/platforms", shareHandler.ListPlatforms)
+		api.GET("/errors", shareHandler.ListErrorCodes)
+		api.POST("/post-status", shareHandler.GetPostStatus)
 
 		// Recent posts endpoints
 		api.POST("/recent-posts", shareHandler.GetRecentPosts)
 		api.POST("/batch-recent-posts", shareHandler.BatchGetRecentPosts)
 	}
 
+	// Admin endpoints, require X-Admin-Token
+	admin := router.Group("/admin", middleware.AdminAuth(cfg.Server.AdminToken))
+	{
+		admin.GET("/maintenance", adminHandler.GetMaintenance)
+		admin.POST("/maintenance", adminHandler.SetMaintenance)
+		admin.GET("/config", adminHandler.GetConfig)
+	}
+
 	return router
 }
@@ -0,0 +1,231 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// State 熔断器状态
+type State string
+
+const (
+	StateClosed   State = "closed"    // 正常放行请求
+	StateOpen     State = "open"      // 拒绝所有请求，直到冷却时间结束
+	StateHalfOpen State = "half_open" // 冷却结束，放行一个探测请求
+)
+
+// Config 熔断配置
+type Config struct {
+	Enabled          bool          // 是否启用熔断
+	FailureThreshold int           // 连续失败多少次后熔断
+	OpenTimeout      time.Duration // 熔断后多久放行探测请求
+}
+
+// DefaultConfig 默认熔断配置
+var DefaultConfig = Config{
+	Enabled:          true,
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+}
+
+// ErrOpen 熔断器打开，请求未发送
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError 说明哪个熔断器拒绝了请求、何时会放行探测请求
+type OpenError struct {
+	Name    string
+	RetryAt time.Time
+}
+
+// Error 实现error接口
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s is temporarily unavailable, retry after %s", e.Name, e.RetryAt.UTC().Format(time.RFC3339))
+}
+
+// Is 让errors.Is(err, ErrOpen)匹配OpenError
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// AsOpenError 从错误链中取出OpenError
+func AsOpenError(err error) (*OpenError, bool) {
+	var openErr *OpenError
+	if errors.As(err, &openErr) {
+		return openErr, true
+	}
+	return nil, false
+}
+
+// Status 熔断器的当前状态，OpenUntil只在打开时有值
+type Status struct {
+	State     State
+	Failures  int
+	OpenUntil time.Time
+}
+
+// Breaker 按连续失败次数熔断：连续失败达到阈值后打开，冷却时间内直接拒绝请求；
+// 冷却结束后进入半开状态放行一个探测请求，成功则关闭，失败则重新打开
+type Breaker struct {
+	name string
+	cfg  Config
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openUntil time.Time
+	probing   bool // 半开状态下已有探测请求在进行
+}
+
+// New 创建熔断器
+func New(name string, cfg Config) *Breaker {
+	return &Breaker{name: name, cfg: cfg, state: StateClosed}
+}
+
+// Allow 判断是否放行请求，放行后必须调用Record报告结果；拒绝时返回*OpenError
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Now().Before(b.openUntil) {
+			return &OpenError{Name: b.name, RetryAt: b.openUntil}
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			return &OpenError{Name: b.name, RetryAt: time.Now().Add(b.cfg.OpenTimeout)}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record 报告放行的请求是否成功
+func (b *Breaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = StateClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = StateOpen
+		b.openUntil = time.Now().Add(b.cfg.OpenTimeout)
+		b.probing = false
+	}
+}
+
+// release 放弃放行的请求而不记录结果
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// Status 返回熔断器的当前状态，冷却已结束的熔断器报告为半开
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !time.Now().Before(b.openUntil) {
+		return Status{State: StateHalfOpen, Failures: b.failures}
+	}
+
+	status := Status{State: b.state, Failures: b.failures}
+	if b.state == StateOpen {
+		status.OpenUntil = b.openUntil
+	}
+	return status
+}
+
+// config 当前熔断配置，熔断器在首次使用时按当时的配置创建
+var config atomic.Pointer[Config]
+
+// breakers 按名称（平台）保存的熔断器
+var breakers sync.Map
+
+func init() {
+	cfg := DefaultConfig
+	config.Store(&cfg)
+}
+
+// Configure 设置熔断配置，只影响之后创建的熔断器，应在启动时调用
+func Configure(cfg Config) {
+	config.Store(&cfg)
+}
+
+// Get 返回指定名称的熔断器，不存在时创建
+func Get(name string) *Breaker {
+	if b, ok := breakers.Load(name); ok {
+		return b.(*Breaker)
+	}
+	b, _ := breakers.LoadOrStore(name, New(name, *config.Load()))
+	return b.(*Breaker)
+}
+
+// Statuses 返回已创建的各熔断器的状态，按名称索引
+func Statuses() map[string]Status {
+	statuses := make(map[string]Status)
+	breakers.Range(func(key, value any) bool {
+		statuses[key.(string)] = value.(*Breaker).Status()
+		return true
+	})
+	return statuses
+}
+
+// Transport 用指定名称的熔断器包装base：熔断时不发送请求，直接返回*OpenError。
+// 网络错误和5xx响应记为失败；调用方取消的请求不计入结果。未启用熔断时直接返回base。
+func Transport(name string, base http.RoundTripper) http.RoundTripper {
+	if !config.Load().Enabled {
+		return base
+	}
+	return &transport{base: base, breaker: Get(name)}
+}
+
+// transport 经过熔断器的RoundTripper
+type transport struct {
+	base    http.RoundTripper
+	breaker *Breaker
+}
+
+// RoundTrip 实现http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil && errors.Is(req.Context().Err(), context.Canceled) {
+		// 调用方放弃的请求不说明平台是否可用，半开状态下放行下一个探测请求
+		t.breaker.release()
+		return resp, err
+	}
+
+	t.breaker.Record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+// Unwrap 返回被包装的RoundTripper
+func (t *transport) Unwrap() http.RoundTripper {
+	return t.base
+}
@@ -3,13 +3,15 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // AppError represents an application error
 type AppError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Status  int    `json:"-"`
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	Status  int       `json:"-"`
+	ResetAt time.Time `json:"-"` // when a platform's rate limit window resets, for rate limited errors
 }
 
 // Error implements the error interface
@@ -26,34 +28,122 @@ func NewAppError(code, message string, status int) *AppError {
 	}
 }
 
+// catalog holds the predefined errors in definition order, see Catalog
+var catalog []*AppError
+
+// define creates a predefined error and adds it to the catalog
+func define(code, message string, status int) *AppError {
+	appErr := NewAppError(code, message, status)
+	catalog = append(catalog, appErr)
+	return appErr
+}
+
 // Predefined errors
 var (
-	ErrInvalidRequest     = NewAppError("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
-	ErrUnauthorized       = NewAppError("UNAUTHORIZED", "Unauthorized", http.StatusUnauthorized)
-	ErrForbidden          = NewAppError("FORBIDDEN", "Forbidden", http.StatusForbidden)
-	ErrNotFound           = NewAppError("NOT_FOUND", "Not found", http.StatusNotFound)
-	ErrInternalServer     = NewAppError("INTERNAL_SERVER_ERROR", "Internal server error", http.StatusInternalServerError)
-	ErrServiceUnavailable = NewAppError("SERVICE_UNAVAILABLE", "Service unavailable", http.StatusServiceUnavailable)
+	ErrInvalidRequest     = define("INVALID_REQUEST", "Invalid request", http.StatusBadRequest)
+	ErrUnauthorized       = define("UNAUTHORIZED", "Unauthorized", http.StatusUnauthorized)
+	ErrForbidden          = define("FORBIDDEN", "Forbidden", http.StatusForbidden)
+	ErrNotFound           = define("NOT_FOUND", "Not found", http.StatusNotFound)
+	ErrInternalServer     = define("INTERNAL_SERVER_ERROR", "Internal server error", http.StatusInternalServerError)
+	ErrServiceUnavailable = define("SERVICE_UNAVAILABLE", "Service unavailable", http.StatusServiceUnavailable)
+	ErrMaintenanceMode    = define("MAINTENANCE_MODE", "Service is under maintenance", http.StatusServiceUnavailable)
+	ErrRateLimited        = define("RATE_LIMITED", "Too many requests, please retry later", http.StatusTooManyRequests)
+	ErrTimeout            = define("TIMEOUT", "Operation timed out", http.StatusGatewayTimeout)
+	ErrRequestTooLarge    = define("REQUEST_TOO_LARGE", "Request body is too large", http.StatusRequestEntityTooLarge)
+
+	// Idempotency errors
+	ErrIdempotencyKeyInUse  = define("IDEMPOTENCY_KEY_IN_USE", "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+	ErrIdempotencyKeyReused = define("IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request", http.StatusUnprocessableEntity)
 
 	// OAuth specific errors
-	ErrInvalidProvider      = NewAppError("INVALID_PROVIDER", "Invalid OAuth provider", http.StatusBadRequest)
-	ErrTokenNotFound        = NewAppError("TOKEN_NOT_FOUND", "OAuth token not found", http.StatusUnauthorized)
-	ErrTokenExchange        = NewAppError("TOKEN_EXCHANGE_FAILED", "OAuth token exchange failed", http.StatusInternalServerError)
-	ErrInvalidState         = NewAppError("INVALID_STATE", "Invalid OAuth state parameter", http.StatusBadRequest)
-	ErrPKCEVerifierNotFound = NewAppError("PKCE_VERIFIER_NOT_FOUND", "PKCE verifier not found or expired", http.StatusBadRequest)
-	ErrTokenExpired         = NewAppError("TOKEN_EXPIRED", "OAuth token expired", http.StatusUnauthorized)
+	ErrInvalidProvider      = define("INVALID_PROVIDER", "Invalid OAuth provider", http.StatusBadRequest)
+	ErrTokenNotFound        = define("TOKEN_NOT_FOUND", "OAuth token not found", http.StatusUnauthorized)
+	ErrTokenExchange        = define("TOKEN_EXCHANGE_FAILED", "OAuth token exchange failed", http.StatusInternalServerError)
+	ErrInvalidState         = define("INVALID_STATE", "Invalid OAuth state parameter", http.StatusBadRequest)
+	ErrPKCEVerifierNotFound = define("PKCE_VERIFIER_NOT_FOUND", "PKCE verifier not found or expired", http.StatusBadRequest)
+	ErrTokenExpired         = define("TOKEN_EXPIRED", "OAuth token expired", http.StatusUnauthorized)
+	ErrInsufficientScope    = define("INSUFFICIENT_SCOPE", "OAuth token is missing a required scope", http.StatusForbidden)
+	ErrAuthorizationDenied  = define("AUTHORIZATION_DENIED", "OAuth authorization was denied", http.StatusBadRequest)
 
 	// Platform specific errors
-	ErrPlatformNotSupported = NewAppError("PLATFORM_NOT_SUPPORTED", "Platform not supported", http.StatusBadRequest)
-	ErrContentRequired      = NewAppError("CONTENT_REQUIRED", "Content is required", http.StatusBadRequest)
-	ErrMediaIDRequired      = NewAppError("MEDIA_ID_REQUIRED", "Media ID is required", http.StatusBadRequest)
+	ErrPlatformNotSupported = define("PLATFORM_NOT_SUPPORTED", "Platform not supported", http.StatusBadRequest)
+	ErrContentRequired      = define("CONTENT_REQUIRED", "Content is required", http.StatusBadRequest)
+	ErrMediaIDRequired      = define("MEDIA_ID_REQUIRED", "Media ID is required", http.StatusBadRequest)
+	ErrMediaTooLarge        = define("MEDIA_TOO_LARGE", "Media exceeds the platform's size limit", http.StatusRequestEntityTooLarge)
+	ErrUnprocessableEntity  = define("UNPROCESSABLE_ENTITY", "Request cannot be processed by the platform", http.StatusUnprocessableEntity)
+	ErrPlatformAuthFailed   = define("PLATFORM_AUTH_FAILED", "Platform rejected the OAuth token, please re-authorize", http.StatusUnauthorized)
+	ErrAccountSuspended     = define("ACCOUNT_SUSPENDED", "Platform account is suspended", http.StatusForbidden)
+	ErrAccessLevel          = define("ACCESS_LEVEL_INSUFFICIENT", "Platform app access level does not permit this operation", http.StatusForbidden)
+	ErrProcessingFailed     = define("PROCESSING_FAILED", "Platform failed to process the uploaded media", http.StatusUnprocessableEntity)
+	ErrUpstream             = define("UPSTREAM_ERROR", "Platform API request failed", http.StatusBadGateway)
+
+	// Scheduling errors
+	ErrScheduledPostNotFound       = define("SCHEDULED_POST_NOT_FOUND", "Scheduled post not found or already published", http.StatusNotFound)
+	ErrFailedScheduledPostNotFound = define("FAILED_SCHEDULED_POST_NOT_FOUND", "Failed scheduled post not found or already retried", http.StatusNotFound)
+
+	// ErrWrapped is the code of errors created with WrapError
+	ErrWrapped = define("WRAPPED_ERROR", "Operation failed", http.StatusInternalServerError)
 )
 
+// Catalog returns every predefined error code in definition order, with its HTTP status and
+// default message. Errors are copied so callers can't change the predefined ones.
+func Catalog() []AppError {
+	entries := make([]AppError, 0, len(catalog))
+	for _, appErr := range catalog {
+		entries = append(entries, *appErr)
+	}
+	return entries
+}
+
+// WithResetAt returns a copy of the error carrying the time a platform's rate limit window resets
+func (e *AppError) WithResetAt(resetAt time.Time) *AppError {
+	appErr := *e
+	appErr.ResetAt = resetAt
+	return &appErr
+}
+
 // WrapError wraps an error with additional context
 func WrapError(err error, message string) *AppError {
 	return &AppError{
-		Code:    "WRAPPED_ERROR",
+		Code:    ErrWrapped.Code,
 		Message: fmt.Sprintf("%s: %v", message, err),
-		Status:  http.StatusInternalServerError,
+		Status:  ErrWrapped.Status,
+	}
+}
+
+// TimeoutError reports which operation exceeded which deadline
+type TimeoutError struct {
+	Operation string
+	Deadline  time.Duration
+}
+
+// NewTimeoutError creates a timeout error for an operation and its configured deadline
+func NewTimeoutError(operation string, deadline time.Duration) *TimeoutError {
+	return &TimeoutError{
+		Operation: operation,
+		Deadline:  deadline,
+	}
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s exceeded %s limit", e.Operation, formatDeadline(e.Deadline))
+}
+
+// Is makes errors.Is(err, ErrTimeout) match timeout errors
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// AppError converts the timeout into an ErrTimeout response carrying the operation and deadline
+func (e *TimeoutError) AppError() *AppError {
+	return NewAppError(ErrTimeout.Code, e.Error(), ErrTimeout.Status)
+}
+
+// formatDeadline formats whole-second deadlines as e.g. "60s" rather than "1m0s"
+func formatDeadline(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%ds", int64(d/time.Second))
 	}
+	return d.String()
 }
@@ -0,0 +1,138 @@
+package httpx
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RetryConfig 重试配置
+type RetryConfig struct {
+	MaxRetries int           // 最大重试次数，0表示不重试
+	BaseDelay  time.Duration // 首次重试前的等待时间，之后每次翻倍
+	MaxDelay   time.Duration // 单次等待上限，Retry-After超过该值时不再重试
+}
+
+// DefaultRetryConfig 默认重试配置
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// retryConfig Do使用的重试配置
+var retryConfig atomic.Pointer[RetryConfig]
+
+func init() {
+	cfg := DefaultRetryConfig
+	retryConfig.Store(&cfg)
+}
+
+// Configure 设置Do使用的重试配置
+func Configure(cfg RetryConfig) {
+	retryConfig.Store(&cfg)
+}
+
+// Do 使用当前重试配置发送请求，见DoWithRetry
+func Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	return DoWithRetry(client, req, *retryConfig.Load())
+}
+
+// DoWithRetry 发送请求，遇到429、502、503、504或网络错误时按指数退避（带随机抖动）重试，
+// 并优先使用响应的Retry-After头作为等待时间。
+// 只有GET、HEAD、OPTIONS请求会重试，POST等非幂等请求只发送一次，避免重复发布。
+// 重试次数用完后返回最后一次的响应或错误。
+func DoWithRetry(client *http.Client, req *http.Request, cfg RetryConfig) (*http.Response, error) {
+	if !isIdempotent(req.Method) {
+		return client.Do(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= cfg.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := backoff(cfg, attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				// 需要等待太久时直接返回，由调用方处理限流
+				if retryAfter > cfg.MaxDelay {
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+			// 读完并关闭响应体以便复用连接
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isIdempotent 判断请求方法是否可以安全重试
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableStatus 判断状态码是否为临时错误
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff 计算第attempt次重试前的等待时间，在指数退避值的一半到全部之间随机取值
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	delay := cfg.BaseDelay << attempt
+	if delay <= 0 || delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// parseRetryAfter 解析Retry-After头，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
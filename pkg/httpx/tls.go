@@ -0,0 +1,49 @@
+package httpx
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// tlsVersions 支持配置的最低TLS版本
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// minTLSVersion ConfigureTLS设置的最低TLS版本，供不基于默认Transport的客户端使用
+var minTLSVersion atomic.Uint32
+
+func init() {
+	minTLSVersion.Store(tls.VersionTLS12)
+}
+
+// MinTLSVersion 返回配置的最低TLS版本，未调用ConfigureTLS时为TLS 1.2
+func MinTLSVersion() uint16 {
+	return uint16(minTLSVersion.Load())
+}
+
+// ConfigureTLS 设置http.DefaultTransport的最低TLS版本（"1.2"或"1.3"）。
+// OAuth客户端、媒体下载、回调和连接预热都基于默认Transport；自建Transport的客户端通过MinTLSVersion读取同一设置。
+// 需要在发出任何请求之前调用。
+func ConfigureTLS(minVersion string) error {
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return fmt.Errorf("unsupported minimum TLS version %q, expected 1.2 or 1.3", minVersion)
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("default transport is %T, not *http.Transport", http.DefaultTransport)
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.MinVersion = version
+	minTLSVersion.Store(uint32(version))
+
+	return nil
+}
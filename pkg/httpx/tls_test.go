@@ -0,0 +1,123 @@
+package httpx
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// defaultTransport returns http.DefaultTransport and restores its TLS config and the configured
+// minimum version when the test ends, since ConfigureTLS changes both globally
+func defaultTransport(t *testing.T) *http.Transport {
+	t.Helper()
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		t.Fatalf("default transport is %T, not *http.Transport", http.DefaultTransport)
+	}
+
+	tlsConfig := transport.TLSClientConfig
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+	}
+	version := minTLSVersion.Load()
+	t.Cleanup(func() {
+		transport.TLSClientConfig = tlsConfig
+		minTLSVersion.Store(version)
+	})
+
+	return transport
+}
+
+func TestMinTLSVersionDefault(t *testing.T) {
+	transport := defaultTransport(t)
+
+	if got := MinTLSVersion(); got != tls.VersionTLS12 {
+		t.Fatalf("MinTLSVersion() = %x before ConfigureTLS, want TLS 1.2", got)
+	}
+
+	if err := ConfigureTLS("1.2"); err != nil {
+		t.Fatalf("ConfigureTLS(1.2): %v", err)
+	}
+	if got := transport.TLSClientConfig.MinVersion; got != tls.VersionTLS12 {
+		t.Fatalf("default transport MinVersion = %x, want TLS 1.2", got)
+	}
+}
+
+func TestConfigureTLS13(t *testing.T) {
+	transport := defaultTransport(t)
+
+	if err := ConfigureTLS("1.3"); err != nil {
+		t.Fatalf("ConfigureTLS(1.3): %v", err)
+	}
+	if got := transport.TLSClientConfig.MinVersion; got != tls.VersionTLS13 {
+		t.Fatalf("default transport MinVersion = %x, want TLS 1.3", got)
+	}
+	if got := MinTLSVersion(); got != tls.VersionTLS13 {
+		t.Fatalf("MinTLSVersion() = %x, want TLS 1.3", got)
+	}
+}
+
+// TestConfigureTLSMediaDownloadClient checks the setting end to end with a client without its own
+// transport, as media downloads and other plain clients use, against a server capped at TLS 1.2
+func TestConfigureTLSMediaDownloadClient(t *testing.T) {
+	transport := defaultTransport(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	// The rejected handshake is expected, keep it out of the test output
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	client := &http.Client{}
+	get := func() error {
+		// Trust the test server's certificate and drop connections from the previous attempt
+		transport.TLSClientConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+		transport.CloseIdleConnections()
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	if err := ConfigureTLS("1.2"); err != nil {
+		t.Fatalf("ConfigureTLS(1.2): %v", err)
+	}
+	if err := get(); err != nil {
+		t.Fatalf("TLS 1.2 server rejected with minimum TLS 1.2: %v", err)
+	}
+
+	if err := ConfigureTLS("1.3"); err != nil {
+		t.Fatalf("ConfigureTLS(1.3): %v", err)
+	}
+	if err := get(); err == nil {
+		t.Fatal("TLS 1.2 server accepted with minimum TLS 1.3")
+	}
+}
+
+func TestConfigureTLSInvalid(t *testing.T) {
+	transport := defaultTransport(t)
+
+	if err := ConfigureTLS("1.3"); err != nil {
+		t.Fatalf("ConfigureTLS(1.3): %v", err)
+	}
+
+	for _, version := range []string{"1.1", "1.0", "abc", ""} {
+		if err := ConfigureTLS(version); err == nil {
+			t.Errorf("ConfigureTLS(%q) succeeded, want an error", version)
+		}
+	}
+
+	if got := transport.TLSClientConfig.MinVersion; got != tls.VersionTLS13 {
+		t.Fatalf("default transport MinVersion = %x after invalid values, want TLS 1.3 unchanged", got)
+	}
+	if got := MinTLSVersion(); got != tls.VersionTLS13 {
+		t.Fatalf("MinTLSVersion() = %x after invalid values, want TLS 1.3 unchanged", got)
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 
@@ -13,13 +14,31 @@ type Logger struct {
 	*slog.Logger
 }
 
+// Config holds the logger output settings
+type Config struct {
+	Level  string // debug, info, warn or error, info when empty or unknown
+	Format string // text for human-readable output, JSON otherwise
+}
+
 // NewLogger creates a new logger instance
-func NewLogger() *Logger {
+func NewLogger(cfg Config) *Logger {
+	level := slog.LevelInfo
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			level = slog.LevelInfo
+		}
+	}
+
 	opts := &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+		Level: level,
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
 	logger := slog.New(handler)
 
 	return &Logger{Logger: logger}
@@ -51,3 +70,22 @@ func (l *Logger) Warn(ctx context.Context, message string, args ...interface{})
 	}
 	l.WarnContext(ctx, message, args...)
 }
+
+// Debug logs a debug message with context, automatically extracting request ID. Debug messages
+// are only written when the logger's level is debug
+func (l *Logger) Debug(ctx context.Context, message string, args ...interface{}) {
+	// Extract request ID from context if available
+	if requestID, ok := ctxutil.GetRequestID(ctx); ok {
+		args = append([]interface{}{"request_id", requestID}, args...)
+	}
+	l.DebugContext(ctx, message, args...)
+}
+
+// Redact hides a secret such as a token in log output, keeping only its length to tell
+// empty and truncated values apart
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return fmt.Sprintf("[REDACTED len=%d]", len(secret))
+}
@@ -0,0 +1,67 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrEmpty 下载到的媒体为空
+var ErrEmpty = errors.New("downloaded media is empty")
+
+// TooLargeError 媒体超过大小上限，Size为-1时表示未读完、不知道完整大小
+type TooLargeError struct {
+	Size  int64
+	Limit int64
+}
+
+// Error 实现error接口
+func (e *TooLargeError) Error() string {
+	if e.Size < 0 {
+		return fmt.Sprintf("media is larger than the %d byte limit", e.Limit)
+	}
+	return fmt.Sprintf("media is %d bytes, larger than the %d byte limit", e.Size, e.Limit)
+}
+
+// Download 下载mediaURL指向的媒体，返回内容和检测出的MIME类型（见DetectType）。
+// 超过maxSize的媒体返回*TooLargeError：服务器返回Content-Length时在读取前判断，
+// 否则多读一个字节后判断，因此不会读入超过maxSize+1字节的内容。
+func Download(ctx context.Context, client *http.Client, mediaURL string, maxSize int64) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("failed to download media: status=%d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxSize {
+		return nil, "", &TooLargeError{Size: resp.ContentLength, Limit: maxSize}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read media data: %w", err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, "", &TooLargeError{Size: -1, Limit: maxSize}
+	}
+
+	if len(data) == 0 {
+		return nil, "", ErrEmpty
+	}
+
+	return data, DetectType(data, resp.Header.Get("Content-Type")), nil
+}
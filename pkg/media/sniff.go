@@ -0,0 +1,140 @@
+package media
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// sniffLen 检测类型时查看的最大字节数
+const sniffLen = 512
+
+// extensionTypes 常见媒体扩展名对应的MIME类型
+var extensionTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".m4a":  "audio/mp4",
+	".wma":  "audio/x-ms-wma",
+	".mp4":  "video/mp4",
+	".avi":  "video/x-msvideo",
+	".mov":  "video/quicktime",
+	".wmv":  "video/x-ms-wmv",
+	".flv":  "video/x-flv",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+	".m4v":  "video/x-m4v",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// DetectType 返回媒体的实际MIME类型（不含参数）。优先按内容的魔数判断，
+// 魔数无法识别时使用服务器返回的contentType，contentType为空或为
+// application/octet-stream时使用http.DetectContentType的结果。
+func DetectType(data []byte, contentType string) string {
+	if len(data) > sniffLen {
+		data = data[:sniffLen]
+	}
+
+	if sniffed := sniff(data); sniffed != "" {
+		return sniffed
+	}
+
+	detected := stripParams(http.DetectContentType(data))
+	if isMedia(detected) {
+		return detected
+	}
+
+	if header := stripParams(contentType); header != "" && header != "application/octet-stream" {
+		return header
+	}
+	return detected
+}
+
+// TypeByURL 按URL路径的扩展名推测MIME类型，忽略查询参数，无法推测时返回空字符串。
+// 仅用于下载前的预检，实际类型以DetectType为准。
+func TypeByURL(mediaURL string) string {
+	p := mediaURL
+	if parsed, err := url.Parse(mediaURL); err == nil {
+		p = parsed.Path
+	}
+	return extensionTypes[strings.ToLower(path.Ext(p))]
+}
+
+// IsAudio 判断MIME类型是否为音频
+func IsAudio(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "audio/")
+}
+
+// IsVideo 判断MIME类型是否为视频
+func IsVideo(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "video/")
+}
+
+// sniff 识别http.DetectContentType不能识别或会误判的音视频格式，无法识别时返回空字符串
+func sniff(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return "audio/flac"
+	case bytes.HasPrefix(data, []byte("ID3")):
+		return "audio/mpeg"
+	case bytes.HasPrefix(data, []byte("FLV")):
+		return "video/x-flv"
+	case len(data) >= 12 && bytes.Equal(data[4:8], []byte("ftyp")):
+		return isoBaseMediaType(data[8:12])
+	case bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// Matroska和WebM共用EBML头，由DocType区分
+		if bytes.Contains(data, []byte("webm")) {
+			return "video/webm"
+		}
+		return "video/x-matroska"
+	case bytes.HasPrefix(data, []byte("OggS")):
+		if bytes.Contains(data, []byte("theora")) {
+			return "video/ogg"
+		}
+		return "audio/ogg"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xF6 == 0xF0:
+		// ADTS帧头：同步字之后layer为0
+		return "audio/aac"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+		// 没有ID3标签的MPEG音频帧
+		return "audio/mpeg"
+	}
+	return ""
+}
+
+// isoBaseMediaType 按ftyp的major brand区分MP4容器中的音频和视频
+func isoBaseMediaType(brand []byte) string {
+	switch string(brand) {
+	case "M4A ", "M4B ", "M4P ":
+		return "audio/mp4"
+	case "qt  ":
+		return "video/quicktime"
+	case "M4V ", "M4VH", "M4VP":
+		return "video/x-m4v"
+	}
+	if bytes.HasPrefix(brand, []byte("3g")) {
+		return "video/3gpp"
+	}
+	return "video/mp4"
+}
+
+// isMedia 判断MIME类型是否为图片、音频或视频
+func isMedia(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "image/") || IsAudio(mimeType) || IsVideo(mimeType)
+}
+
+// stripParams 去掉MIME类型中的参数，如 "video/mp4; codecs=avc1" 返回 "video/mp4"
+func stripParams(mimeType string) string {
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return strings.TrimSpace(strings.ToLower(mimeType))
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 结果标签取值
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	// HTTPRequestsTotal HTTP请求总数，按路由、方法和状态码区分
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "social_http_requests_total",
+		Help: "Total number of HTTP requests.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration HTTP请求耗时分布（秒）
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "social_http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestsInFlight 正在处理的HTTP请求数
+	HTTPRequestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "social_http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served.",
+	}, []string{"route"})
+
+	// SharesTotal 各平台分享结果计数
+	SharesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "social_shares_total",
+		Help: "Total number of shares by provider and result.",
+	}, []string{"provider", "result"})
+
+	// TokenRefreshesTotal 各平台token刷新结果计数
+	TokenRefreshesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "social_token_refreshes_total",
+		Help: "Total number of token refreshes by provider and result.",
+	}, []string{"provider", "result"})
+)
+
+// RecordShare 记录一次分享结果
+func RecordShare(provider string, err error) {
+	SharesTotal.WithLabelValues(provider, result(err)).Inc()
+}
+
+// RecordTokenRefresh 记录一次token刷新结果
+func RecordTokenRefresh(provider string, err error) {
+	TokenRefreshesTotal.WithLabelValues(provider, result(err)).Inc()
+}
+
+// result 将错误转换为结果标签
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
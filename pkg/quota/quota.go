@@ -0,0 +1,337 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Info 一次平台响应中的限流信息
+type Info struct {
+	Remaining int       // 当前窗口剩余的请求数，-1表示未知
+	ResetAt   time.Time // 窗口重置时间
+}
+
+// Exhausted 报告窗口是否已用尽
+func (i Info) Exhausted() bool {
+	return i.Remaining == 0
+}
+
+// Parse 解析响应头中的限流信息，支持X的x-rate-limit-*（重置时间为Unix时间戳）、
+// Reddit的x-ratelimit-*（重置时间为剩余秒数）、Facebook的x-business-use-case-usage
+// 以及Retry-After。429响应视为窗口已用尽；没有可用的重置时间时返回false
+func Parse(resp *http.Response, now time.Time) (Info, bool) {
+	info := Info{Remaining: -1}
+	header := resp.Header
+
+	switch {
+	case header.Get("x-rate-limit-reset") != "":
+		if reset, err := strconv.ParseInt(header.Get("x-rate-limit-reset"), 10, 64); err == nil {
+			info.ResetAt = time.Unix(reset, 0)
+		}
+		info.Remaining = parseRemaining(header.Get("x-rate-limit-remaining"))
+	case header.Get("x-ratelimit-reset") != "":
+		if seconds, err := strconv.ParseFloat(header.Get("x-ratelimit-reset"), 64); err == nil {
+			info.ResetAt = now.Add(time.Duration(seconds * float64(time.Second)))
+		}
+		info.Remaining = parseRemaining(header.Get("x-ratelimit-remaining"))
+	case header.Get("x-business-use-case-usage") != "":
+		if wait, ok := parseBusinessUseCase(header.Get("x-business-use-case-usage")); ok {
+			info.ResetAt = now.Add(wait)
+			info.Remaining = 0
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		info.Remaining = 0
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			info.ResetAt = now.Add(retryAfter)
+		}
+	}
+
+	if info.ResetAt.IsZero() {
+		return Info{}, false
+	}
+	return info, true
+}
+
+// parseRemaining 解析剩余请求数，Reddit返回带小数的值，无法解析时返回-1
+func parseRemaining(value string) int {
+	remaining, err := strconv.ParseFloat(value, 64)
+	if err != nil || remaining < 0 {
+		return -1
+	}
+	return int(remaining)
+}
+
+// parseBusinessUseCase 从Facebook的x-business-use-case-usage头中取出恢复访问前的最长等待时间，
+// 该头按业务ID列出各类调用的用量，只有被限流时estimated_time_to_regain_access（分钟）大于0
+func parseBusinessUseCase(value string) (time.Duration, bool) {
+	var usage map[string][]struct {
+		EstimatedTimeToRegainAccess int `json:"estimated_time_to_regain_access"`
+	}
+	if err := json.Unmarshal([]byte(value), &usage); err != nil {
+		return 0, false
+	}
+
+	var minutes int
+	for _, entries := range usage {
+		for _, entry := range entries {
+			minutes = max(minutes, entry.EstimatedTimeToRegainAccess)
+		}
+	}
+	if minutes == 0 {
+		return 0, false
+	}
+	return time.Duration(minutes) * time.Minute, true
+}
+
+// parseRetryAfter 解析Retry-After头，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// ErrExhausted 平台限流窗口已用尽，请求未发送
+var ErrExhausted = errors.New("platform rate limit exhausted")
+
+// ExhaustedError 说明哪个接口的限流窗口已用尽、何时重置
+type ExhaustedError struct {
+	Name    string
+	ResetAt time.Time
+}
+
+// Error 实现error接口
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("%s rate limit exhausted, retry after %s", e.Name, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Is 让errors.Is(err, ErrExhausted)匹配ExhaustedError
+func (e *ExhaustedError) Is(target error) bool {
+	return target == ErrExhausted
+}
+
+// AsExhaustedError 从错误链中取出ExhaustedError
+func AsExhaustedError(err error) (*ExhaustedError, bool) {
+	var exhaustedErr *ExhaustedError
+	if errors.As(err, &exhaustedErr) {
+		return exhaustedErr, true
+	}
+	return nil, false
+}
+
+// Store 保存已用尽的限流窗口，多实例部署时应使用共享的存储
+type Store interface {
+	// Block 记录key在resetAt之前不可用
+	Block(ctx context.Context, key string, resetAt time.Time) error
+	// BlockedUntil 返回key不可用的截止时间，未被限制时返回零值
+	BlockedUntil(ctx context.Context, key string) (time.Time, error)
+}
+
+// MemoryStore 进程内的Store
+type MemoryStore struct {
+	blocked sync.Map // key -> time.Time
+}
+
+// NewMemoryStore 创建进程内的Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Block 实现Store
+func (s *MemoryStore) Block(_ context.Context, key string, resetAt time.Time) error {
+	s.blocked.Store(key, resetAt)
+	return nil
+}
+
+// BlockedUntil 实现Store，已过期的记录顺便删除
+func (s *MemoryStore) BlockedUntil(_ context.Context, key string) (time.Time, error) {
+	value, ok := s.blocked.Load(key)
+	if !ok {
+		return time.Time{}, nil
+	}
+	resetAt := value.(time.Time)
+	if !time.Now().Before(resetAt) {
+		s.blocked.CompareAndDelete(key, value)
+		return time.Time{}, nil
+	}
+	return resetAt, nil
+}
+
+// redisKeyPrefix Redis中限流窗口的键前缀
+const redisKeyPrefix = "quota:"
+
+// RedisStore 保存在Redis中的Store，记录在窗口重置时自动过期
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 创建保存在Redis中的Store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Block 实现Store
+func (s *RedisStore) Block(ctx context.Context, key string, resetAt time.Time) error {
+	ttl := time.Until(resetAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, redisKeyPrefix+key, resetAt.Unix(), ttl).Err()
+}
+
+// BlockedUntil 实现Store
+func (s *RedisStore) BlockedUntil(ctx context.Context, key string) (time.Time, error) {
+	reset, err := s.client.Get(ctx, redisKeyPrefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(reset, 0), nil
+}
+
+// Config 平台限流配置
+type Config struct {
+	Enabled bool  // 是否在窗口用尽后、重置前直接拒绝请求
+	Store   Store // 保存已用尽的窗口，为nil时使用进程内存储
+}
+
+// DefaultConfig 默认平台限流配置
+var DefaultConfig = Config{Enabled: true}
+
+// config 当前平台限流配置
+var config atomic.Pointer[Config]
+
+func init() {
+	cfg := DefaultConfig
+	cfg.Store = NewMemoryStore()
+	config.Store(&cfg)
+}
+
+// Configure 设置平台限流配置，应在启动时调用
+func Configure(cfg Config) {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+	config.Store(&cfg)
+}
+
+// Recorder 记录一次操作中最近一次平台响应的限流信息，供出错时返回重置时间
+type Recorder struct {
+	mu   sync.Mutex
+	info Info
+	ok   bool
+}
+
+// recorderKey Recorder在context中的键
+type recorderKey struct{}
+
+// WithRecorder 返回带Recorder的context，经过Transport的请求会把限流信息记录在其中
+func WithRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, recorderKey{}, &Recorder{})
+}
+
+// record 记录一次响应的限流信息，ok为false表示该响应没有限流信息，清除之前的记录
+func (r *Recorder) record(info Info, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.info = info
+	r.ok = ok
+}
+
+// recorderFrom 返回ctx中的Recorder
+func recorderFrom(ctx context.Context) (*Recorder, bool) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	return r, ok
+}
+
+// ResetAt 返回ctx中记录的最近一次限流窗口的重置时间，没有记录时返回false
+func ResetAt(ctx context.Context) (time.Time, bool) {
+	r, ok := recorderFrom(ctx)
+	if !ok {
+		return time.Time{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.info.ResetAt, r.ok
+}
+
+// Transport 解析经过name（平台）的响应中的限流信息：记录到请求context的Recorder中，窗口用尽时
+// 保存到Store；启用后在窗口重置前直接返回*ExhaustedError，不发送请求。owner区分同一平台的
+// 不同token（如服务和用户），窗口还按请求方法和路径区分，其他用户和其他接口的请求不受影响
+func Transport(name, owner string, base http.RoundTripper) http.RoundTripper {
+	return &transport{name: name, owner: owner, base: base}
+}
+
+// transport 解析限流信息的RoundTripper
+type transport struct {
+	name  string
+	owner string
+	base  http.RoundTripper
+}
+
+// RoundTrip 实现http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cfg := config.Load()
+	key := t.name + ":" + t.owner + ":" + req.Method + " " + req.URL.Host + strings.TrimSuffix(req.URL.Path, "/")
+	recorder, hasRecorder := recorderFrom(req.Context())
+
+	if cfg.Enabled && cfg.Store != nil {
+		// 存储不可用时照常发送请求，由平台自己限流
+		if resetAt, err := cfg.Store.BlockedUntil(req.Context(), key); err == nil && time.Now().Before(resetAt) {
+			if hasRecorder {
+				recorder.record(Info{Remaining: 0, ResetAt: resetAt}, true)
+			}
+			return nil, &ExhaustedError{Name: t.name, ResetAt: resetAt}
+		}
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	info, ok := Parse(resp, time.Now())
+	if hasRecorder {
+		recorder.record(info, ok)
+	}
+	if ok && cfg.Enabled && cfg.Store != nil && info.Exhausted() {
+		_ = cfg.Store.Block(req.Context(), key, info.ResetAt)
+	}
+	return resp, nil
+}
+
+// Unwrap 返回被包装的RoundTripper
+func (t *transport) Unwrap() http.RoundTripper {
+	return t.base
+}
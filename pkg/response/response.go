@@ -1,10 +1,14 @@
 package response
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"social/internal/types"
 	"social/pkg/errors"
+	"social/pkg/validator"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,6 +29,7 @@ func (r *ResponseHandler) Success(c *gin.Context, data interface{}) {
 		Status:    "ok",
 		Data:      data,
 		RequestID: requestID,
+		Warnings:  r.getWarnings(c),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -39,6 +44,7 @@ func (r *ResponseHandler) SuccessWithMessage(c *gin.Context, message string, dat
 		Message:   message,
 		Data:      data,
 		RequestID: requestID,
+		Warnings:  r.getWarnings(c),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -54,6 +60,12 @@ func (r *ResponseHandler) Error(c *gin.Context, appErr *errors.AppError) {
 		RequestID: requestID,
 	}
 
+	// 平台限流时告诉客户端何时可以重试
+	if !appErr.ResetAt.IsZero() {
+		response.ResetAt = appErr.ResetAt.Unix()
+		c.Header("Retry-After", strconv.Itoa(max(int(math.Ceil(time.Until(appErr.ResetAt).Seconds())), 0)))
+	}
+
 	c.JSON(appErr.Status, response)
 }
 
@@ -84,6 +96,23 @@ func (r *ResponseHandler) BadRequest(c *gin.Context, message string) {
 	})
 }
 
+// BindError 返回请求绑定失败的400错误，字段校验失败时在fields中列出各字段的错误信息，
+// JSON格式错误等其他情况只返回通用信息
+func (r *ResponseHandler) BindError(c *gin.Context, err error) {
+	fields := validator.GetValidationErrors(err)
+	if len(fields) == 0 {
+		r.BadRequest(c, "invalid request format")
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, types.ErrorResponse{
+		Error:     "request validation failed",
+		Code:      errors.ErrInvalidRequest.Code,
+		Fields:    fields,
+		RequestID: r.getRequestID(c),
+	})
+}
+
 // Unauthorized 返回401错误
 func (r *ResponseHandler) Unauthorized(c *gin.Context, message string) {
 	r.Error(c, &errors.AppError{
@@ -120,6 +149,15 @@ func (r *ResponseHandler) InternalServerError(c *gin.Context, message string) {
 	})
 }
 
+// UnprocessableEntity 返回422错误，用于格式正确但语义无效的请求
+func (r *ResponseHandler) UnprocessableEntity(c *gin.Context, message string) {
+	r.Error(c, &errors.AppError{
+		Code:    errors.ErrUnprocessableEntity.Code,
+		Message: message,
+		Status:  http.StatusUnprocessableEntity,
+	})
+}
+
 // ServiceUnavailable 返回503错误
 func (r *ResponseHandler) ServiceUnavailable(c *gin.Context, message string) {
 	r.Error(c, &errors.AppError{
@@ -129,6 +167,21 @@ func (r *ResponseHandler) ServiceUnavailable(c *gin.Context, message string) {
 	})
 }
 
+// ServiceUnavailableWithData 返回带数据的503响应，如就绪检查中各组件的状态
+func (r *ResponseHandler) ServiceUnavailableWithData(c *gin.Context, message string, data interface{}) {
+	requestID := r.getRequestID(c)
+
+	response := types.APIResponse{
+		Status:    "unavailable",
+		Message:   message,
+		Data:      data,
+		RequestID: requestID,
+		Warnings:  r.getWarnings(c),
+	}
+
+	c.JSON(http.StatusServiceUnavailable, response)
+}
+
 // Created 返回201创建成功响应
 func (r *ResponseHandler) Created(c *gin.Context, data interface{}) {
 	requestID := r.getRequestID(c)
@@ -137,6 +190,7 @@ func (r *ResponseHandler) Created(c *gin.Context, data interface{}) {
 		Status:    "created",
 		Data:      data,
 		RequestID: requestID,
+		Warnings:  r.getWarnings(c),
 	}
 
 	c.JSON(http.StatusCreated, response)
@@ -162,6 +216,22 @@ func (r *ResponseHandler) getRequestID(c *gin.Context) string {
 	return ""
 }
 
+// AddWarning 记录不影响结果的警告，之后的成功响应会在warnings中返回
+func (r *ResponseHandler) AddWarning(c *gin.Context, warnings ...string) {
+	if len(warnings) == 0 {
+		return
+	}
+	c.Set(warningsKey, append(r.getWarnings(c), warnings...))
+}
+
+// getWarnings 从上下文中获取已记录的警告
+func (r *ResponseHandler) getWarnings(c *gin.Context) []string {
+	return c.GetStringSlice(warningsKey)
+}
+
+// warningsKey 上下文中保存警告的键
+const warningsKey = "response_warnings"
+
 // 全局响应处理器实例
 var DefaultResponseHandler = NewResponseHandler()
 
@@ -177,6 +247,11 @@ func SuccessWithMessage(c *gin.Context, message string, data interface{}) {
 	DefaultResponseHandler.SuccessWithMessage(c, message, data)
 }
 
+// AddWarning 记录不影响结果的警告，之后的成功响应会在warnings中返回
+func AddWarning(c *gin.Context, warnings ...string) {
+	DefaultResponseHandler.AddWarning(c, warnings...)
+}
+
 // Error 返回错误响应
 func Error(c *gin.Context, appErr *errors.AppError) {
 	DefaultResponseHandler.Error(c, appErr)
@@ -192,6 +267,11 @@ func BadRequest(c *gin.Context, message string) {
 	DefaultResponseHandler.BadRequest(c, message)
 }
 
+// BindError 返回请求绑定失败的400错误
+func BindError(c *gin.Context, err error) {
+	DefaultResponseHandler.BindError(c, err)
+}
+
 // Unauthorized 返回401错误
 func Unauthorized(c *gin.Context, message string) {
 	DefaultResponseHandler.Unauthorized(c, message)
@@ -212,11 +292,21 @@ func InternalServerError(c *gin.Context, message string) {
 	DefaultResponseHandler.InternalServerError(c, message)
 }
 
+// UnprocessableEntity 返回422错误
+func UnprocessableEntity(c *gin.Context, message string) {
+	DefaultResponseHandler.UnprocessableEntity(c, message)
+}
+
 // ServiceUnavailable 返回503错误
 func ServiceUnavailable(c *gin.Context, message string) {
 	DefaultResponseHandler.ServiceUnavailable(c, message)
 }
 
+// ServiceUnavailableWithData 返回带数据的503响应
+func ServiceUnavailableWithData(c *gin.Context, message string, data interface{}) {
+	DefaultResponseHandler.ServiceUnavailableWithData(c, message, data)
+}
+
 // Created 返回201创建成功响应
 func Created(c *gin.Context, data interface{}) {
 	DefaultResponseHandler.Created(c, data)
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config 链路追踪配置
+type Config struct {
+	Endpoint    string // OTLP/HTTP exporter地址，如 http://otel-collector:4318，为空时不追踪
+	ServiceName string // 上报的服务名
+}
+
+// tracerName 本服务创建的span所属的tracer
+const tracerName = "social"
+
+// ShutdownFunc 导出尚未发送的span并关闭exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// enabled 是否已配置exporter，未启用时不创建任何span
+var enabled atomic.Bool
+
+// Setup 按配置设置全局TracerProvider，应在启动时调用，返回的函数在退出前调用以导出剩余的span。
+// 未配置Endpoint时保持no-op tracer，中间件和Transport直接跳过，没有额外开销
+func Setup(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	provider, shutdown, err := newOTLPProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+	return shutdown, nil
+}
+
+// Enabled 是否启用了链路追踪
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Start 开始一个span，未启用追踪时返回的span不记录任何内容
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End 结束span，err不为nil时将span标记为失败
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport 为每个出站请求创建子span，父span取自请求的context。
+// 不向请求注入trace头，避免把内部链路信息发送给第三方平台。未启用追踪时直接返回base
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if !Enabled() {
+		return base
+	}
+	return &transport{base: base}
+}
+
+// transport 为请求创建span的RoundTripper
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip 实现http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		End(span, err)
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
+
+// Unwrap 返回被包装的RoundTripper
+func (t *transport) Unwrap() http.RoundTripper {
+	return t.base
+}
@@ -1,10 +1,13 @@
 package validator
 
 import (
+	stderrors "errors"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -18,17 +21,20 @@ func NewValidator() *Validator {
 	v := validator.New()
 
 	// 注册字段名称函数
-	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
+	v.RegisterTagNameFunc(jsonFieldName)
 
 	return &Validator{validator: v}
 }
 
+// jsonFieldName 以json标签中的名称作为字段名，错误信息中的字段与请求体一致
+func jsonFieldName(fld reflect.StructField) string {
+	name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
 // Validate 验证结构体
 func (v *Validator) Validate(i interface{}) error {
 	return v.validator.Struct(i)
@@ -43,9 +49,10 @@ func (v *Validator) ValidateVar(field interface{}, tag string) error {
 func (v *Validator) GetValidationErrors(err error) map[string]string {
 	errors := make(map[string]string)
 
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+	var validationErrors validator.ValidationErrors
+	if stderrors.As(err, &validationErrors) {
 		for _, e := range validationErrors {
-			field := e.Field()
+			field := fieldPath(e.Namespace())
 			tag := e.Tag()
 			param := e.Param()
 
@@ -58,6 +65,15 @@ func (v *Validator) GetValidationErrors(err error) map[string]string {
 	return errors
 }
 
+// fieldPath 去掉命名空间开头的结构体名，如 "BatchShareRequest.platforms[0].provider"
+// 返回 "platforms[0].provider"，嵌套字段的错误也能定位到请求体中的位置
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
 // getErrorMessage 生成用户友好的错误消息
 func getErrorMessage(field, tag, param string) string {
 	switch tag {
@@ -75,6 +91,10 @@ func getErrorMessage(field, tag, param string) string {
 		return fmt.Sprintf("%s must be a valid URL", field)
 	case "oneof":
 		return fmt.Sprintf("%s must be one of: %s", field, param)
+	case "provider":
+		return fmt.Sprintf("%s must be a supported platform", field)
+	case "media_url":
+		return fmt.Sprintf("%s must be a valid URL", field)
 	case "alphanum":
 		return fmt.Sprintf("%s must contain only alphanumeric characters", field)
 	case "alpha":
@@ -94,6 +114,48 @@ func getErrorMessage(field, tag, param string) string {
 	}
 }
 
+// RegisterProvider 注册provider标签，由resolve判断平台名称是否有效并返回规范名称，
+// 新注册的平台无需修改结构体标签即可通过校验
+func (v *Validator) RegisterProvider(resolve func(string) (string, bool)) error {
+	return v.validator.RegisterValidation("provider", providerValidation(resolve))
+}
+
+// providerValidation 创建校验平台名称的验证函数，字段可写时将别名（如twitter）
+// 替换为规范名称（如x），后续按平台名称查找token和配置时无需再处理别名
+func providerValidation(resolve func(string) (string, bool)) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		field := fl.Field()
+		canonical, ok := resolve(field.String())
+		if !ok {
+			return false
+		}
+		if field.CanSet() {
+			field.SetString(canonical)
+		}
+		return true
+	}
+}
+
+// RegisterMediaURL 注册media_url标签：接受绝对URL，allowRelative返回true时
+// 也接受 /uploads/a.jpg 和 //cdn/a.jpg 这样的相对地址，由调用方再解析为绝对URL
+func (v *Validator) RegisterMediaURL(allowRelative func() bool) error {
+	return v.validator.RegisterValidation("media_url", mediaURLValidation(allowRelative))
+}
+
+// mediaURLValidation 创建校验媒体地址的验证函数
+func mediaURLValidation(allowRelative func() bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		parsed, err := url.Parse(fl.Field().String())
+		if err != nil {
+			return false
+		}
+		if parsed.Scheme != "" {
+			return parsed.Host != "" || parsed.Opaque != ""
+		}
+		return strings.HasPrefix(parsed.String(), "/") && allowRelative()
+	}
+}
+
 // 全局验证器实例
 var DefaultValidator = NewValidator()
 
@@ -113,3 +175,40 @@ func ValidateVar(field interface{}, tag string) error {
 func GetValidationErrors(err error) map[string]string {
 	return DefaultValidator.GetValidationErrors(err)
 }
+
+// UseJSONFieldNames 让gin的请求绑定验证器也以json标签作为字段名，
+// 绑定失败时GetValidationErrors返回的字段与请求体一致
+func UseJSONFieldNames() error {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unsupported binding validator engine %T", binding.Validator.Engine())
+	}
+	engine.RegisterTagNameFunc(jsonFieldName)
+	return nil
+}
+
+// RegisterProvider 在全局验证器和gin的请求绑定验证器上注册provider标签
+func RegisterProvider(resolve func(string) (string, bool)) error {
+	if err := DefaultValidator.RegisterProvider(resolve); err != nil {
+		return err
+	}
+
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unsupported binding validator engine %T", binding.Validator.Engine())
+	}
+	return engine.RegisterValidation("provider", providerValidation(resolve))
+}
+
+// RegisterMediaURL 在全局验证器和gin的请求绑定验证器上注册media_url标签
+func RegisterMediaURL(allowRelative func() bool) error {
+	if err := DefaultValidator.RegisterMediaURL(allowRelative); err != nil {
+		return err
+	}
+
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unsupported binding validator engine %T", binding.Validator.Engine())
+	}
+	return engine.RegisterValidation("media_url", mediaURLValidation(allowRelative))
+}